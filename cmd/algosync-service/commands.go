@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"test-task/infra"
+	"test-task/internal/manager"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `usage: algosync-service [command]
+
+Without a command the API server and the algorithm synchronization are started.
+
+Commands:
+  manifests <client-id>   print the pod manifests the deployer would apply for the client
+`
+
+// runCommand executes a CLI subcommand and returns the process exit code.
+func runCommand(i infra.Infra, args []string) int {
+	switch args[0] {
+	case "manifests":
+		return manifestsCommand(i, args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+// manifestsCommand renders the pod manifests of a client to stdout without applying them.
+func manifestsCommand(i infra.Infra, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	clientID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid client id %q\n", args[0])
+		return 2
+	}
+
+	// Keep stdout clean for the rendered YAML
+	i.GetLogger().Logger.SetLevel(logrus.ErrorLevel)
+
+	manifests, err := manager.NewServiceManager(i).ClientService().Manifests(context.Background(), clientID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render manifests: %v\n", err)
+		return 1
+	}
+
+	os.Stdout.Write(manifests)
+	return 0
+}
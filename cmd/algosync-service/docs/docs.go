@@ -15,6 +15,190 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/admin/clients/deleted": {
+            "get": {
+                "description": "DeletedClients returns a page of the deleted clients that have not been purged yet, with their deletion time.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List deleted clients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client name prefix",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client image",
+                        "name": "image",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum priority",
+                        "name": "priority_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum priority",
+                        "name": "priority_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Enabled algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Need restart flag",
+                        "name": "need_restart",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key (id, client_name, version, priority), id by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order (asc, desc), asc by default",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default and at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of deleted clients",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientPage"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/algorithms": {
+            "get": {
+                "description": "AlgorithmStatuses returns the algorithm status of every client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List algorithm statuses",
+                "responses": {
+                    "200": {
+                        "description": "Algorithm statuses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlgorithmStatus"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/algorithms/{algorithm}/schema": {
+            "get": {
+                "description": "ParameterSchema returns the JSON schema the parameters document of the algorithm type is validated against.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get algorithm parameters schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JSON schema",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/calendars": {
+            "get": {
+                "description": "Calendars returns the trading calendars run windows can refer to, keyed by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List trading calendars",
+                "responses": {
+                    "200": {
+                        "description": "Trading calendars",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
         "/api/client/add": {
             "post": {
                 "description": "AddClient creates a new client with the provided data.",
@@ -27,32 +211,60 @@ const docTemplate = `{
                 "summary": "Add new client to the database",
                 "parameters": [
                     {
-                        "description": "Client object that needs to be added",
+                        "type": "string",
+                        "description": "Key identifying retries of the same request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Client that needs to be added",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Client"
+                            "$ref": "#/definitions/models.ClientCreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully created client",
+                    "201": {
+                        "description": "Created client",
                         "schema": {
                             "$ref": "#/definitions/models.Client"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the client"
+                            },
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "Set to true if the response is replayed for a retry"
+                            }
                         }
                     },
                     "400": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
-                    "501": {
+                    "500": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
@@ -77,13 +289,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated algorithm status data",
+                        "description": "Algorithms to enable or disable",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.AlgorithmStatusPatch"
                         }
                     }
                 ],
@@ -97,19 +308,135 @@ const docTemplate = `{
                     "400": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/by-name": {
+            "get": {
+                "description": "ClientByName returns the client with the specified name, compared regardless of case.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Find a client by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client",
+                        "schema": {
+                            "$ref": "#/definitions/models.Client"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the client"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
-                    "501": {
+                    "500": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
         "/api/client/{id}": {
+            "get": {
+                "description": "GetClient returns the client with the specified ID together with its algorithm status.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get a client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client with algorithm status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientDetails"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the client"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
             "delete": {
                 "description": "DeleteClient deletes the client with the specified ID.",
                 "consumes": [
@@ -126,6 +453,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected client revision",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -138,19 +471,37 @@ const docTemplate = `{
                     "400": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
-                    "501": {
+                    "500": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             },
             "patch": {
-                "description": "UpdateClient updates the specified client with new data.",
+                "description": "UpdateClient changes the fields set in the body of the specified client and returns the updated client.",
                 "consumes": [
                     "application/json"
                 ],
@@ -167,82 +518,2663 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated client data",
+                        "type": "string",
+                        "description": "ETag of the expected client revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Client fields to change",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ClientPatch"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Updated client",
                         "schema": {
                             "$ref": "#/definitions/models.Client"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the client"
+                            }
                         }
                     },
                     "400": {
                         "description": "error",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Client": {
-            "type": "object",
-            "properties": {
-                "client_name": {
-                    "type": "string"
-                },
-                "cpu": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "image": {
-                    "type": "string"
-                },
-                "memory": {
-                    "type": "string"
-                },
-                "need_restart": {
-                    "type": "boolean"
-                },
+        },
+        "/api/client/{id}/algorithm/schedule": {
+            "get": {
+                "description": "ScheduledChanges returns the scheduled changes of the specified client ordered by the time they apply at.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List scheduled algorithm changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduled changes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "ScheduleChange enables or disables an algorithm of the specified client at apply_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Schedule algorithm change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Algorithm, flag, time to apply at, actor and reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduledChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Scheduled change",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/algorithm/schedule/{change}": {
+            "delete": {
+                "description": "CancelScheduledChange cancels a pending change of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Cancel scheduled algorithm change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Scheduled change ID",
+                        "name": "change",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully cancelled change",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/audit": {
+            "get": {
+                "description": "AuditEntries returns the latest audit entries of the specified client, newest first, such as applied and failed scheduled changes.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Client audit history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit entries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/manifests": {
+            "get": {
+                "description": "Manifests renders the Kubernetes YAML the deployer would apply for every enabled algorithm of the specified client, without applying it.",
+                "produces": [
+                    "application/yaml"
+                ],
+                "summary": "Render pod manifests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Multi-document YAML with one pod manifest per enabled algorithm",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/migrate": {
+            "post": {
+                "description": "MigrateClient moves the algorithm pods of the specified client to another cluster.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Migrate client to another cluster",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target cluster, null for the default cluster",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientMigration"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Algorithm state after migration",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlgorithmState"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/parameters/{algorithm}": {
+            "get": {
+                "description": "Parameters returns the strategy parameters document of an algorithm of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get algorithm parameters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parameters document",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlgorithmParameters"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "SetParameters validates the strategy parameters document against the schema of the algorithm type and stores it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set algorithm parameters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Recreate the pod after updating its ConfigMap",
+                        "name": "restart",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Parameters document",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored parameters and rollout result",
+                        "schema": {
+                            "$ref": "#/definitions/models.ParametersUpdate"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/pause": {
+            "get": {
+                "description": "ClientPause returns the active pause of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get client pause",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active pause",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientPause"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "PauseClient stops the synchronization from touching the pods of the specified client, for example while they are debugged manually.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Pause client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason, actor and optional expiry of the pause",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active pause",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientPause"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "ResumeClient ends the pause of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Resume client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client resumed",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/restore": {
+            "post": {
+                "description": "RestoreClient restores the deleted client with the specified ID and returns it.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Restore a deleted client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID to restore",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored client",
+                        "schema": {
+                            "$ref": "#/definitions/models.Client"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the client"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/scheduling": {
+            "get": {
+                "description": "Scheduling returns the effective placement constraints of every algorithm type for the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get algorithm scheduling",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduling per algorithm type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.Scheduling"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/scheduling/{algorithm}": {
+            "put": {
+                "description": "SetSchedulingOverride overrides the placement constraints of an algorithm type for the specified client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Override algorithm scheduling",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Placement constraints",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Scheduling"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully saved scheduling override",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteSchedulingOverride restores the default placement constraints of an algorithm type for the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove algorithm scheduling override",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully removed scheduling override",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/secrets": {
+            "get": {
+                "description": "Secrets returns the names and injection modes of the secrets of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List client secrets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client secrets without values",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ClientSecret"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/secrets/{name}": {
+            "put": {
+                "description": "SetSecret encrypts and stores a secret of the specified client, such as an exchange API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set a client secret",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Secret name, used as the environment variable name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Secret value and injection mode",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SecretValue"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored secret without value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteSecret deletes a secret of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a client secret",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Secret name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/state": {
+            "get": {
+                "description": "AlgorithmStates returns the observed state of the algorithm pods of the specified client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get observed algorithm state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Observed algorithm state",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlgorithmState"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/windows": {
+            "get": {
+                "description": "AlgorithmWindows returns the run windows of the specified client keyed by algorithm type.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get algorithm run windows",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Run window per algorithm type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.AlgorithmWindow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/client/{id}/windows/{algorithm}": {
+            "put": {
+                "description": "SetAlgorithmWindow restricts when an enabled algorithm of the specified client runs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Set algorithm run window",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cron expression and time zone, or calendar name, with start and stop margins",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AlgorithmWindow"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored run window",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlgorithmWindow"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "DeleteAlgorithmWindow removes the run window of an algorithm of the specified client, so it runs whenever it is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove algorithm run window",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully removed run window",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/clients": {
+            "get": {
+                "description": "Clients returns a page of the clients matching the filters.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List clients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client name prefix",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client image",
+                        "name": "image",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum priority",
+                        "name": "priority_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum priority",
+                        "name": "priority_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Enabled algorithm type (vwap, twap, hft)",
+                        "name": "algorithm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Need restart flag",
+                        "name": "need_restart",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key (id, client_name, version, priority), id by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order (asc, desc), asc by default",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default and at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to return",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of clients",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClientPage"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/clusters": {
+            "get": {
+                "description": "Clusters returns all registered clusters.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List deployment clusters",
+                "responses": {
+                    "200": {
+                        "description": "Registered clusters",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Cluster"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "AddCluster registers a Kubernetes cluster algorithm pods can be deployed to.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Register a deployment cluster",
+                "parameters": [
+                    {
+                        "description": "Cluster name, kubeconfig context, API endpoint and credentials reference",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Cluster"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully registered cluster",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cluster"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/clusters/health": {
+            "get": {
+                "description": "Health checks the API server of the default cluster and of every registered cluster.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Check cluster health",
+                "responses": {
+                    "200": {
+                        "description": "Health of every cluster",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ClusterHealth"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/clusters/{id}": {
+            "delete": {
+                "description": "DeleteCluster removes a cluster.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete a deployment cluster",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cluster ID to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted cluster",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/killswitch": {
+            "get": {
+                "description": "KillSwitches returns the engaged kill switches with the actor who engaged them.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "List engaged kill switches",
+                "responses": {
+                    "200": {
+                        "description": "Engaged kill switches",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.KillSwitch"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "EngageKillSwitch stops the selected algorithms, or every algorithm if none are selected, across all clients.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Engage kill switch",
+                "parameters": [
+                    {
+                        "description": "Algorithm filter, actor and reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.KillSwitchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Engaged kill switches and deleted pods",
+                        "schema": {
+                            "$ref": "#/definitions/models.KillSwitchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/killswitch/release": {
+            "post": {
+                "description": "ReleaseKillSwitch releases the kill switches of the selected algorithms, or of every algorithm if none are selected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Release kill switch",
+                "parameters": [
+                    {
+                        "description": "Algorithm filter and actor",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.KillSwitchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Released kill switches",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.KillSwitch"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/metrics": {
+            "get": {
+                "description": "SyncMetrics returns the queue depth, running deployer calls and per-client latency of the synchronization.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get synchronization metrics",
+                "responses": {
+                    "200": {
+                        "description": "Synchronization metrics",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncMetrics"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/plan": {
+            "post": {
+                "description": "PlanSync computes and stores the actions the synchronization would take for every client.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Plan synchronization",
+                "responses": {
+                    "201": {
+                        "description": "Stored plan",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncPlan"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/plan/{id}": {
+            "get": {
+                "description": "SyncPlan returns a stored plan with its status and, once applied, the outcome of its actions.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get synchronization plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/plan/{id}/apply": {
+            "post": {
+                "description": "ApplySyncPlan executes exactly the actions of a pending plan.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Apply synchronization plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Applied plan",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync/runs/last": {
+            "get": {
+                "description": "LastSyncRun returns the outcome of every client in the last finished synchronization cycle.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get last synchronization run",
+                "responses": {
+                    "200": {
+                        "description": "Last synchronization run",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncRun"
+                        }
+                    },
+                    "404": {
+                        "description": "error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AlgorithmParameters": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "parameters": {
+                    "type": "object"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlgorithmState": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "failed_at": {
+                    "description": "FailedAt is set when the algorithm was disabled after crash-looping.\nThe pod is not recreated until an operator re-enables the algorithm.",
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "pod_name": {
+                    "type": "string"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlgorithmStatus": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "hft": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "twap": {
+                    "type": "boolean"
+                },
+                "vwap": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.AlgorithmStatusPatch": {
+            "type": "object",
+            "properties": {
+                "hft": {
+                    "type": "boolean"
+                },
+                "twap": {
+                    "type": "boolean"
+                },
+                "vwap": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.AlgorithmWindow": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "calendar": {
+                    "description": "Calendar is the name of a trading calendar the algorithm runs during the sessions of.\nCalendars are loaded from a local file.",
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "cron": {
+                    "description": "Cron is a five-field cron expression matching the minutes during which\nthe algorithm runs, e.g. \"30-59 9 * * MON-FRI\" and \"* 10-15 * * MON-FRI\".",
+                    "type": "string"
+                },
+                "start_before": {
+                    "description": "StartBefore is how many minutes before the window opens the pod is started.",
+                    "type": "integer"
+                },
+                "stop_after": {
+                    "description": "StopAfter is how many minutes after the window closes the pod is stopped.",
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA time zone Cron is evaluated in, UTC if empty.",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "algorithm": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Details holds action specific data as a JSON object.",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Client": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "cluster_id": {
+                    "type": "integer"
+                },
+                "cpu": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "need_restart": {
+                    "type": "boolean"
+                },
+                "priority": {
+                    "type": "number"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "spawned_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClientCreateRequest": {
+            "type": "object",
+            "required": [
+                "client_name",
+                "image"
+            ],
+            "properties": {
+                "client_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cluster_id": {
+                    "description": "ClusterID is the cluster the client is deployed to, the default cluster if null.",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "cpu": {
+                    "description": "CPU and Memory are Kubernetes resource quantities; empty means no limit.",
+                    "type": "string"
+                },
+                "image": {
+                    "description": "Image is a container image reference such as \"registry.example.com/algo/twap:1.2\".",
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "need_restart": {
+                    "type": "boolean"
+                },
+                "priority": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.ClientDetails": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "$ref": "#/definitions/models.AlgorithmStatus"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "cluster_id": {
+                    "type": "integer"
+                },
+                "cpu": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "need_restart": {
+                    "type": "boolean"
+                },
                 "priority": {
                     "type": "number"
                 },
-                "spawned_at": {
+                "revision": {
+                    "type": "integer"
+                },
+                "spawned_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClientMigration": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "description": "ClusterID is the target cluster, or null for the default cluster.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClientPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Client"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor requests the next page, empty on the last page.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of clients matching the filter across all pages.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClientPatch": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cpu": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "need_restart": {
+                    "type": "boolean"
+                },
+                "priority": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.ClientPause": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the pause ends automatically, nil pauses until the client is resumed.",
+                    "type": "string"
+                },
+                "paused_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClientSecret": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "injection": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClientSyncResult": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Message explains why the client was skipped.",
+                    "type": "string"
+                },
+                "pause": {
+                    "$ref": "#/definitions/models.ClientPause"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cluster": {
+            "type": "object",
+            "properties": {
+                "api_endpoint": {
+                    "description": "APIEndpoint is the address of the cluster API server.",
+                    "type": "string"
+                },
+                "context": {
+                    "description": "Context is the kubeconfig context used to reach the cluster.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credentials_ref": {
+                    "description": "CredentialsRef is the path to the kubeconfig file holding the cluster credentials.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClusterHealth": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "cluster_id": {
+                    "description": "ClusterID is nil for the default cluster.",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KillSwitch": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "algorithm": {
+                    "type": "string"
+                },
+                "engaged_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "released_by": {
+                    "description": "ReleasedBy and ReleasedAt are nil while the kill switch is engaged.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.KillSwitchRequest": {
+            "type": "object",
+            "required": [
+                "actor"
+            ],
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "algorithms": {
+                    "description": "Algorithms is the algorithm filter, empty means every algorithm type.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KillSwitchResult": {
+            "type": "object",
+            "properties": {
+                "deleted_pods": {
+                    "type": "integer"
+                },
+                "disabled_clients": {
+                    "description": "DisabledClients is the number of clients that had a matching algorithm enabled.",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Errors lists the pods that could not be deleted; they are retried by the synchronization.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kill_switches": {
+                    "description": "KillSwitches are the engaged kill switches of the requested algorithms.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KillSwitch"
+                    }
+                }
+            }
+        },
+        "models.LatencySummary": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "p95": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NodeSelectorRequirement": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ParametersUpdate": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
                     "type": "string"
                 },
+                "applied": {
+                    "description": "Applied is true if the ConfigMap of the running pod was updated in place.",
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "parameters": {
+                    "type": "object"
+                },
+                "restarted": {
+                    "description": "Restarted is true if the pod was recreated to pick up the change.",
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "models.PauseRequest": {
+            "type": "object",
+            "required": [
+                "actor",
+                "reason"
+            ],
+            "properties": {
+                "actor": {
+                    "type": "string"
                 },
-                "version": {
+                "expires_at": {
+                    "description": "ExpiresAt is an optional RFC 3339 time at which the pause ends.",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlanAction": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "algorithm": {
+                    "type": "string"
+                },
+                "client_id": {
                     "type": "integer"
+                },
+                "cluster": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error is set once the plan is applied if the action failed.",
+                    "type": "string"
+                },
+                "pod_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "models.Response": {
+        "models.Problem": {
             "type": "object",
             "properties": {
                 "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProblemField"
+                    }
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProblemField": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduledChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "algorithm": {
+                    "type": "string"
+                },
+                "applied_at": {
+                    "description": "AppliedAt is set once the change was applied or failed.",
+                    "type": "string"
+                },
+                "apply_at": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "description": "Error is the reason a failed change could not be applied.",
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduledChangeRequest": {
+            "type": "object",
+            "required": [
+                "actor",
+                "algorithm",
+                "apply_at",
+                "enabled"
+            ],
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "vwap",
+                        "twap",
+                        "hft"
+                    ]
+                },
+                "apply_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Scheduling": {
+            "type": "object",
+            "properties": {
+                "node_affinity": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NodeSelectorRequirement"
+                    }
+                },
+                "node_selector": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tolerations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Toleration"
+                    }
+                }
+            }
+        },
+        "models.SecretValue": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "injection": {
+                    "description": "Injection is either \"env\" or \"secret\", defaults to \"secret\".",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SuccessResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
                     "type": "string"
                 }
             }
+        },
+        "models.SyncMetrics": {
+            "type": "object",
+            "properties": {
+                "client_latency": {
+                    "description": "ClientLatency summarizes per-client reconciliation time in the latest completed cycle.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatencySummary"
+                        }
+                    ]
+                },
+                "clients": {
+                    "description": "Clients holds the latest reconciliation time per client ID.",
+                    "type": "object"
+                },
+                "cycles": {
+                    "description": "Cycles is the number of completed synchronization cycles.",
+                    "type": "integer"
+                },
+                "deployer_calls": {
+                    "description": "DeployerCalls is the number of kubectl calls running right now.",
+                    "type": "integer"
+                },
+                "in_flight": {
+                    "description": "InFlight is the number of clients being reconciled right now.",
+                    "type": "integer"
+                },
+                "last_cycle_duration": {
+                    "description": "LastCycleDuration is how long the latest completed cycle took.",
+                    "type": "integer"
+                },
+                "last_cycle_started_at": {
+                    "description": "LastCycleStartedAt is when the latest cycle started.",
+                    "type": "string"
+                },
+                "queue_depth": {
+                    "description": "QueueDepth is the number of clients waiting for a worker in the current cycle.",
+                    "type": "integer"
+                },
+                "workers": {
+                    "description": "Workers is the number of clients reconciled concurrently.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SyncPlan": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanAction"
+                    }
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SyncRun": {
+            "type": "object",
+            "properties": {
+                "clients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClientSyncResult"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "synced": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Toleration": {
+            "type": "object",
+            "properties": {
+                "effect": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "toleration_seconds": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -250,8 +3182,8 @@ const docTemplate = `{
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
-	Host:             "",
-	BasePath:         "",
+	Host:             "localhost:4000",
+	BasePath:         "/api",
 	Schemes:          []string{},
 	Title:            "AlgorithmSync service",
 	Description:      "сервис для синхронизации пользовательских алгоритмов",
@@ -3,9 +3,12 @@ package main
 import (
 	"net/http"
 	_ "net/http/pprof"
+	"os"
 	_ "test-task/cmd/algosync-service/docs"
 	"test-task/infra"
 	"test-task/internal/api"
+	// Embed the time zone database for run windows, the runtime image has none
+	_ "time/tzdata"
 )
 
 // @title AlgorithmSync service
@@ -21,6 +24,11 @@ func main() {
 	// Set project mod
 	i.SetMode()
 
+	// Run CLI subcommand if one is given
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(i, os.Args[1:]))
+	}
+
 	// Get custom logrus logger
 	log := i.GetLogger()
 
@@ -4,6 +4,7 @@ BASE_URL="http://localhost:4000"
 
 # Test Endpoint 1: Add Client (POST)
 echo "Testing Endpoint: Add Client"
+# Client names are unique, so only the first request creates a client and the rest measure the 409 conflict path
 hey -m POST -H 'Content-Type: application/json' -D '{"client_name": "Test Client", "version": 1, "image": "test_image", "cpu": "2x Intel Xeon", "memory": "16GB", "priority": 0.75, "need_restart": false}' -c 10 -z 200ms -q 100 -n 1000 ${BASE_URL}/api/client/add
 echo ""
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"test-task/infra/k8s"
+	"test-task/pkg/notify"
 	"test-task/pkg/util/logger"
 	"test-task/storage/postgres"
 
@@ -22,7 +23,8 @@ type Infra interface {
 	RedisClient() *redis.Client
 	PSQLClient() *postgres.PSQLClient
 	RunSQLMigrations()
-	KubernetesDeployer() k8s.KubernetesDeployer
+	DeployerFactory() k8s.DeployerFactory
+	Notifier() notify.Notifier
 }
 
 type infra struct {
@@ -40,9 +42,13 @@ var (
 
 // Config returns the Viper configuration instance.
 // It reads and initializes configuration from the specified configFile path.
+// The secret key can be overridden with the SECRET_KEY environment variable.
 func (i *infra) Config() *viper.Viper {
 	vprOnce.Do(func() {
 		viper.SetConfigFile(i.configFile)
+		if err := viper.BindEnv("secret.key", "SECRET_KEY"); err != nil {
+			logrus.Fatalf("[infra][Config][viper.BindEnv] %v", err)
+		}
 		if err := viper.ReadInConfig(); err != nil {
 			logrus.Fatalf("[infra][Config][viper.ReadInConfig] %v", err)
 		}
@@ -147,13 +153,41 @@ func (i *infra) PSQLClient() *postgres.PSQLClient {
 }
 
 // RunSQLMigrations runs SQL migrations on the configured PostgreSQL database.
-// It triggers SQL migrations using the initialized PostgreSQL client.
+// It triggers SQL migrations using the initialized PostgreSQL client and logs a failed
+// migration, such as the report of duplicate client names.
 func (i *infra) RunSQLMigrations() {
-	i.PSQLClient().SqlMigrate()
+	if err := i.PSQLClient().SqlMigrate(); err != nil {
+		logrus.Errorf("[infra][RunSQLMigrations][SqlMigrate] %v", err)
+	}
 }
 
-// KubernetesDeployer returns a new instance of KubernetesDeployer.
-// It initializes a Kubernetes deployer used for managing deployments.
-func (i *infra) KubernetesDeployer() k8s.KubernetesDeployer {
-	return k8s.NewKubernetesDeployer()
+var (
+	deployerFactoryOnce sync.Once
+	deployerFactory     k8s.DeployerFactory
+)
+
+// DeployerFactory returns the factory of Kubernetes deployers, one per target cluster.
+// Deployers render pod manifests from the templates directory configured in k8s.templates_dir.
+func (i *infra) DeployerFactory() k8s.DeployerFactory {
+	deployerFactoryOnce.Do(func() {
+		renderer, err := k8s.NewRenderer(i.Config().GetString("k8s.templates_dir"))
+		if err != nil {
+			logrus.Fatalf("[infra][DeployerFactory][k8s.NewRenderer] %v", err)
+		}
+
+		deployerFactory = k8s.NewDeployerFactory(renderer, i.Config().GetInt("k8s.max_concurrent_calls"))
+	})
+
+	return deployerFactory
+}
+
+// Notifier returns the notifier used to report events to operators.
+// Events are posted to the configured webhook, or only logged if no webhook is configured.
+func (i *infra) Notifier() notify.Notifier {
+	url := i.Config().GetString("notify.webhook_url")
+	if url == "" {
+		return notify.NewLogNotifier()
+	}
+
+	return notify.NewWebhookNotifier(url)
 }
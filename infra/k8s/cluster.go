@@ -0,0 +1,105 @@
+package k8s
+
+import "sync"
+
+// Cluster describes how kubectl reaches a Kubernetes cluster.
+// Empty fields fall back to the defaults of the current kubeconfig.
+type Cluster struct {
+	// Name identifies the cluster in logs and health reports.
+	Name string
+	// Context is the kubeconfig context to use.
+	Context string
+	// Server is the address of the Kubernetes API server.
+	Server string
+	// Kubeconfig is the path to the kubeconfig file holding the credentials.
+	Kubeconfig string
+}
+
+// flags returns the kubectl global flags selecting the cluster.
+func (c Cluster) flags() []string {
+	var flags []string
+	if c.Kubeconfig != "" {
+		flags = append(flags, "--kubeconfig="+c.Kubeconfig)
+	}
+	if c.Context != "" {
+		flags = append(flags, "--context="+c.Context)
+	}
+	if c.Server != "" {
+		flags = append(flags, "--server="+c.Server)
+	}
+	return flags
+}
+
+type DeployerFactory interface {
+	Deployer(cluster Cluster) KubernetesDeployer
+	Default() KubernetesDeployer
+	InFlightCalls() int
+}
+
+type deployerFactory struct {
+	renderer Renderer
+	limiter  *CallLimiter
+
+	mu        sync.Mutex
+	deployers map[Cluster]KubernetesDeployer
+}
+
+// NewDeployerFactory creates a factory that returns one deployer per cluster,
+// all rendering pod manifests with the given renderer. At most maxCalls kubectl
+// calls run at the same time across all clusters; zero means no limit.
+func NewDeployerFactory(renderer Renderer, maxCalls int) DeployerFactory {
+	return &deployerFactory{
+		renderer:  renderer,
+		limiter:   NewCallLimiter(maxCalls),
+		deployers: make(map[Cluster]KubernetesDeployer),
+	}
+}
+
+// Deployer returns the deployer for the given cluster, creating it on first use.
+func (f *deployerFactory) Deployer(cluster Cluster) KubernetesDeployer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	deployer, ok := f.deployers[cluster]
+	if !ok {
+		deployer = NewKubernetesDeployer(f.renderer, cluster, f.limiter)
+		f.deployers[cluster] = deployer
+	}
+
+	return deployer
+}
+
+// Default returns the deployer for the current kubectl context.
+func (f *deployerFactory) Default() KubernetesDeployer {
+	return f.Deployer(Cluster{})
+}
+
+// InFlightCalls returns the number of kubectl calls currently running across all clusters.
+func (f *deployerFactory) InFlightCalls() int {
+	return f.limiter.InFlight()
+}
+
+type staticDeployerFactory struct {
+	deployer KubernetesDeployer
+}
+
+// NewStaticDeployerFactory creates a factory that returns the same deployer for every cluster.
+// It is meant for single-cluster setups and tests.
+func NewStaticDeployerFactory(deployer KubernetesDeployer) DeployerFactory {
+	return &staticDeployerFactory{deployer: deployer}
+}
+
+// Deployer returns the static deployer.
+func (f *staticDeployerFactory) Deployer(Cluster) KubernetesDeployer {
+	return f.deployer
+}
+
+// Default returns the static deployer.
+func (f *staticDeployerFactory) Default() KubernetesDeployer {
+	return f.deployer
+}
+
+// InFlightCalls always returns zero, the static deployer is not tracked.
+func (f *staticDeployerFactory) InFlightCalls() int {
+	return 0
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterFlags(t *testing.T) {
+	assert.Empty(t, Cluster{Name: "default"}.flags())
+
+	cluster := Cluster{Name: "eu-west", Context: "eu-west-admin", Server: "https://10.0.0.1:6443", Kubeconfig: "/etc/algosync/eu-west.kubeconfig"}
+	assert.Equal(t, []string{
+		"--kubeconfig=/etc/algosync/eu-west.kubeconfig",
+		"--context=eu-west-admin",
+		"--server=https://10.0.0.1:6443",
+	}, cluster.flags())
+}
+
+func TestDeployerFactory(t *testing.T) {
+	factory := NewDeployerFactory(nil, 0)
+
+	eu := Cluster{Name: "eu-west", Context: "eu-west-admin"}
+	assert.Same(t, factory.Deployer(eu), factory.Deployer(eu))
+	assert.NotSame(t, factory.Default(), factory.Deployer(eu))
+}
+
+func TestCallLimiter(t *testing.T) {
+	limiter := NewCallLimiter(2)
+
+	release1 := limiter.acquire()
+	release2 := limiter.acquire()
+	assert.Equal(t, 2, limiter.InFlight())
+
+	acquired := make(chan struct{})
+	go func() {
+		defer limiter.acquire()()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("third call admitted while two are running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release1()
+	<-acquired
+	release2()
+
+	var unlimited *CallLimiter
+	unlimited.acquire()()
+	assert.Equal(t, 0, unlimited.InFlight())
+	assert.Nil(t, NewCallLimiter(0))
+}
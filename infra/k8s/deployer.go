@@ -3,30 +3,89 @@ package k8s
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
+)
+
+const (
+	// podPollInterval is the delay between pod status checks while waiting for readiness.
+	podPollInterval = 2 * time.Second
+	// healthTimeout limits how long a cluster health check may take.
+	healthTimeout = 5 * time.Second
 )
 
 type KubernetesDeployer interface {
-	CreatePod(name string, image string) error
+	CreatePod(spec PodSpec) error
+	ApplyConfigMap(spec PodSpec) error
+	RenderPod(spec PodSpec) ([]byte, error)
 	DeletePod(name string) error
 	GetPodList() ([]string, error)
+	PodStatus(name string) (*PodStatus, error)
+	WaitForPodReady(name string, timeout time.Duration) (*PodStatus, error)
+	Health() error
+}
+
+type kubernetesDeployer struct {
+	renderer Renderer
+	cluster  Cluster
+	limiter  *CallLimiter
+}
+
+// NewKubernetesDeployer creates a deployer that manages pods in the given cluster.
+// The zero Cluster targets the current kubectl context. kubectl calls are bounded
+// by the limiter, which may be shared between deployers; a nil limiter does not limit.
+func NewKubernetesDeployer(renderer Renderer, cluster Cluster, limiter *CallLimiter) KubernetesDeployer {
+	return &kubernetesDeployer{renderer: renderer, cluster: cluster, limiter: limiter}
 }
 
-type kubernetesDeployer struct{}
+// kubectl builds a kubectl command targeting the deployer's cluster.
+func (k *kubernetesDeployer) kubectl(args ...string) *exec.Cmd {
+	return exec.Command("kubectl", append(k.cluster.flags(), args...)...)
+}
 
-func NewKubernetesDeployer() KubernetesDeployer {
-	return &kubernetesDeployer{}
+// run runs the command once the limiter admits it.
+func (k *kubernetesDeployer) run(cmd *exec.Cmd) error {
+	defer k.limiter.acquire()()
+	return cmd.Run()
 }
 
-// CreatePod created pod by name and with the image of the client
-func (k *kubernetesDeployer) CreatePod(name string, image string) error {
-	cmd := exec.Command("kubectl", "run", name, "--image="+image)
+// output runs the command once the limiter admits it and returns its standard output.
+func (k *kubernetesDeployer) output(cmd *exec.Cmd) ([]byte, error) {
+	defer k.limiter.acquire()()
+	return cmd.Output()
+}
+
+// CreatePod creates a pod from the manifest rendered for the given spec,
+// including its labels, resources, environment and scheduling constraints.
+// If the spec has secret environment variables or parameters, the pod's Secret
+// and ConfigMap are applied first.
+func (k *kubernetesDeployer) CreatePod(spec PodSpec) error {
+	manifest, err := k.RenderPod(spec)
+	if err != nil {
+		return err
+	}
+
+	if len(spec.SecretEnv) > 0 {
+		if err := k.applySecret(spec); err != nil {
+			return err
+		}
+	}
+
+	if spec.Parameters != "" {
+		if err := k.ApplyConfigMap(spec); err != nil {
+			return err
+		}
+	}
+
+	cmd := k.kubectl("create", "-f", "-")
+	cmd.Stdin = bytes.NewReader(manifest)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := k.run(cmd); err != nil {
 		if strings.Contains(stderr.String(), "already exists") {
 			return nil
 		}
@@ -35,25 +94,76 @@ func (k *kubernetesDeployer) CreatePod(name string, image string) error {
 	return nil
 }
 
-// DeletePod deleted pod by name
+// applySecret creates or updates the Kubernetes Secret holding the secret environment of the pod.
+func (k *kubernetesDeployer) applySecret(spec PodSpec) error {
+	manifest, err := k.renderer.RenderSecret(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := k.apply(manifest); err != nil {
+		return fmt.Errorf("failed to apply secret: %w", err)
+	}
+	return nil
+}
+
+// ApplyConfigMap creates or updates the ConfigMap holding the parameters of the pod.
+// Running pods see the new parameters file once the kubelet refreshes the mounted volume.
+func (k *kubernetesDeployer) ApplyConfigMap(spec PodSpec) error {
+	manifest, err := k.renderer.RenderConfigMap(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := k.apply(manifest); err != nil {
+		return fmt.Errorf("failed to apply config map: %w", err)
+	}
+	return nil
+}
+
+// apply creates or updates the resource described by the manifest.
+func (k *kubernetesDeployer) apply(manifest []byte) error {
+	cmd := k.kubectl("apply", "-f", "-")
+	cmd.Stdin = bytes.NewReader(manifest)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := k.run(cmd); err != nil {
+		return fmt.Errorf("%w, stderr: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// RenderPod renders the manifest that CreatePod applies for the given spec without applying it
+func (k *kubernetesDeployer) RenderPod(spec PodSpec) ([]byte, error) {
+	return k.renderer.RenderPod(spec)
+}
+
+// DeletePod deleted pod by name together with its Secret and ConfigMap
 func (k *kubernetesDeployer) DeletePod(name string) error {
-	cmd := exec.Command("kubectl", "delete", "pod", name)
+	cmd := k.kubectl("delete", "pod", name)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
-		if strings.Contains(stderr.String(), "NotFound") {
-			return nil
-		}
+	if err := k.run(cmd); err != nil && !strings.Contains(stderr.String(), "NotFound") {
 		return fmt.Errorf("failed to delete pod: %w, stderr: %s", err, stderr.String())
 	}
+
+	spec := PodSpec{Name: name}
+	cmd = k.kubectl("delete", "secret/"+spec.SecretName(), "configmap/"+spec.ConfigMapName(), "--ignore-not-found")
+	stderr.Reset()
+	cmd.Stderr = &stderr
+
+	if err := k.run(cmd); err != nil {
+		return fmt.Errorf("failed to delete pod resources: %w, stderr: %s", err, stderr.String())
+	}
 	return nil
 }
 
 // GetAllPodList returns all list pods in the kubernetes
 func (k *kubernetesDeployer) GetPodList() ([]string, error) {
-	cmd := exec.Command("kubectl", "get", "pods", "-o", "json")
-	output, err := cmd.Output()
+	cmd := k.kubectl("get", "pods", "-o", "json")
+	output, err := k.output(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pods: %w", err)
 	}
@@ -77,3 +187,73 @@ func (k *kubernetesDeployer) GetPodList() ([]string, error) {
 
 	return podNames, nil
 }
+
+// Health checks that the cluster API server is reachable and ready.
+func (k *kubernetesDeployer) Health() error {
+	cmd := k.kubectl("get", "--raw=/readyz", "--request-timeout="+healthTimeout.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := k.run(cmd); err != nil {
+		return fmt.Errorf("cluster is not ready: %w, stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// PodStatus returns the observed status of the pod with the given name.
+// It returns ErrPodNotFound if the pod does not exist.
+func (k *kubernetesDeployer) PodStatus(name string) (*PodStatus, error) {
+	cmd := k.kubectl("get", "pod", name, "-o", "json")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := k.output(cmd)
+	if err != nil {
+		if strings.Contains(stderr.String(), "NotFound") {
+			return nil, ErrPodNotFound
+		}
+		return nil, fmt.Errorf("failed to get pod: %w, stderr: %s", err, stderr.String())
+	}
+
+	return parsePodStatus(output)
+}
+
+// WaitForPodReady polls the pod until it becomes ready, fails or the timeout expires.
+// On failure or timeout it returns the last observed status together with an error
+// describing the reason reported by the pod; on timeout the error wraps ErrPodNotReady.
+func (k *kubernetesDeployer) WaitForPodReady(name string, timeout time.Duration) (*PodStatus, error) {
+	return waitForPodReady(k.PodStatus, name, timeout, podPollInterval)
+}
+
+// waitForPodReady polls the status of the pod every interval until it becomes ready,
+// fails or the timeout expires. A pod that does not exist yet is polled again.
+func waitForPodReady(podStatus func(name string) (*PodStatus, error), name string, timeout, interval time.Duration) (*PodStatus, error) {
+	deadline := time.Now().Add(timeout)
+
+	var last *PodStatus
+	for {
+		status, err := podStatus(name)
+		if err != nil && !errors.Is(err, ErrPodNotFound) {
+			return last, err
+		}
+
+		if status != nil {
+			last = status
+			if status.Ready {
+				return status, nil
+			}
+			if status.Failed() {
+				return status, fmt.Errorf("pod %s failed: %s %s", name, status.Reason, status.Message)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if last == nil {
+				return nil, fmt.Errorf("%w: pod %s did not appear within %s", ErrPodNotReady, name, timeout)
+			}
+			return last, fmt.Errorf("%w: pod %s not ready within %s: phase %s, reason %s", ErrPodNotReady, name, timeout, last.Phase, last.Reason)
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForPodReady(t *testing.T) {
+	pending := &PodStatus{Name: "vwap-1", Phase: PodPending, Reason: "ContainerCreating"}
+	running := &PodStatus{Name: "vwap-1", Phase: PodRunning, Ready: true}
+	pullBackOff := &PodStatus{Name: "vwap-1", Phase: PodPending, Reason: "ImagePullBackOff", Message: "image not found"}
+	kubectlErr := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		polls    []*PodStatus
+		errs     []error
+		status   *PodStatus
+		err      error
+		contains string
+	}{
+		{
+			name:   "ready after pending",
+			polls:  []*PodStatus{nil, pending, running},
+			errs:   []error{ErrPodNotFound, nil, nil},
+			status: running,
+		},
+		{
+			name:     "failed",
+			polls:    []*PodStatus{pending, pullBackOff},
+			errs:     []error{nil, nil},
+			status:   pullBackOff,
+			contains: "pod vwap-1 failed: ImagePullBackOff image not found",
+		},
+		{
+			name:     "timeout while pending",
+			polls:    []*PodStatus{pending},
+			errs:     []error{nil},
+			status:   pending,
+			err:      ErrPodNotReady,
+			contains: "phase Pending, reason ContainerCreating",
+		},
+		{
+			name:     "timeout before the pod appears",
+			polls:    []*PodStatus{nil},
+			errs:     []error{ErrPodNotFound},
+			err:      ErrPodNotReady,
+			contains: "did not appear",
+		},
+		{
+			name:   "status error",
+			polls:  []*PodStatus{pending, nil},
+			errs:   []error{nil, kubectlErr},
+			status: pending,
+			err:    kubectlErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The last scripted poll repeats until the timeout expires.
+			calls := 0
+			podStatus := func(name string) (*PodStatus, error) {
+				assert.Equal(t, "vwap-1", name)
+				i := min(calls, len(tt.polls)-1)
+				calls++
+				return tt.polls[i], tt.errs[i]
+			}
+
+			status, err := waitForPodReady(podStatus, "vwap-1", 20*time.Millisecond, time.Millisecond)
+
+			assert.Equal(t, tt.status, status)
+			if tt.err == nil && tt.contains == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.polls), calls)
+				return
+			}
+			assert.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NotErrorIs(t, err, ErrPodNotReady)
+			}
+			if tt.contains != "" {
+				assert.Contains(t, err.Error(), tt.contains)
+			}
+		})
+	}
+}
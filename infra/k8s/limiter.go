@@ -0,0 +1,40 @@
+package k8s
+
+import "sync/atomic"
+
+// CallLimiter bounds the number of kubectl processes running at the same time.
+type CallLimiter struct {
+	slots    chan struct{}
+	inFlight int64
+}
+
+// NewCallLimiter creates a limiter admitting at most max concurrent calls.
+// It returns nil, which does not limit, if max is not positive.
+func NewCallLimiter(max int) *CallLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &CallLimiter{slots: make(chan struct{}, max)}
+}
+
+// acquire blocks until a call slot is free and returns the function releasing it.
+func (l *CallLimiter) acquire() func() {
+	if l == nil {
+		return func() {}
+	}
+
+	l.slots <- struct{}{}
+	atomic.AddInt64(&l.inFlight, 1)
+	return func() {
+		atomic.AddInt64(&l.inFlight, -1)
+		<-l.slots
+	}
+}
+
+// InFlight returns the number of calls currently running.
+func (l *CallLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return int(atomic.LoadInt64(&l.inFlight))
+}
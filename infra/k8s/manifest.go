@@ -0,0 +1,214 @@
+package k8s
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"text/template"
+)
+
+// Template file names of the pod and secret manifests.
+// Operators can override them by placing a file with the same name in the templates directory.
+const (
+	podTemplateName       = "pod.yaml.tmpl"
+	secretTemplateName    = "secret.yaml.tmpl"
+	configMapTemplateName = "configmap.yaml.tmpl"
+)
+
+// Location of the parameters document inside the algorithm container.
+// The mounted file is refreshed by the kubelet when the ConfigMap changes.
+const (
+	ParametersDir  = "/etc/algosync"
+	ParametersFile = "parameters.json"
+)
+
+//go:embed templates/*.yaml.tmpl
+var defaultTemplates embed.FS
+
+// quantityPattern matches Kubernetes resource quantities such as "500m", "2" or "1Gi".
+var quantityPattern = regexp.MustCompile(`^([0-9]+(\.[0-9]*)?|\.[0-9]+)(m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei|[eE][+-]?[0-9]+)?$`)
+
+// imageReferencePattern matches container image references: an optional registry host with port,
+// a lowercase repository path, an optional tag and an optional sha256 digest.
+// The first group is the repository name including the registry.
+var imageReferencePattern = regexp.MustCompile(`^((?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*)(?::[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+
+// PodSpec describes an algorithm pod to be created by the deployer.
+type PodSpec struct {
+	Name   string
+	Image  string
+	Labels map[string]string
+	Env    []EnvVar
+	// SecretEnv are environment variables read from the pod's Kubernetes Secret.
+	SecretEnv []EnvVar
+	// Parameters is the JSON parameters document materialized in the pod's ConfigMap.
+	Parameters   string
+	Resources    Resources
+	NodeSelector map[string]string
+	NodeAffinity []NodeSelectorRequirement
+	Tolerations  []Toleration
+}
+
+// SecretName returns the name of the Kubernetes Secret holding SecretEnv.
+func (s PodSpec) SecretName() string {
+	return s.Name + "-secrets"
+}
+
+// ConfigMapName returns the name of the ConfigMap holding Parameters.
+func (s PodSpec) ConfigMapName() string {
+	return s.Name + "-params"
+}
+
+// ParametersDir returns the directory the parameters ConfigMap is mounted at.
+func (s PodSpec) ParametersDir() string {
+	return ParametersDir
+}
+
+// ParametersFile returns the file name of the parameters document in the ConfigMap.
+func (s PodSpec) ParametersFile() string {
+	return ParametersFile
+}
+
+// EnvVar is an environment variable set in the algorithm container.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// Resources holds the CPU and memory of the algorithm container,
+// used both as requests and limits.
+type Resources struct {
+	CPU    string
+	Memory string
+}
+
+// NodeSelectorRequirement is a node label requirement in Kubernetes format.
+type NodeSelectorRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// Toleration is a pod toleration in Kubernetes format.
+type Toleration struct {
+	Key               string
+	Operator          string
+	Value             string
+	Effect            string
+	TolerationSeconds *int64
+}
+
+// IsQuantity reports whether s is a valid Kubernetes resource quantity.
+func IsQuantity(s string) bool {
+	return quantityPattern.MatchString(s)
+}
+
+// maxImageNameLength is the maximum length of the repository name of an image reference.
+const maxImageNameLength = 255
+
+// IsImageReference reports whether s is a valid container image reference such as
+// "nginx", "registry.example.com:5000/algo/twap:1.2" or "twap@sha256:<digest>".
+func IsImageReference(s string) bool {
+	match := imageReferencePattern.FindStringSubmatch(s)
+	return match != nil && len(match[1]) <= maxImageNameLength
+}
+
+type Renderer interface {
+	RenderPod(spec PodSpec) ([]byte, error)
+	RenderSecret(spec PodSpec) ([]byte, error)
+	RenderConfigMap(spec PodSpec) ([]byte, error)
+}
+
+type renderer struct {
+	pod       *template.Template
+	secret    *template.Template
+	configMap *template.Template
+}
+
+// NewRenderer creates a manifest renderer using the built-in templates.
+// If templatesDir is not empty, pod.yaml.tmpl, secret.yaml.tmpl and configmap.yaml.tmpl found there are used instead.
+func NewRenderer(templatesDir string) (Renderer, error) {
+	pod, err := loadTemplate(templatesDir, podTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := loadTemplate(templatesDir, secretTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := loadTemplate(templatesDir, configMapTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderer{pod: pod, secret: secret, configMap: configMap}, nil
+}
+
+// loadTemplate parses the named template from templatesDir, falling back to the built-in one.
+func loadTemplate(templatesDir, name string) (*template.Template, error) {
+	source, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default template %s: %w", name, err)
+	}
+
+	if templatesDir != "" {
+		override, err := os.ReadFile(filepath.Join(templatesDir, name))
+		if err == nil {
+			source = override
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+		}
+	}
+
+	tmpl, err := template.New(name).
+		Funcs(template.FuncMap{"quote": quote}).
+		Option("missingkey=error").
+		Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// RenderPod renders the Kubernetes pod manifest for the given spec as YAML.
+func (r *renderer) RenderPod(spec PodSpec) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.pod.Execute(&buf, spec); err != nil {
+		return nil, fmt.Errorf("failed to render pod manifest: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderSecret renders the Kubernetes Secret manifest holding the SecretEnv of the given spec as YAML.
+func (r *renderer) RenderSecret(spec PodSpec) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.secret.Execute(&buf, spec); err != nil {
+		return nil, fmt.Errorf("failed to render secret manifest: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderConfigMap renders the Kubernetes ConfigMap manifest holding the Parameters of the given spec as YAML.
+func (r *renderer) RenderConfigMap(spec PodSpec) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.configMap.Execute(&buf, spec); err != nil {
+		return nil, fmt.Errorf("failed to render config map manifest: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// quote returns s as a double-quoted string that is safe to embed in YAML.
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
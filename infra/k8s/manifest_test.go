@@ -0,0 +1,201 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderPod(t *testing.T) {
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+
+	tolerationSeconds := int64(30)
+	spec := PodSpec{
+		Name:         "hft-1",
+		Image:        "registry.local/hft:1.0",
+		Labels:       map[string]string{"app": "algosync", "algosync/algorithm": "hft"},
+		Env:          []EnvVar{{Name: "ALGOSYNC_CLIENT_ID", Value: "1"}},
+		Resources:    Resources{CPU: "500m", Memory: "1Gi"},
+		NodeSelector: map[string]string{"node-pool": "hft"},
+		NodeAffinity: []NodeSelectorRequirement{{Key: "zone", Operator: "In", Values: []string{"ld4"}}},
+		Tolerations:  []Toleration{{Key: "dedicated", Operator: "Equal", Value: "hft", Effect: "NoSchedule", TolerationSeconds: &tolerationSeconds}},
+	}
+
+	data, err := renderer.RenderPod(spec)
+	assert.NoError(t, err)
+
+	var pod map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(data, &pod), string(data))
+
+	expected := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":   "hft-1",
+			"labels": map[string]interface{}{"app": "algosync", "algosync/algorithm": "hft"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":  "hft-1",
+					"image": "registry.local/hft:1.0",
+					"env":   []interface{}{map[string]interface{}{"name": "ALGOSYNC_CLIENT_ID", "value": "1"}},
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+						"limits":   map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+					},
+				},
+			},
+			"nodeSelector": map[string]interface{}{"node-pool": "hft"},
+			"affinity": map[string]interface{}{
+				"nodeAffinity": map[string]interface{}{
+					"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+						"nodeSelectorTerms": []interface{}{
+							map[string]interface{}{
+								"matchExpressions": []interface{}{
+									map[string]interface{}{"key": "zone", "operator": "In", "values": []interface{}{"ld4"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "hft", "effect": "NoSchedule", "tolerationSeconds": 30},
+			},
+		},
+	}
+	assert.Equal(t, expected, pod)
+}
+
+func TestRenderPod_Minimal(t *testing.T) {
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+
+	data, err := renderer.RenderPod(PodSpec{Name: "vwap-1", Image: "test-image"})
+	assert.NoError(t, err)
+
+	var pod struct {
+		Spec map[string]interface{} `yaml:"spec"`
+	}
+	assert.NoError(t, yaml.Unmarshal(data, &pod), string(data))
+	assert.Contains(t, pod.Spec, "containers")
+	assert.NotContains(t, pod.Spec, "nodeSelector")
+	assert.NotContains(t, pod.Spec, "affinity")
+	assert.NotContains(t, pod.Spec, "tolerations")
+}
+
+func TestRenderSecret(t *testing.T) {
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+
+	spec := PodSpec{
+		Name:      "hft-1",
+		Image:     "registry.local/hft:1.0",
+		Labels:    map[string]string{"app": "algosync"},
+		SecretEnv: []EnvVar{{Name: "EXCHANGE_API_KEY", Value: "s3cr3t: \"quoted\""}},
+	}
+
+	data, err := renderer.RenderSecret(spec)
+	assert.NoError(t, err)
+
+	var secret map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(data, &secret), string(data))
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "hft-1-secrets", "labels": map[string]interface{}{"app": "algosync"}},
+		"type":       "Opaque",
+		"stringData": map[string]interface{}{"EXCHANGE_API_KEY": "s3cr3t: \"quoted\""},
+	}, secret)
+
+	data, err = renderer.RenderPod(spec)
+	assert.NoError(t, err)
+
+	var pod struct {
+		Spec struct {
+			Containers []struct {
+				Env []map[string]interface{} `yaml:"env"`
+			} `yaml:"containers"`
+		} `yaml:"spec"`
+	}
+	assert.NoError(t, yaml.Unmarshal(data, &pod), string(data))
+	assert.Equal(t, []map[string]interface{}{{
+		"name": "EXCHANGE_API_KEY",
+		"valueFrom": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{"name": "hft-1-secrets", "key": "EXCHANGE_API_KEY"},
+		},
+	}}, pod.Spec.Containers[0].Env)
+	assert.NotContains(t, string(data), "s3cr3t")
+}
+
+func TestRenderConfigMap(t *testing.T) {
+	renderer, err := NewRenderer("")
+	assert.NoError(t, err)
+
+	spec := PodSpec{Name: "vwap-1", Image: "test-image", Parameters: `{"participation_rate": 0.1}`}
+
+	data, err := renderer.RenderConfigMap(spec)
+	assert.NoError(t, err)
+
+	var configMap map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(data, &configMap), string(data))
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "vwap-1-params"},
+		"data":       map[string]interface{}{"parameters.json": `{"participation_rate": 0.1}`},
+	}, configMap)
+
+	data, err = renderer.RenderPod(spec)
+	assert.NoError(t, err)
+
+	var pod struct {
+		Spec struct {
+			Containers []struct {
+				VolumeMounts []map[string]interface{} `yaml:"volumeMounts"`
+			} `yaml:"containers"`
+			Volumes []map[string]interface{} `yaml:"volumes"`
+		} `yaml:"spec"`
+	}
+	assert.NoError(t, yaml.Unmarshal(data, &pod), string(data))
+	assert.Equal(t, []map[string]interface{}{{"name": "parameters", "mountPath": "/etc/algosync", "readOnly": true}}, pod.Spec.Containers[0].VolumeMounts)
+	assert.Equal(t, []map[string]interface{}{{"name": "parameters", "configMap": map[string]interface{}{"name": "vwap-1-params"}}}, pod.Spec.Volumes)
+}
+
+func TestNewRenderer_TemplatesDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	template := "kind: Pod\nmetadata:\n  name: {{ quote .Name }}\n  annotations:\n    team: trading\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, podTemplateName), []byte(template), 0644))
+
+	renderer, err := NewRenderer(dir)
+	assert.NoError(t, err)
+
+	data, err := renderer.RenderPod(PodSpec{Name: "twap-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: Pod\nmetadata:\n  name: \"twap-1\"\n  annotations:\n    team: trading\n", string(data))
+}
+
+func TestIsQuantity(t *testing.T) {
+	for _, q := range []string{"2", "500m", "1.5", "1Gi", "512Mi", "1e3"} {
+		assert.True(t, IsQuantity(q), q)
+	}
+	for _, q := range []string{"", "2x Intel Xeon", "16GB", "-1", "Gi"} {
+		assert.False(t, IsQuantity(q), q)
+	}
+}
+
+func TestIsImageReference(t *testing.T) {
+	digest := "@sha256:" + strings.Repeat("a", 64)
+	for _, ref := range []string{"nginx", "algo/twap:1.2", "registry.example.com:5000/algo/twap:v1.2.3-rc1", "localhost/twap_v2", "twap" + digest, "algo/twap:1.2" + digest} {
+		assert.True(t, IsImageReference(ref), ref)
+	}
+	for _, ref := range []string{"", "Algo/TWAP", "algo/twap:", "algo twap", "algo/twap:1.2@sha256:abc", ":latest", "algo//twap", "http://registry/twap", strings.Repeat("a", 256)} {
+		assert.False(t, IsImageReference(ref), ref)
+	}
+}
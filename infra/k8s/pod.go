@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Pod phases reported by Kubernetes.
+const (
+	PodPending   = "Pending"
+	PodRunning   = "Running"
+	PodSucceeded = "Succeeded"
+	PodFailed    = "Failed"
+	PodUnknown   = "Unknown"
+)
+
+// ErrPodNotFound is returned when the requested pod does not exist in the cluster.
+var ErrPodNotFound = errors.New("pod not found")
+
+// ErrPodNotReady is returned when a pod did not become ready within the wait timeout.
+// The pod may still become ready later.
+var ErrPodNotReady = errors.New("pod not ready")
+
+// failureReasons contains container waiting reasons after which a pod will not become ready on its own.
+var failureReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// PodStatus represents the observed state of a pod.
+type PodStatus struct {
+	Name         string    `json:"name"`
+	Phase        string    `json:"phase"`
+	Ready        bool      `json:"ready"`
+	Reason       string    `json:"reason,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	Image        string    `json:"image"`
+	RestartCount int       `json:"restart_count"`
+	StartedAt    time.Time `json:"started_at"`
+}
+
+// Failed reports whether the pod ended up in a state it cannot recover from without intervention.
+func (s *PodStatus) Failed() bool {
+	return s.Phase == PodFailed || failureReasons[s.Reason]
+}
+
+// pod is the subset of the Kubernetes pod object returned by `kubectl get pod -o json`.
+type pod struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Containers []struct {
+			Image string `json:"image"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase      string     `json:"phase"`
+		Reason     string     `json:"reason"`
+		Message    string     `json:"message"`
+		StartTime  *time.Time `json:"startTime"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+		ContainerStatuses []struct {
+			Ready        bool `json:"ready"`
+			RestartCount int  `json:"restartCount"`
+			State        struct {
+				Waiting *struct {
+					Reason  string `json:"reason"`
+					Message string `json:"message"`
+				} `json:"waiting"`
+				Terminated *struct {
+					Reason  string `json:"reason"`
+					Message string `json:"message"`
+				} `json:"terminated"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// parsePodStatus converts the JSON output of `kubectl get pod` into a PodStatus.
+// The reason is taken from the first waiting or terminated container and falls back to the pod reason.
+func parsePodStatus(data []byte) (*PodStatus, error) {
+	var p pod
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+
+	status := &PodStatus{
+		Name:    p.Metadata.Name,
+		Phase:   p.Status.Phase,
+		Reason:  p.Status.Reason,
+		Message: p.Status.Message,
+	}
+
+	if len(p.Spec.Containers) > 0 {
+		status.Image = p.Spec.Containers[0].Image
+	}
+
+	if p.Status.StartTime != nil {
+		status.StartedAt = *p.Status.StartTime
+	}
+
+	for _, condition := range p.Status.Conditions {
+		if condition.Type == "Ready" {
+			status.Ready = condition.Status == "True"
+		}
+	}
+
+	containerReason := false
+	for _, container := range p.Status.ContainerStatuses {
+		status.RestartCount += container.RestartCount
+
+		if containerReason {
+			continue
+		}
+		if container.State.Waiting != nil && container.State.Waiting.Reason != "" {
+			status.Reason = container.State.Waiting.Reason
+			status.Message = container.State.Waiting.Message
+			containerReason = true
+		} else if container.State.Terminated != nil && container.State.Terminated.Reason != "" {
+			status.Reason = container.State.Terminated.Reason
+			status.Message = container.State.Terminated.Message
+			containerReason = true
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePodStatus_Ready(t *testing.T) {
+	output := `{
+		"metadata": {"name": "vwap-1"},
+		"spec": {"containers": [{"image": "test-image"}]},
+		"status": {
+			"phase": "Running",
+			"startTime": "2024-07-01T10:00:00Z",
+			"conditions": [{"type": "Ready", "status": "True"}],
+			"containerStatuses": [{"ready": true, "restartCount": 1, "state": {"running": {}}}]
+		}
+	}`
+
+	status, err := parsePodStatus([]byte(output))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vwap-1", status.Name)
+	assert.Equal(t, PodRunning, status.Phase)
+	assert.Equal(t, "test-image", status.Image)
+	assert.Equal(t, 1, status.RestartCount)
+	assert.True(t, status.Ready)
+	assert.False(t, status.Failed())
+}
+
+func TestParsePodStatus_ImagePullBackOff(t *testing.T) {
+	output := `{
+		"metadata": {"name": "hft-1"},
+		"spec": {"containers": [{"image": "missing-image"}]},
+		"status": {
+			"phase": "Pending",
+			"conditions": [{"type": "Ready", "status": "False"}],
+			"containerStatuses": [{
+				"ready": false,
+				"restartCount": 0,
+				"state": {"waiting": {"reason": "ImagePullBackOff", "message": "Back-off pulling image"}}
+			}]
+		}
+	}`
+
+	status, err := parsePodStatus([]byte(output))
+
+	assert.NoError(t, err)
+	assert.Equal(t, PodPending, status.Phase)
+	assert.Equal(t, "ImagePullBackOff", status.Reason)
+	assert.Equal(t, "Back-off pulling image", status.Message)
+	assert.False(t, status.Ready)
+	assert.True(t, status.Failed())
+}
@@ -1,65 +1,226 @@
 package algosync
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"test-task/internal/models"
 	service "test-task/internal/services"
+	"test-task/pkg/http/request"
 	"test-task/pkg/http/response"
 
 	"github.com/gin-gonic/gin"
-	"github.com/mitchellh/mapstructure"
 )
 
 type ClientHandler interface {
 	AddClient(c *gin.Context)
+	GetClient(c *gin.Context)
+	ClientByName(c *gin.Context)
+	Clients(c *gin.Context)
+	AlgorithmStatuses(c *gin.Context)
 	UpdateClient(c *gin.Context)
 	DeleteClient(c *gin.Context)
+	RestoreClient(c *gin.Context)
+	DeletedClients(c *gin.Context)
 	UpdateAlgorithmStatus(c *gin.Context)
+	AlgorithmStates(c *gin.Context)
+	Scheduling(c *gin.Context)
+	SetSchedulingOverride(c *gin.Context)
+	DeleteSchedulingOverride(c *gin.Context)
+	AlgorithmWindows(c *gin.Context)
+	SetAlgorithmWindow(c *gin.Context)
+	DeleteAlgorithmWindow(c *gin.Context)
+	Calendars(c *gin.Context)
+	Manifests(c *gin.Context)
+	MigrateClient(c *gin.Context)
+	ParameterSchema(c *gin.Context)
+	Parameters(c *gin.Context)
+	SetParameters(c *gin.Context)
+	PauseClient(c *gin.Context)
+	ResumeClient(c *gin.Context)
+	ClientPause(c *gin.Context)
+	SyncMetrics(c *gin.Context)
+	LastSyncRun(c *gin.Context)
+	PlanSync(c *gin.Context)
+	SyncPlan(c *gin.Context)
+	ApplySyncPlan(c *gin.Context)
 }
 
 type clientHandler struct {
 	service service.ClientService
+	// requireIfMatch rejects updates and deletions of clients without an If-Match header.
+	requireIfMatch bool
 }
 
-func NewClientHandler(clientService service.ClientService) ClientHandler {
-	return &clientHandler{service: clientService}
+func NewClientHandler(clientService service.ClientService, requireIfMatch bool) ClientHandler {
+	return &clientHandler{service: clientService, requireIfMatch: requireIfMatch}
 }
 
 // @Summary Add new client to the database
 // @Description AddClient creates a new client with the provided data.
 // @Accept json
 // @Produce json
-// @Param body body models.Client true "Client object that needs to be added"
-// @Success 200 {object} models.Client "Successfully created client"
-// @Failure 400 {object} models.Response "error"
-// @Failure 501 {object} models.Response "error"
+// @Param Idempotency-Key header string false "Key identifying retries of the same request"
+// @Param body body models.ClientCreateRequest true "Client that needs to be added"
+// @Success 201 {object} models.Client "Created client"
+// @Header 201 {string} ETag "Revision of the client"
+// @Header 201 {string} Idempotent-Replayed "Set to true if the response is replayed for a retry"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 409 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
 // @Router /api/client/add [post]
 func (ch *clientHandler) AddClient(c *gin.Context) {
 	response := response.New(c)
-	var client models.Client
+	var create models.ClientCreateRequest
 
-	if err := c.ShouldBindJSON(&client); err != nil {
+	if err := c.ShouldBindJSON(&create); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	client := create.Client()
+	if _, err := ch.service.Create(client); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.Header("ETag", request.RevisionETag(client.Revision))
+	c.JSON(201, client)
+}
+
+// @Summary Get a client
+// @Description GetClient returns the client with the specified ID together with its algorithm status.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} models.ClientDetails "Client with algorithm status"
+// @Header 200 {string} ETag "Revision of the client"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id} [get]
+func (ch *clientHandler) GetClient(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
 		response.Error(400, err)
 		return
 	}
 
-	id, err := ch.service.Create(&client)
+	client, err := ch.service.ClientDetails(c.Request.Context(), clientID)
 	if err != nil {
-		response.Error(501, err)
+		response.Fail(err)
 		return
 	}
 
-	c.JSON(201, gin.H{"message:": "create client success", "id": id})
+	c.Header("ETag", request.RevisionETag(client.Revision))
+	c.JSON(200, client)
+}
+
+// @Summary Find a client by name
+// @Description ClientByName returns the client with the specified name, compared regardless of case.
+// @Produce json
+// @Param name query string true "Client name"
+// @Success 200 {object} models.Client "Client"
+// @Header 200 {string} ETag "Revision of the client"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/by-name [get]
+func (ch *clientHandler) ClientByName(c *gin.Context) {
+	response := response.New(c)
+
+	name := c.Query("name")
+	if name == "" {
+		response.Error(400, errors.New("name is required"))
+		return
+	}
+
+	client, err := ch.service.ClientByName(c.Request.Context(), name)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.Header("ETag", request.RevisionETag(client.Revision))
+	c.JSON(200, client)
+}
+
+// @Summary List clients
+// @Description Clients returns a page of the clients matching the filters.
+// @Produce json
+// @Param name query string false "Client name prefix"
+// @Param version query int false "Client version"
+// @Param image query string false "Client image"
+// @Param priority_min query number false "Minimum priority"
+// @Param priority_max query number false "Maximum priority"
+// @Param algorithm query string false "Enabled algorithm type (vwap, twap, hft)"
+// @Param need_restart query bool false "Need restart flag"
+// @Param sort query string false "Sort key (id, client_name, version, priority), id by default"
+// @Param order query string false "Sort order (asc, desc), asc by default"
+// @Param limit query int false "Page size, 50 by default and at most 500"
+// @Param cursor query string false "Cursor of the page to return"
+// @Success 200 {object} models.ClientPage "Page of clients"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/clients [get]
+func (ch *clientHandler) Clients(c *gin.Context) {
+	response := response.New(c)
+
+	var request models.ClientListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	page, err := ch.service.ListClients(c.Request.Context(), request)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, page)
+}
+
+// @Summary List algorithm statuses
+// @Description AlgorithmStatuses returns the algorithm status of every client.
+// @Produce json
+// @Success 200 {array} models.AlgorithmStatus "Algorithm statuses"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/algorithms [get]
+func (ch *clientHandler) AlgorithmStatuses(c *gin.Context) {
+	response := response.New(c)
+
+	statuses, err := ch.service.AlgorithmStatuses()
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+	if statuses == nil {
+		statuses = []models.AlgorithmStatus{}
+	}
+
+	c.JSON(200, statuses)
 }
 
 // @Summary UpdateClient an existing client
-// @Description UpdateClient updates the specified client with new data.
+// @Description UpdateClient changes the fields set in the body of the specified client and returns the updated client.
 // @Accept json
 // @Produce json
 // @Param id path int true "Client ID to update"
-// @Param body body map[string]interface{} true "Updated client data"
-// @Success 200 {object} models.Client
-// @Failure 400 {object} models.Response "error"
+// @Param If-Match header string false "ETag of the expected client revision"
+// @Param body body models.ClientPatch true "Client fields to change"
+// @Success 200 {object} models.Client "Updated client"
+// @Header 200 {string} ETag "Revision of the client"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 409 {object} models.Problem "error"
+// @Failure 412 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 428 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
 // @Router /api/client/{id} [patch]
 func (ch *clientHandler) UpdateClient(c *gin.Context) {
 	response := response.New(c)
@@ -70,22 +231,25 @@ func (ch *clientHandler) UpdateClient(c *gin.Context) {
 		return
 	}
 
-	var updateParams map[string]interface{}
-	if err := c.ShouldBindJSON(&updateParams); err != nil {
-		response.Error(400, err)
+	revision, ok := ch.ifMatch(c)
+	if !ok {
 		return
 	}
 
-	var client models.Client
-	if err := mapstructure.Decode(updateParams, &client); err != nil {
-		response.Error(400, err)
+	var patch models.ClientPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		response.Bind(err)
 		return
 	}
 
-	c.JSON(200, gin.H{
-		"id":      clientID,
-		"message": "client update success",
-	})
+	client, err := ch.service.PatchClient(c.Request.Context(), clientID, patch, revision)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.Header("ETag", request.RevisionETag(client.Revision))
+	c.JSON(200, client)
 }
 
 // @Summary Delete a client
@@ -93,9 +257,13 @@ func (ch *clientHandler) UpdateClient(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Client ID to delete"
+// @Param If-Match header string false "ETag of the expected client revision"
 // @Success 200 {object} models.Client "Successfully deleted client"
-// @Failure 400 {object} models.Response "error"
-// @Failure 501 {object} models.Response "error"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 412 {object} models.Problem "error"
+// @Failure 428 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
 // @Router /api/client/{id} [delete]
 func (ch *clientHandler) DeleteClient(c *gin.Context) {
 	response := response.New(c)
@@ -106,8 +274,13 @@ func (ch *clientHandler) DeleteClient(c *gin.Context) {
 		return
 	}
 
-	if err := ch.service.Delete(clientID); err != nil {
-		response.Error(501, err)
+	revision, ok := ch.ifMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := ch.service.Delete(clientID, revision); err != nil {
+		response.Fail(err)
 		return
 	}
 
@@ -117,15 +290,85 @@ func (ch *clientHandler) DeleteClient(c *gin.Context) {
 	})
 }
 
+// @Summary Restore a deleted client
+// @Description RestoreClient restores the deleted client with the specified ID and returns it.
+// @Produce json
+// @Param id path int true "Client ID to restore"
+// @Success 200 {object} models.Client "Restored client"
+// @Header 200 {string} ETag "Revision of the client"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 409 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/restore [post]
+func (ch *clientHandler) RestoreClient(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	client, err := ch.service.Restore(clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.Header("ETag", request.RevisionETag(client.Revision))
+	c.JSON(200, client)
+}
+
+// @Summary List deleted clients
+// @Description DeletedClients returns a page of the deleted clients that have not been purged yet, with their deletion time.
+// @Produce json
+// @Param name query string false "Client name prefix"
+// @Param version query int false "Client version"
+// @Param image query string false "Client image"
+// @Param priority_min query number false "Minimum priority"
+// @Param priority_max query number false "Maximum priority"
+// @Param algorithm query string false "Enabled algorithm type (vwap, twap, hft)"
+// @Param need_restart query bool false "Need restart flag"
+// @Param sort query string false "Sort key (id, client_name, version, priority), id by default"
+// @Param order query string false "Sort order (asc, desc), asc by default"
+// @Param limit query int false "Page size, 50 by default and at most 500"
+// @Param cursor query string false "Cursor of the page to return"
+// @Success 200 {object} models.ClientPage "Page of deleted clients"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/admin/clients/deleted [get]
+func (ch *clientHandler) DeletedClients(c *gin.Context) {
+	response := response.New(c)
+
+	var request models.ClientListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	page, err := ch.service.DeletedClients(c.Request.Context(), request)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, page)
+}
+
 // @Summary Update algorithm status
 // @Description UpdateAlgorithmStatus updates the algorithm status for the specified client.
 // @Accept json
 // @Produce json
 // @Param id path int true "Algorithm ID to update"
-// @Param body body map[string]interface{} true "Updated algorithm status data"
+// @Param body body models.AlgorithmStatusPatch true "Algorithms to enable or disable"
 // @Success 200 {object} models.Client "Successfully updated algorithm status"
-// @Failure 400 {object} models.Response "error"
-// @Failure 501 {object} models.Response "error"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 409 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
 // @Router /api/client/algorithm/{id} [patch]
 func (ch *clientHandler) UpdateAlgorithmStatus(c *gin.Context) {
 	response := response.New(c)
@@ -136,14 +379,14 @@ func (ch *clientHandler) UpdateAlgorithmStatus(c *gin.Context) {
 		return
 	}
 
-	var statusParams map[string]interface{}
-	if err := c.ShouldBindJSON(&statusParams); err != nil {
-		response.Error(400, err)
+	var patch models.AlgorithmStatusPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		response.Bind(err)
 		return
 	}
 
-	if err := ch.service.UpdateAlgorithmStatus(algorithmID, statusParams); err != nil {
-		response.Error(501, err)
+	if err := ch.service.UpdateAlgorithmStatus(algorithmID, patch.Columns()); err != nil {
+		response.Fail(err)
 		return
 	}
 
@@ -152,3 +395,620 @@ func (ch *clientHandler) UpdateAlgorithmStatus(c *gin.Context) {
 		"message": "algorithm updated success",
 	})
 }
+
+// @Summary Get observed algorithm state
+// @Description AlgorithmStates returns the observed state of the algorithm pods of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {array} models.AlgorithmState "Observed algorithm state"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/state [get]
+func (ch *clientHandler) AlgorithmStates(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	states, err := ch.service.AlgorithmStates(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, states)
+}
+
+// @Summary Get algorithm scheduling
+// @Description Scheduling returns the effective placement constraints of every algorithm type for the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} map[string]models.Scheduling "Scheduling per algorithm type"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/scheduling [get]
+func (ch *clientHandler) Scheduling(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	scheduling, err := ch.service.Scheduling(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, scheduling)
+}
+
+// @Summary Override algorithm scheduling
+// @Description SetSchedulingOverride overrides the placement constraints of an algorithm type for the specified client.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Param body body models.Scheduling true "Placement constraints"
+// @Success 200 {object} models.SuccessResponse "Successfully saved scheduling override"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/scheduling/{algorithm} [put]
+func (ch *clientHandler) SetSchedulingOverride(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	var scheduling models.Scheduling
+	if err := c.ShouldBindJSON(&scheduling); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	if err := ch.service.SetSchedulingOverride(c.Request.Context(), clientID, algorithm, scheduling); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "scheduling override saved"})
+}
+
+// @Summary Remove algorithm scheduling override
+// @Description DeleteSchedulingOverride restores the default placement constraints of an algorithm type for the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Success 200 {object} models.SuccessResponse "Successfully removed scheduling override"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/scheduling/{algorithm} [delete]
+func (ch *clientHandler) DeleteSchedulingOverride(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	if err := ch.service.DeleteSchedulingOverride(c.Request.Context(), clientID, algorithm); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "scheduling override removed"})
+}
+
+// @Summary Get algorithm run windows
+// @Description AlgorithmWindows returns the run windows of the specified client keyed by algorithm type.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} map[string]models.AlgorithmWindow "Run window per algorithm type"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/windows [get]
+func (ch *clientHandler) AlgorithmWindows(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	windows, err := ch.service.AlgorithmWindows(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, windows)
+}
+
+// @Summary Set algorithm run window
+// @Description SetAlgorithmWindow restricts when an enabled algorithm of the specified client runs.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Param body body models.AlgorithmWindow true "Cron expression and time zone, or calendar name, with start and stop margins"
+// @Success 200 {object} models.AlgorithmWindow "Stored run window"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/windows/{algorithm} [put]
+func (ch *clientHandler) SetAlgorithmWindow(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	var window models.AlgorithmWindow
+	if err := c.ShouldBindJSON(&window); err != nil {
+		response.Bind(err)
+		return
+	}
+	window.ClientID = clientID
+	window.Algorithm = algorithm
+
+	if err := ch.service.SetAlgorithmWindow(c.Request.Context(), &window); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, window)
+}
+
+// @Summary Remove algorithm run window
+// @Description DeleteAlgorithmWindow removes the run window of an algorithm of the specified client, so it runs whenever it is enabled.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Success 200 {object} models.SuccessResponse "Successfully removed run window"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/windows/{algorithm} [delete]
+func (ch *clientHandler) DeleteAlgorithmWindow(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	if err := ch.service.DeleteAlgorithmWindow(c.Request.Context(), clientID, algorithm); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "run window removed"})
+}
+
+// @Summary List trading calendars
+// @Description Calendars returns the trading calendars run windows can refer to, keyed by name.
+// @Produce json
+// @Success 200 {object} object "Trading calendars"
+// @Router /api/calendars [get]
+func (ch *clientHandler) Calendars(c *gin.Context) {
+	c.JSON(200, ch.service.Calendars())
+}
+
+// @Summary Render pod manifests
+// @Description Manifests renders the Kubernetes YAML the deployer would apply for every enabled algorithm of the specified client, without applying it.
+// @Produce application/yaml
+// @Param id path int true "Client ID"
+// @Success 200 {string} string "Multi-document YAML with one pod manifest per enabled algorithm"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/manifests [get]
+func (ch *clientHandler) Manifests(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	manifests, err := ch.service.Manifests(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.Data(200, "application/yaml", manifests)
+}
+
+// @Summary Migrate client to another cluster
+// @Description MigrateClient moves the algorithm pods of the specified client to another cluster.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param body body models.ClientMigration true "Target cluster, null for the default cluster"
+// @Success 200 {array} models.AlgorithmState "Algorithm state after migration"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 409 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/migrate [post]
+func (ch *clientHandler) MigrateClient(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	var migration models.ClientMigration
+	if err := c.ShouldBindJSON(&migration); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	states, err := ch.service.MigrateClient(c.Request.Context(), clientID, migration.ClusterID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, states)
+}
+
+// @Summary Get algorithm parameters schema
+// @Description ParameterSchema returns the JSON schema the parameters document of the algorithm type is validated against.
+// @Produce json
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Success 200 {object} object "JSON schema"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Router /api/algorithms/{algorithm}/schema [get]
+func (ch *clientHandler) ParameterSchema(c *gin.Context) {
+	response := response.New(c)
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	schema, err := ch.service.ParameterSchema(algorithm)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, schema)
+}
+
+// @Summary Get algorithm parameters
+// @Description Parameters returns the strategy parameters document of an algorithm of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Success 200 {object} models.AlgorithmParameters "Parameters document"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/parameters/{algorithm} [get]
+func (ch *clientHandler) Parameters(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	params, err := ch.service.Parameters(c.Request.Context(), clientID, algorithm)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, params)
+}
+
+// @Summary Set algorithm parameters
+// @Description SetParameters validates the strategy parameters document against the schema of the algorithm type and stores it.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param algorithm path string true "Algorithm type (vwap, twap, hft)"
+// @Param restart query bool false "Recreate the pod after updating its ConfigMap"
+// @Param body body object true "Parameters document"
+// @Success 200 {object} models.ParametersUpdate "Stored parameters and rollout result"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/parameters/{algorithm} [put]
+func (ch *clientHandler) SetParameters(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	algorithm := c.Param("algorithm")
+	if !models.IsAlgorithm(algorithm) {
+		response.Error(400, fmt.Errorf("unknown algorithm %q", algorithm))
+		return
+	}
+
+	restart, err := strconv.ParseBool(c.DefaultQuery("restart", "false"))
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	parameters, err := c.GetRawData()
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	update, err := ch.service.SetParameters(c.Request.Context(), clientID, algorithm, parameters, restart)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, update)
+}
+
+// @Summary Pause client
+// @Description PauseClient stops the synchronization from touching the pods of the specified client, for example while they are debugged manually.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param body body models.PauseRequest true "Reason, actor and optional expiry of the pause"
+// @Success 200 {object} models.ClientPause "Active pause"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/pause [post]
+func (ch *clientHandler) PauseClient(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	var request models.PauseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	pause, err := ch.service.PauseClient(c.Request.Context(), clientID, request)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, pause)
+}
+
+// @Summary Resume client
+// @Description ResumeClient ends the pause of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} models.SuccessResponse "Client resumed"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/pause [delete]
+func (ch *clientHandler) ResumeClient(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	if err := ch.service.ResumeClient(c.Request.Context(), clientID); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "client resumed"})
+}
+
+// @Summary Get client pause
+// @Description ClientPause returns the active pause of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} models.ClientPause "Active pause"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/pause [get]
+func (ch *clientHandler) ClientPause(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	pause, err := ch.service.ClientPause(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, pause)
+}
+
+// @Summary Get synchronization metrics
+// @Description SyncMetrics returns the queue depth, running deployer calls and per-client latency of the synchronization.
+// @Produce json
+// @Success 200 {object} models.SyncMetrics "Synchronization metrics"
+// @Router /api/sync/metrics [get]
+func (ch *clientHandler) SyncMetrics(c *gin.Context) {
+	c.JSON(200, ch.service.SyncMetrics())
+}
+
+// @Summary Get last synchronization run
+// @Description LastSyncRun returns the outcome of every client in the last finished synchronization cycle.
+// @Produce json
+// @Success 200 {object} models.SyncRun "Last synchronization run"
+// @Failure 404 {object} models.Problem "error"
+// @Router /api/sync/runs/last [get]
+func (ch *clientHandler) LastSyncRun(c *gin.Context) {
+	response := response.New(c)
+
+	run := ch.service.LastSyncRun()
+	if run == nil {
+		response.Error(404, errors.New("no synchronization run finished yet"))
+		return
+	}
+
+	c.JSON(200, run)
+}
+
+// @Summary Plan synchronization
+// @Description PlanSync computes and stores the actions the synchronization would take for every client.
+// @Produce json
+// @Success 201 {object} models.SyncPlan "Stored plan"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/sync/plan [post]
+func (ch *clientHandler) PlanSync(c *gin.Context) {
+	response := response.New(c)
+
+	plan, err := ch.service.PlanSync(c.Request.Context())
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(201, plan)
+}
+
+// @Summary Get synchronization plan
+// @Description SyncPlan returns a stored plan with its status and, once applied, the outcome of its actions.
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Success 200 {object} models.SyncPlan "Plan"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/sync/plan/{id} [get]
+func (ch *clientHandler) SyncPlan(c *gin.Context) {
+	response := response.New(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	plan, err := ch.service.SyncPlan(c.Request.Context(), id)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, plan)
+}
+
+// @Summary Apply synchronization plan
+// @Description ApplySyncPlan executes exactly the actions of a pending plan.
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Success 200 {object} models.SyncPlan "Applied plan"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 409 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/sync/plan/{id}/apply [post]
+func (ch *clientHandler) ApplySyncPlan(c *gin.Context) {
+	response := response.New(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	plan, err := ch.service.ApplySyncPlan(c.Request.Context(), id)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, plan)
+}
+
+// ifMatch returns the client revision of the If-Match header, nil if any revision matches.
+// It writes an error response and returns false if the header is malformed, or missing
+// while it is required.
+func (ch *clientHandler) ifMatch(c *gin.Context) (*int64, bool) {
+	revision, ok, err := request.IfMatchRevision(c)
+	if err != nil {
+		response.New(c).Error(400, err)
+		return nil, false
+	}
+	if !ok && ch.requireIfMatch {
+		response.New(c).Error(428, request.ErrIfMatchRequired)
+		return nil, false
+	}
+
+	return revision, true
+}
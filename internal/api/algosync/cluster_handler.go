@@ -0,0 +1,113 @@
+package algosync
+
+import (
+	"strconv"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"test-task/pkg/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClusterHandler interface {
+	AddCluster(c *gin.Context)
+	Clusters(c *gin.Context)
+	DeleteCluster(c *gin.Context)
+	Health(c *gin.Context)
+}
+
+type clusterHandler struct {
+	service service.ClusterService
+}
+
+func NewClusterHandler(clusterService service.ClusterService) ClusterHandler {
+	return &clusterHandler{service: clusterService}
+}
+
+// @Summary Register a deployment cluster
+// @Description AddCluster registers a Kubernetes cluster algorithm pods can be deployed to.
+// @Accept json
+// @Produce json
+// @Param body body models.Cluster true "Cluster name, kubeconfig context, API endpoint and credentials reference"
+// @Success 201 {object} models.Cluster "Successfully registered cluster"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/clusters [post]
+func (ch *clusterHandler) AddCluster(c *gin.Context) {
+	response := response.New(c)
+	var cluster models.Cluster
+
+	if err := c.ShouldBindJSON(&cluster); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	if _, err := ch.service.Create(c.Request.Context(), &cluster); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(201, cluster)
+}
+
+// @Summary List deployment clusters
+// @Description Clusters returns all registered clusters.
+// @Produce json
+// @Success 200 {array} models.Cluster "Registered clusters"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/clusters [get]
+func (ch *clusterHandler) Clusters(c *gin.Context) {
+	response := response.New(c)
+
+	clusters, err := ch.service.Clusters(c.Request.Context())
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, clusters)
+}
+
+// @Summary Delete a deployment cluster
+// @Description DeleteCluster removes a cluster.
+// @Produce json
+// @Param id path int true "Cluster ID to delete"
+// @Success 200 {object} models.SuccessResponse "Successfully deleted cluster"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/clusters/{id} [delete]
+func (ch *clusterHandler) DeleteCluster(c *gin.Context) {
+	response := response.New(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	if err := ch.service.Delete(c.Request.Context(), id); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "cluster deleted"})
+}
+
+// @Summary Check cluster health
+// @Description Health checks the API server of the default cluster and of every registered cluster.
+// @Produce json
+// @Success 200 {array} models.ClusterHealth "Health of every cluster"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/clusters/health [get]
+func (ch *clusterHandler) Health(c *gin.Context) {
+	response := response.New(c)
+
+	health, err := ch.service.Health(c.Request.Context())
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, health)
+}
@@ -0,0 +1,118 @@
+package algosync
+
+import (
+	"fmt"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"test-task/pkg/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KillSwitchHandler interface {
+	EngageKillSwitch(c *gin.Context)
+	ReleaseKillSwitch(c *gin.Context)
+	KillSwitches(c *gin.Context)
+}
+
+type killSwitchHandler struct {
+	service service.ClientService
+}
+
+func NewKillSwitchHandler(clientService service.ClientService) KillSwitchHandler {
+	return &killSwitchHandler{service: clientService}
+}
+
+// @Summary Engage kill switch
+// @Description EngageKillSwitch stops the selected algorithms, or every algorithm if none are selected, across all clients.
+// @Accept json
+// @Produce json
+// @Param body body models.KillSwitchRequest true "Algorithm filter, actor and reason"
+// @Success 200 {object} models.KillSwitchResult "Engaged kill switches and deleted pods"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/killswitch [post]
+func (kh *killSwitchHandler) EngageKillSwitch(c *gin.Context) {
+	response := response.New(c)
+
+	var request models.KillSwitchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	if err := validateAlgorithms(request.Algorithms); err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	result, err := kh.service.EngageKillSwitch(c.Request.Context(), request)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, result)
+}
+
+// @Summary Release kill switch
+// @Description ReleaseKillSwitch releases the kill switches of the selected algorithms, or of every algorithm if none are selected.
+// @Accept json
+// @Produce json
+// @Param body body models.KillSwitchRequest true "Algorithm filter and actor"
+// @Success 200 {array} models.KillSwitch "Released kill switches"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/killswitch/release [post]
+func (kh *killSwitchHandler) ReleaseKillSwitch(c *gin.Context) {
+	response := response.New(c)
+
+	var request models.KillSwitchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	if err := validateAlgorithms(request.Algorithms); err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	switches, err := kh.service.ReleaseKillSwitch(c.Request.Context(), request)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, switches)
+}
+
+// @Summary List engaged kill switches
+// @Description KillSwitches returns the engaged kill switches with the actor who engaged them.
+// @Produce json
+// @Success 200 {array} models.KillSwitch "Engaged kill switches"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/killswitch [get]
+func (kh *killSwitchHandler) KillSwitches(c *gin.Context) {
+	response := response.New(c)
+
+	switches, err := kh.service.KillSwitches(c.Request.Context())
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, switches)
+}
+
+// validateAlgorithms checks that every algorithm of a filter is a supported algorithm type.
+func validateAlgorithms(algorithms []string) error {
+	for _, algorithm := range algorithms {
+		if !models.IsAlgorithm(algorithm) {
+			return fmt.Errorf("unknown algorithm %q", algorithm)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package algosync
+
+import (
+	"errors"
+	"strconv"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"test-task/pkg/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAuditLimit is the number of audit entries returned when no limit is requested.
+const defaultAuditLimit = 100
+
+type ScheduledChangeHandler interface {
+	ScheduleChange(c *gin.Context)
+	ScheduledChanges(c *gin.Context)
+	CancelScheduledChange(c *gin.Context)
+	AuditEntries(c *gin.Context)
+}
+
+type scheduledChangeHandler struct {
+	service service.ScheduledChangeService
+}
+
+func NewScheduledChangeHandler(scheduledChangeService service.ScheduledChangeService) ScheduledChangeHandler {
+	return &scheduledChangeHandler{service: scheduledChangeService}
+}
+
+// @Summary Schedule algorithm change
+// @Description ScheduleChange enables or disables an algorithm of the specified client at apply_at.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param body body models.ScheduledChangeRequest true "Algorithm, flag, time to apply at, actor and reason"
+// @Success 201 {object} models.ScheduledChange "Scheduled change"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/algorithm/schedule [post]
+func (sh *scheduledChangeHandler) ScheduleChange(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	var request models.ScheduledChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	change, err := sh.service.Schedule(c.Request.Context(), clientID, request)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(201, change)
+}
+
+// @Summary List scheduled algorithm changes
+// @Description ScheduledChanges returns the scheduled changes of the specified client ordered by the time they apply at.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {array} models.ScheduledChange "Scheduled changes"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/algorithm/schedule [get]
+func (sh *scheduledChangeHandler) ScheduledChanges(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	changes, err := sh.service.ScheduledChanges(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, changes)
+}
+
+// @Summary Cancel scheduled algorithm change
+// @Description CancelScheduledChange cancels a pending change of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param change path int true "Scheduled change ID"
+// @Success 200 {object} models.SuccessResponse "Successfully cancelled change"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/algorithm/schedule/{change} [delete]
+func (sh *scheduledChangeHandler) CancelScheduledChange(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	changeID, err := strconv.ParseInt(c.Param("change"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	if err := sh.service.Cancel(c.Request.Context(), clientID, changeID); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "cancel scheduled change success"})
+}
+
+// @Summary Client audit history
+// @Description AuditEntries returns the latest audit entries of the specified client, newest first, such as applied and failed scheduled changes.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param limit query int false "Maximum number of entries, 100 by default"
+// @Success 200 {array} models.AuditEntry "Audit entries"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/audit [get]
+func (sh *scheduledChangeHandler) AuditEntries(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	limit := defaultAuditLimit
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			response.Error(400, errors.New("limit must be a positive integer"))
+			return
+		}
+	}
+
+	entries, err := sh.service.AuditEntries(c.Request.Context(), clientID, limit)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, entries)
+}
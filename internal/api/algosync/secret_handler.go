@@ -0,0 +1,114 @@
+package algosync
+
+import (
+	"strconv"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"test-task/pkg/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecretHandler interface {
+	Secrets(c *gin.Context)
+	SetSecret(c *gin.Context)
+	DeleteSecret(c *gin.Context)
+}
+
+type secretHandler struct {
+	service service.SecretService
+}
+
+func NewSecretHandler(secretService service.SecretService) SecretHandler {
+	return &secretHandler{service: secretService}
+}
+
+// @Summary List client secrets
+// @Description Secrets returns the names and injection modes of the secrets of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {array} models.ClientSecret "Client secrets without values"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/secrets [get]
+func (sh *secretHandler) Secrets(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	secrets, err := sh.service.Secrets(c.Request.Context(), clientID)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, secrets)
+}
+
+// @Summary Set a client secret
+// @Description SetSecret encrypts and stores a secret of the specified client, such as an exchange API key.
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param name path string true "Secret name, used as the environment variable name"
+// @Param body body models.SecretValue true "Secret value and injection mode"
+// @Success 200 {object} models.ClientSecret "Stored secret without value"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 404 {object} models.Problem "error"
+// @Failure 422 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/secrets/{name} [put]
+func (sh *secretHandler) SetSecret(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	var value models.SecretValue
+	if err := c.ShouldBindJSON(&value); err != nil {
+		response.Bind(err)
+		return
+	}
+
+	secret, err := sh.service.SetSecret(c.Request.Context(), clientID, c.Param("name"), value)
+	if err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, secret)
+}
+
+// @Summary Delete a client secret
+// @Description DeleteSecret deletes a secret of the specified client.
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param name path string true "Secret name"
+// @Success 200 {object} models.SuccessResponse "Successfully deleted secret"
+// @Failure 400 {object} models.Problem "error"
+// @Failure 500 {object} models.Problem "error"
+// @Router /api/client/{id}/secrets/{name} [delete]
+func (sh *secretHandler) DeleteSecret(c *gin.Context) {
+	response := response.New(c)
+
+	clientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(400, err)
+		return
+	}
+
+	if err := sh.service.DeleteSecret(c.Request.Context(), clientID, c.Param("name")); err != nil {
+		response.Fail(err)
+		return
+	}
+
+	c.JSON(200, models.SuccessResponse{Message: "secret deleted"})
+}
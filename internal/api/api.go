@@ -9,6 +9,7 @@ import (
 	"test-task/pkg/util/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerFile "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -34,11 +35,17 @@ func NewServer(infra infra.Infra) Server {
 }
 
 // Run starts the server and initializes necessary middleware and handlers.
-// It sets up rate limiting based on the configured RPS limit,
+// It registers the validation rules of request bodies, assigns request IDs, sets up rate limiting based on the configured RPS limit,
 // enables CORS middleware, registers application handlers, and API routes.
-// It also starts a background service to synchronize algorithm statuses.
+// It also starts a background service to synchronize algorithm statuses,
+// the scheduler applying scheduled algorithm changes and the purge of deleted clients.
 // Finally, it logs the start of algorithm synchronization and listens on the configured port.
 func (c *server) Run() {
+	if err := registerValidators(); err != nil {
+		logrus.Fatalf("[api][Run][registerValidators] %v", err)
+	}
+
+	c.gin.Use(c.middleware.RequestID())
 	c.gin.Use(c.middleware.RPSLimit(c.infra.Config().GetInt("rps_limit")))
 
 	c.gin.Use(c.middleware.CORS())
@@ -46,6 +53,8 @@ func (c *server) Run() {
 	c.v1()
 
 	go c.startAlgorithmSync()
+	c.service.ScheduledChangeService().StartScheduler()
+	c.service.RetentionService().StartPurge()
 
 	log := logger.GetLogger()
 	log.Info("Start algorithm sync")
@@ -67,20 +76,87 @@ func (c *server) handlers() {
 }
 
 // v1 configures versioned API endpoints (v1) for client operations.
-// It sets up routes for client management operations such as adding, updating, deleting clients,
-// and updating algorithm statuses associated with clients.
+// It sets up routes for client management operations such as reading, adding, updating, deleting and restoring clients,
+// and reading and updating algorithm statuses associated with clients.
 func (c *server) v1() {
-	clientHandler := algosync.NewClientHandler(c.service.ClientService())
+	clientHandler := algosync.NewClientHandler(c.service.ClientService(), c.infra.Config().GetBool("http.require_if_match"))
+	clusterHandler := algosync.NewClusterHandler(c.service.ClusterService())
+	secretHandler := algosync.NewSecretHandler(c.service.SecretService())
+	killSwitchHandler := algosync.NewKillSwitchHandler(c.service.ClientService())
+	scheduledChangeHandler := algosync.NewScheduledChangeHandler(c.service.ScheduledChangeService())
 
 	api := c.gin.Group("/api")
 	{
 		client := api.Group("/client")
 		{
-			client.POST("/add", clientHandler.AddClient)
+			client.POST("/add", idempotent(c.service.IdempotencyService(), "client.add"), clientHandler.AddClient)
+			client.GET("/by-name", clientHandler.ClientByName)
+			client.GET("/:id", clientHandler.GetClient)
 			client.PATCH("/:id", clientHandler.UpdateClient)
 			client.DELETE("/:id", clientHandler.DeleteClient)
+			client.POST("/:id/restore", clientHandler.RestoreClient)
+			client.GET("/:id/state", clientHandler.AlgorithmStates)
+			client.GET("/:id/scheduling", clientHandler.Scheduling)
+			client.GET("/:id/manifests", clientHandler.Manifests)
+			client.POST("/:id/migrate", clientHandler.MigrateClient)
+			client.GET("/:id/pause", clientHandler.ClientPause)
+			client.POST("/:id/pause", clientHandler.PauseClient)
+			client.DELETE("/:id/pause", clientHandler.ResumeClient)
+			client.GET("/:id/secrets", secretHandler.Secrets)
+			client.PUT("/:id/secrets/:name", secretHandler.SetSecret)
+			client.DELETE("/:id/secrets/:name", secretHandler.DeleteSecret)
+			client.GET("/:id/parameters/:algorithm", clientHandler.Parameters)
+			client.PUT("/:id/parameters/:algorithm", clientHandler.SetParameters)
+			client.PUT("/:id/scheduling/:algorithm", clientHandler.SetSchedulingOverride)
+			client.DELETE("/:id/scheduling/:algorithm", clientHandler.DeleteSchedulingOverride)
+			client.GET("/:id/windows", clientHandler.AlgorithmWindows)
+			client.PUT("/:id/windows/:algorithm", clientHandler.SetAlgorithmWindow)
+			client.DELETE("/:id/windows/:algorithm", clientHandler.DeleteAlgorithmWindow)
+			client.GET("/:id/algorithm/schedule", scheduledChangeHandler.ScheduledChanges)
+			client.POST("/:id/algorithm/schedule", scheduledChangeHandler.ScheduleChange)
+			client.DELETE("/:id/algorithm/schedule/:change", scheduledChangeHandler.CancelScheduledChange)
+			client.GET("/:id/audit", scheduledChangeHandler.AuditEntries)
 			client.PATCH("/algorithm/:id", clientHandler.UpdateAlgorithmStatus)
 		}
+
+		sync := api.Group("/sync")
+		{
+			sync.GET("/metrics", clientHandler.SyncMetrics)
+			sync.GET("/runs/last", clientHandler.LastSyncRun)
+			sync.POST("/plan", clientHandler.PlanSync)
+			sync.GET("/plan/:id", clientHandler.SyncPlan)
+			sync.POST("/plan/:id/apply", clientHandler.ApplySyncPlan)
+		}
+
+		killSwitch := api.Group("/killswitch")
+		{
+			killSwitch.POST("", killSwitchHandler.EngageKillSwitch)
+			killSwitch.GET("", killSwitchHandler.KillSwitches)
+			killSwitch.POST("/release", killSwitchHandler.ReleaseKillSwitch)
+		}
+
+		api.GET("/clients", clientHandler.Clients)
+
+		admin := api.Group("/admin")
+		{
+			admin.GET("/clients/deleted", clientHandler.DeletedClients)
+		}
+
+		algorithms := api.Group("/algorithms")
+		{
+			algorithms.GET("", clientHandler.AlgorithmStatuses)
+			algorithms.GET("/:algorithm/schema", clientHandler.ParameterSchema)
+		}
+
+		api.GET("/calendars", clientHandler.Calendars)
+
+		clusters := api.Group("/clusters")
+		{
+			clusters.POST("", clusterHandler.AddCluster)
+			clusters.GET("", clusterHandler.Clusters)
+			clusters.GET("/health", clusterHandler.Health)
+			clusters.DELETE("/:id", clusterHandler.DeleteCluster)
+		}
 	}
 
 	c.gin.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFile.Handler))
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	service "test-task/internal/services"
+	"test-task/pkg/http/request"
+	"test-task/pkg/http/response"
+	"test-task/pkg/util/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotentReplayedHeader marks responses returned from the idempotency store.
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// recordingWriter keeps a copy of the response body written by a handler.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent returns a middleware handler that makes an endpoint safe to retry for requests
+// with an Idempotency-Key header. The response of the first request with a key is stored and
+// returned for retries with the same key and body, marked by the Idempotent-Replayed header.
+// Server errors are not stored, so that a retry is processed again. Requests without the
+// header are processed as usual.
+func idempotent(idempotency service.IdempotencyService, scope string) gin.HandlerFunc {
+	const op = "api.idempotent"
+
+	log := logger.GetLogger()
+
+	return func(c *gin.Context) {
+		response := response.New(c)
+
+		key, err := request.IdempotencyKey(c)
+		if err != nil {
+			response.Error(400, err)
+			c.Abort()
+			return
+		}
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(400, err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := idempotency.Begin(c.Request.Context(), scope, key, body)
+		if err != nil {
+			response.Fail(err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		// Keep the key and store the response even if the caller went away, it is what a retry has to get.
+		ctx := context.WithoutCancel(c.Request.Context())
+		stop := idempotency.Hold(ctx, scope, key, body)
+		defer stop()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		stop()
+
+		if status := writer.Status(); status >= 500 {
+			err = idempotency.Release(ctx, scope, key)
+		} else {
+			err = idempotency.Complete(ctx, scope, key, body, status, writer.Header().Get("Content-Type"), writer.body.Bytes())
+		}
+		if err != nil {
+			log.Errorf("%s: failed to finish idempotency key %q of %s: %v", op, key, scope, err)
+		}
+	}
+}
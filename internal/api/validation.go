@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"test-task/infra/k8s"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// validators are the custom rules used in the binding tags of request models.
+var validators = map[string]validator.Func{
+	"notblank": func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	},
+	"image_ref": func(fl validator.FieldLevel) bool {
+		return k8s.IsImageReference(fl.Field().String())
+	},
+	"quantity": func(fl validator.FieldLevel) bool {
+		return k8s.IsQuantity(fl.Field().String())
+	},
+}
+
+// registerValidators configures request binding. Unknown JSON fields are rejected,
+// validation errors name fields as they appear in the request, and the custom rules
+// used in the binding tags of request models are registered.
+func registerValidators() error {
+	binding.EnableDecoderDisallowUnknownFields = true
+
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("binding validator is not a go-playground validator")
+	}
+
+	validate.RegisterTagNameFunc(requestFieldName)
+	for tag, fn := range validators {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requestFieldName returns the name of a struct field in JSON bodies or query strings.
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
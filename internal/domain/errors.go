@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+)
+
+// Kind classifies domain errors independently of the layer that returns them.
+// A kind is itself an error, so errors.Is(err, domain.NotFound) reports whether err is of that kind.
+type Kind string
+
+const (
+	// NotFound means that the requested entity does not exist.
+	NotFound Kind = "not found"
+	// Conflict means that the request contradicts the current state of an entity.
+	Conflict Kind = "conflict"
+	// Validation means that the request is well-formed but semantically invalid.
+	Validation Kind = "validation failed"
+	// PreconditionFailed means that the entity does not match the state the caller expected.
+	PreconditionFailed Kind = "precondition failed"
+	// Unavailable means that a dependency such as the database or a cluster could not be reached.
+	Unavailable Kind = "unavailable"
+)
+
+// kinds are the kinds KindOf looks for, in order of precedence.
+var kinds = []Kind{NotFound, Conflict, Validation, PreconditionFailed, Unavailable}
+
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Code returns the error code of errors of the kind that have no code of their own.
+func (k Kind) Code() string {
+	return strings.ReplaceAll(string(k), " ", "_")
+}
+
+// Error is an error of a kind with its own message and a stable code callers can branch on.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+// New returns an error of the given kind with the given code and message.
+// It is meant for sentinel errors that are wrapped with details where they occur.
+func New(kind Kind, code, message string) error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the kind of e.
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.Kind
+}
+
+// FieldError describes why the value of a request field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is a Validation error listing the invalid fields of a request.
+type FieldErrors []FieldError
+
+func (f FieldErrors) Error() string {
+	messages := make([]string, len(f))
+	for i, field := range f {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return "invalid fields: " + strings.Join(messages, "; ")
+}
+
+// Is reports whether target is the Validation kind.
+func (f FieldErrors) Is(target error) bool {
+	return target == Validation
+}
+
+// IsDomain reports whether err is of a kind set by the application.
+// The messages of such errors are written for callers, while the messages of other
+// errors may contain internal details.
+func IsDomain(err error) bool {
+	_, ok := domainKind(err)
+	return ok
+}
+
+// CodeOf returns the code of a domain error, or the code of its kind if it has none.
+// It returns an empty string for errors without a kind.
+func CodeOf(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	if kind, ok := KindOf(err); ok {
+		return kind.Code()
+	}
+	return ""
+}
+
+// KindOf returns the kind of err, and false if err has none.
+// Errors of broken database connections and failed network calls are Unavailable.
+func KindOf(err error) (Kind, bool) {
+	if kind, ok := domainKind(err); ok {
+		return kind, true
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) {
+		return Unavailable, true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Unavailable, true
+	}
+
+	return "", false
+}
+
+// domainKind returns the kind set by the application on err.
+func domainKind(err error) (Kind, bool) {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind, true
+		}
+	}
+	return "", false
+}
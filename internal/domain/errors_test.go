@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"test-task/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKindOf tests classifying errors by their domain kind.
+//
+// The test verifies that wrapped domain errors keep their kind and message, that
+// connection failures are Unavailable and that other errors have no kind.
+func TestKindOf(t *testing.T) {
+	errMissing := domain.New(domain.NotFound, "client_not_found", "client not found")
+	wrapped := fmt.Errorf("%w: 7", errMissing)
+
+	assert.EqualError(t, wrapped, "client not found: 7")
+	assert.ErrorIs(t, wrapped, errMissing)
+	assert.ErrorIs(t, wrapped, domain.NotFound)
+	assert.NotErrorIs(t, wrapped, domain.Conflict)
+
+	for err, want := range map[error]domain.Kind{
+		wrapped: domain.NotFound,
+		fmt.Errorf("%w: name taken", domain.Conflict):             domain.Conflict,
+		fmt.Errorf("failed to get client: %w", driver.ErrBadConn): domain.Unavailable,
+		context.DeadlineExceeded:                                  domain.Unavailable,
+	} {
+		kind, ok := domain.KindOf(err)
+		assert.True(t, ok, err.Error())
+		assert.Equal(t, want, kind, err.Error())
+	}
+
+	_, ok := domain.KindOf(errors.New("boom"))
+	assert.False(t, ok)
+}
+
+// TestCodeOf tests the error codes of domain errors.
+//
+// The test verifies that domain errors keep their own code when wrapped, that errors of a
+// kind without a code use the code of the kind, that field errors are validation errors and
+// that only errors of a kind set by the application are domain errors.
+func TestCodeOf(t *testing.T) {
+	errMissing := domain.New(domain.NotFound, "client_not_found", "client not found")
+	fields := domain.FieldErrors{{Field: "cpu", Message: "must be a quantity"}, {Field: "image", Message: "must not be empty"}}
+
+	assert.Equal(t, "client_not_found", domain.CodeOf(fmt.Errorf("%w: 7", errMissing)))
+	assert.Equal(t, "conflict", domain.CodeOf(fmt.Errorf("%w: name taken", domain.Conflict)))
+	assert.Equal(t, "validation_failed", domain.CodeOf(fields))
+	assert.Equal(t, "unavailable", domain.CodeOf(driver.ErrBadConn))
+	assert.Equal(t, "", domain.CodeOf(errors.New("boom")))
+
+	assert.ErrorIs(t, fields, domain.Validation)
+	assert.EqualError(t, fields, "invalid fields: cpu must be a quantity; image must not be empty")
+
+	assert.True(t, domain.IsDomain(errMissing))
+	assert.False(t, domain.IsDomain(driver.ErrBadConn))
+}
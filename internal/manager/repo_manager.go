@@ -8,6 +8,13 @@ import (
 
 type RepoManager interface {
 	ClientRepository() repository.ClientRepository
+	ClusterRepository() repository.ClusterRepository
+	SecretRepository() repository.SecretRepository
+	KillSwitchRepository() repository.KillSwitchRepository
+	ScheduledChangeRepository() repository.ScheduledChangeRepository
+	AuditRepository() repository.AuditRepository
+	SyncPlanRepository() repository.SyncPlanRepository
+	IdempotencyRepository() repository.IdempotencyRepository
 }
 
 type repoManager struct {
@@ -32,3 +39,101 @@ func (rm *repoManager) ClientRepository() repository.ClientRepository {
 	})
 	return clientRepository
 }
+
+var (
+	clusterRepositoryOnce sync.Once
+	clusterRepository     repository.ClusterRepository
+)
+
+// ClusterRepository returns an instance of the cluster repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) ClusterRepository() repository.ClusterRepository {
+	clusterRepositoryOnce.Do(func() {
+		clusterRepository = repository.NewClusterRepository(rm.infra.PSQLClient().DB)
+	})
+	return clusterRepository
+}
+
+var (
+	secretRepositoryOnce sync.Once
+	secretRepository     repository.SecretRepository
+)
+
+// SecretRepository returns an instance of the client secret repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) SecretRepository() repository.SecretRepository {
+	secretRepositoryOnce.Do(func() {
+		secretRepository = repository.NewSecretRepository(rm.infra.PSQLClient().DB)
+	})
+	return secretRepository
+}
+
+var (
+	killSwitchRepositoryOnce sync.Once
+	killSwitchRepository     repository.KillSwitchRepository
+)
+
+// KillSwitchRepository returns an instance of the kill switch repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) KillSwitchRepository() repository.KillSwitchRepository {
+	killSwitchRepositoryOnce.Do(func() {
+		killSwitchRepository = repository.NewKillSwitchRepository(rm.infra.PSQLClient().DB)
+	})
+	return killSwitchRepository
+}
+
+var (
+	scheduledChangeRepositoryOnce sync.Once
+	scheduledChangeRepository     repository.ScheduledChangeRepository
+)
+
+// ScheduledChangeRepository returns an instance of the scheduled change repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) ScheduledChangeRepository() repository.ScheduledChangeRepository {
+	scheduledChangeRepositoryOnce.Do(func() {
+		scheduledChangeRepository = repository.NewScheduledChangeRepository(rm.infra.PSQLClient().DB)
+	})
+	return scheduledChangeRepository
+}
+
+var (
+	auditRepositoryOnce sync.Once
+	auditRepository     repository.AuditRepository
+)
+
+// AuditRepository returns an instance of the audit repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) AuditRepository() repository.AuditRepository {
+	auditRepositoryOnce.Do(func() {
+		auditRepository = repository.NewAuditRepository(rm.infra.PSQLClient().DB)
+	})
+	return auditRepository
+}
+
+var (
+	syncPlanRepositoryOnce sync.Once
+	syncPlanRepository     repository.SyncPlanRepository
+)
+
+// SyncPlanRepository returns an instance of the sync plan repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) SyncPlanRepository() repository.SyncPlanRepository {
+	syncPlanRepositoryOnce.Do(func() {
+		syncPlanRepository = repository.NewSyncPlanRepository(rm.infra.PSQLClient().DB)
+	})
+	return syncPlanRepository
+}
+
+var (
+	idempotencyRepositoryOnce sync.Once
+	idempotencyRepository     repository.IdempotencyRepository
+)
+
+// IdempotencyRepository returns an instance of the idempotency key repository.
+// It lazily initializes the repository on the first call using the PSQLClient from the infrastructure.
+func (rm *repoManager) IdempotencyRepository() repository.IdempotencyRepository {
+	idempotencyRepositoryOnce.Do(func() {
+		idempotencyRepository = repository.NewIdempotencyRepository(rm.infra.PSQLClient().DB)
+	})
+	return idempotencyRepository
+}
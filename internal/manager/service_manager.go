@@ -1,13 +1,27 @@
 package manager
 
 import (
+	"os"
+	"path/filepath"
 	"sync"
 	"test-task/infra"
+	"test-task/internal/models"
 	service "test-task/internal/services"
+	"test-task/pkg/calendar"
+	"test-task/pkg/encryption"
+	"test-task/pkg/jsonschema"
+	"test-task/storage/postgres"
+
+	"github.com/sirupsen/logrus"
 )
 
 type ServiceManager interface {
 	ClientService() service.ClientService
+	ClusterService() service.ClusterService
+	SecretService() service.SecretService
+	ScheduledChangeService() service.ScheduledChangeService
+	RetentionService() service.RetentionService
+	IdempotencyService() service.IdempotencyService
 }
 
 type serviceManager struct {
@@ -29,12 +43,170 @@ var (
 )
 
 // ClientService returns an instance of the client service.
-// It lazily initializes the service on the first call using the client repository and Kubernetes deployer from the infrastructure.
+// It lazily initializes the service on the first call using the client and cluster repositories and the Kubernetes deployers from the infrastructure.
 func (sm *serviceManager) ClientService() service.ClientService {
 	clientServiceOnce.Do(func() {
 		clientRepo := sm.repo.ClientRepository()
-		clientService = service.NewClientService(clientRepo, sm.infra.KubernetesDeployer())
+		config := service.SyncConfig{
+			ReadyTimeout:     sm.infra.Config().GetDuration("sync.ready_timeout"),
+			RestartThreshold: sm.infra.Config().GetInt("sync.restart_threshold"),
+			RestartWindow:    sm.infra.Config().GetDuration("sync.restart_window"),
+			Workers:          sm.infra.Config().GetInt("sync.workers"),
+			BatchSize:        sm.infra.Config().GetInt("sync.batch_size"),
+			Interval:         sm.infra.Config().GetDuration("sync.interval"),
+		}
+		if err := sm.infra.Config().UnmarshalKey("scheduling", &config.Scheduling); err != nil {
+			logrus.Fatalf("[manager][ClientService][UnmarshalKey] %v", err)
+		}
+		config.ParameterSchemas = parameterSchemas(sm.infra.Config().GetString("parameters.schemas_dir"))
+		config.Calendars = calendars(sm.infra.Config().GetString("windows.calendars_file"))
+		clientService = service.NewClientService(clientRepo, sm.repo.ClusterRepository(), sm.repo.KillSwitchRepository(), sm.repo.SyncPlanRepository(), sm.SecretService(), sm.infra.DeployerFactory(), sm.infra.Notifier(), config)
 	})
 
 	return clientService
 }
+
+// calendars loads the trading calendars run windows can refer to.
+// Without a calendars file only cron windows can be used.
+func calendars(path string) map[string]*calendar.Calendar {
+	if path == "" {
+		return map[string]*calendar.Calendar{}
+	}
+
+	calendars, err := calendar.Load(path)
+	if err != nil {
+		logrus.Fatalf("[manager][calendars][Load] %v", err)
+	}
+
+	return calendars
+}
+
+// parameterSchemas loads the parameters schema of every algorithm type from <dir>/<algorithm>.json.
+// Algorithm types without a schema file accept any parameters object.
+func parameterSchemas(dir string) map[string]*jsonschema.Schema {
+	schemas := make(map[string]*jsonschema.Schema)
+	if dir == "" {
+		return schemas
+	}
+
+	for _, algorithm := range models.Algorithms {
+		path := filepath.Join(dir, algorithm+".json")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		schema, err := jsonschema.CompileFile(path)
+		if err != nil {
+			logrus.Fatalf("[manager][parameterSchemas][CompileFile] %v", err)
+		}
+		schemas[algorithm] = schema
+	}
+
+	return schemas
+}
+
+var (
+	clusterServiceOnce sync.Once
+	clusterService     service.ClusterService
+)
+
+// ClusterService returns an instance of the cluster service.
+// It lazily initializes the service on the first call using the cluster repository and Kubernetes deployers from the infrastructure.
+func (sm *serviceManager) ClusterService() service.ClusterService {
+	clusterServiceOnce.Do(func() {
+		clusterService = service.NewClusterService(sm.repo.ClusterRepository(), sm.infra.DeployerFactory())
+	})
+
+	return clusterService
+}
+
+const (
+	// placeholderSecretKey is the secret key the sample config used to ship with; the service refuses to start with it.
+	placeholderSecretKey = "sercret"
+	// devSecretKey is the secret key of the sample config, accepted only in the dev environment mode.
+	devSecretKey = "dev-only-insecure-key-change-me-in-production"
+)
+
+var (
+	secretServiceOnce sync.Once
+	secretService     service.SecretService
+)
+
+// SecretService returns an instance of the client secret service.
+// It lazily initializes the service on the first call, encrypting secrets with the configured secret key.
+func (sm *serviceManager) SecretService() service.SecretService {
+	secretServiceOnce.Do(func() {
+		key := sm.infra.Config().GetString("secret.key")
+		if key == placeholderSecretKey || (key == devSecretKey && sm.infra.Config().GetString("environment.mode") != "dev") {
+			logrus.Fatalf("[manager][SecretService] secret.key is the key from the sample config, set SECRET_KEY to a random key of at least %d bytes", encryption.MinKeyLength)
+		}
+		cipher, err := encryption.NewAESCipher(key)
+		if err != nil {
+			logrus.Fatalf("[manager][SecretService][NewAESCipher] %v", err)
+		}
+		secretService = service.NewSecretService(sm.repo.SecretRepository(), sm.repo.ClientRepository(), cipher)
+	})
+
+	return secretService
+}
+
+var (
+	scheduledChangeServiceOnce sync.Once
+	scheduledChangeService     service.ScheduledChangeService
+)
+
+// ScheduledChangeService returns an instance of the scheduled change service.
+// It lazily initializes the service on the first call. Instances sharing the database
+// elect the one that applies due changes with a PostgreSQL advisory lock.
+func (sm *serviceManager) ScheduledChangeService() service.ScheduledChangeService {
+	scheduledChangeServiceOnce.Do(func() {
+		leader := postgres.NewLeader(sm.infra.PSQLClient().DB, "scheduled-changes")
+		scheduledChangeService = service.NewScheduledChangeService(
+			sm.repo.ScheduledChangeRepository(),
+			sm.repo.AuditRepository(),
+			sm.ClientService(),
+			leader,
+			sm.infra.Config().GetDuration("scheduler.interval"),
+		)
+	})
+
+	return scheduledChangeService
+}
+
+var (
+	retentionServiceOnce sync.Once
+	retentionService     service.RetentionService
+)
+
+// RetentionService returns an instance of the retention service.
+// It lazily initializes the service on the first call. Instances sharing the database
+// elect the one that purges deleted clients and expired idempotency keys with a PostgreSQL advisory lock.
+func (sm *serviceManager) RetentionService() service.RetentionService {
+	retentionServiceOnce.Do(func() {
+		leader := postgres.NewLeader(sm.infra.PSQLClient().DB, "client-purge")
+		retentionService = service.NewRetentionService(
+			sm.repo.ClientRepository(),
+			sm.repo.IdempotencyRepository(),
+			leader,
+			sm.infra.Config().GetDuration("retention.deleted_clients"),
+			sm.infra.Config().GetDuration("retention.purge_interval"),
+		)
+	})
+
+	return retentionService
+}
+
+var (
+	idempotencyServiceOnce sync.Once
+	idempotencyService     service.IdempotencyService
+)
+
+// IdempotencyService returns an instance of the idempotency service.
+// It lazily initializes the service on the first call, keeping responses for the configured TTL.
+func (sm *serviceManager) IdempotencyService() service.IdempotencyService {
+	idempotencyServiceOnce.Do(func() {
+		idempotencyService = service.NewIdempotencyService(sm.repo.IdempotencyRepository(), sm.infra.Config().GetDuration("idempotency.ttl"))
+	})
+
+	return idempotencyService
+}
@@ -1,5 +1,42 @@
 package models
 
+import "time"
+
+// Algorithm types that can be enabled for a client.
+const (
+	AlgorithmVWAP = "vwap"
+	AlgorithmTWAP = "twap"
+	AlgorithmHFT  = "hft"
+)
+
+// Algorithms lists every supported algorithm type in synchronization order.
+var Algorithms = []string{AlgorithmVWAP, AlgorithmTWAP, AlgorithmHFT}
+
+// IsAlgorithm reports whether name is a supported algorithm type.
+func IsAlgorithm(name string) bool {
+	for _, algorithm := range Algorithms {
+		if algorithm == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Observed phases of an algorithm pod that are not reported by Kubernetes itself.
+const (
+	// PhaseCreated means the pod was created but its readiness was not checked.
+	PhaseCreated = "Created"
+	// PhaseFailed means the pod could not be created or did not become ready.
+	PhaseFailed = "Failed"
+	// PhaseDeleted means the algorithm is disabled and its pod was removed.
+	PhaseDeleted = "Deleted"
+	// PhaseUnknown means the state of the pod could not be determined.
+	PhaseUnknown = "Unknown"
+)
+
+// ReasonCrashLoop is the reason recorded when an algorithm is disabled after restarting too often.
+const ReasonCrashLoop = "RestartThresholdExceeded"
+
 // AlgorithmStatus represents the status of algorithms for a client.
 type AlgorithmStatus struct {
 	ID       int64 `json:"id"`
@@ -8,3 +45,48 @@ type AlgorithmStatus struct {
 	TWAP     bool  `json:"twap"`
 	HFT      bool  `json:"hft"`
 }
+
+// Enabled reports whether the given algorithm type is enabled.
+func (a AlgorithmStatus) Enabled(algorithm string) bool {
+	switch algorithm {
+	case AlgorithmVWAP:
+		return a.VWAP
+	case AlgorithmTWAP:
+		return a.TWAP
+	case AlgorithmHFT:
+		return a.HFT
+	}
+	return false
+}
+
+// AlgorithmState represents the observed state of an algorithm pod for a client.
+type AlgorithmState struct {
+	ClientID     int64      `json:"client_id"`
+	Algorithm    string     `json:"algorithm"`
+	Phase        string     `json:"phase"`
+	Ready        bool       `json:"ready"`
+	PodName      string     `json:"pod_name"`
+	Image        string     `json:"image"`
+	Reason       string     `json:"reason,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	RestartCount int        `json:"restart_count"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastSyncedAt time.Time  `json:"last_synced_at"`
+	// FailedAt is set when the algorithm was disabled after crash-looping.
+	// The pod is not recreated until an operator re-enables the algorithm.
+	FailedAt *time.Time `json:"failed_at,omitempty"`
+
+	// RestartWindowStart and RestartWindowBase track the restart count at the
+	// beginning of the current crash-loop detection window.
+	RestartWindowStart *time.Time `json:"-"`
+	RestartWindowBase  int        `json:"-"`
+}
+
+// DesiredState is a client together with the algorithm status it should run.
+// Algorithm is nil if the client has no algorithm status,
+// Pause is nil unless the client is paused.
+type DesiredState struct {
+	Client    Client
+	Algorithm *AlgorithmStatus
+	Pause     *ClientPause
+}
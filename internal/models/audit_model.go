@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actions.
+const (
+	AuditScheduledChangeApplied = "scheduled_change.applied"
+	AuditScheduledChangeFailed  = "scheduled_change.failed"
+)
+
+// AuditEntry records a change made to a client and who made it.
+type AuditEntry struct {
+	ID        int64  `json:"id"`
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	ClientID  int64  `json:"client_id"`
+	Algorithm string `json:"algorithm,omitempty"`
+	// Details holds action specific data as a JSON object.
+	Details   json.RawMessage `json:"details" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -5,16 +5,67 @@ import (
 )
 
 // Client represents a client entity in the system.
+// Revision is managed by the server and incremented on every update of the client.
+// DeletedAt is set when the client is deleted; deleted clients are kept until purged
+// and can be restored until then.
 type Client struct {
-	ID          int64     `json:"id"`
-	ClientName  string    `json:"client_name"`
-	Version     int       `json:"version"`
-	Image       string    `json:"image"`
-	CPU         string    `json:"cpu"`
-	Memory      string    `json:"memory"`
-	Priority    float64   `json:"priority"`
-	NeedRestart bool      `json:"need_restart"`
-	SpawnedAt   time.Time `json:"spawned_at"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64      `json:"id"`
+	ClientName  string     `json:"client_name"`
+	Version     int        `json:"version"`
+	Image       string     `json:"image"`
+	CPU         string     `json:"cpu"`
+	Memory      string     `json:"memory"`
+	Priority    float64    `json:"priority"`
+	NeedRestart bool       `json:"need_restart"`
+	ClusterID   *int64     `json:"cluster_id"`
+	SpawnedAt   time.Time  `json:"spawned_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Revision    int64      `json:"revision"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ClientDetails is a client together with its algorithm status.
+// Algorithm is nil if the client has no algorithm status.
+type ClientDetails struct {
+	Client
+	Algorithm *AlgorithmStatus `json:"algorithm"`
+}
+
+// ClientPatch is a partial update of a client. Only the fields that are set are changed.
+type ClientPatch struct {
+	ClientName  *string  `json:"client_name" binding:"omitempty,notblank,max=255"`
+	Version     *int     `json:"version" binding:"omitempty,min=0"`
+	Image       *string  `json:"image" binding:"omitempty,image_ref"`
+	CPU         *string  `json:"cpu" binding:"omitempty,quantity"`
+	Memory      *string  `json:"memory" binding:"omitempty,quantity"`
+	Priority    *float64 `json:"priority" binding:"omitempty,min=0,max=100"`
+	NeedRestart *bool    `json:"need_restart"`
+}
+
+// Columns returns the set fields of the patch keyed by their column name.
+func (p ClientPatch) Columns() map[string]interface{} {
+	columns := make(map[string]interface{})
+	if p.ClientName != nil {
+		columns["client_name"] = *p.ClientName
+	}
+	if p.Version != nil {
+		columns["version"] = *p.Version
+	}
+	if p.Image != nil {
+		columns["image"] = *p.Image
+	}
+	if p.CPU != nil {
+		columns["cpu"] = *p.CPU
+	}
+	if p.Memory != nil {
+		columns["memory"] = *p.Memory
+	}
+	if p.Priority != nil {
+		columns["priority"] = *p.Priority
+	}
+	if p.NeedRestart != nil {
+		columns["need_restart"] = *p.NeedRestart
+	}
+	return columns
 }
@@ -0,0 +1,65 @@
+package models
+
+// Sort keys of the client listing. Each is backed by an index on the clients table.
+const (
+	ClientSortID         = "id"
+	ClientSortClientName = "client_name"
+	ClientSortVersion    = "version"
+	ClientSortPriority   = "priority"
+)
+
+// ClientFilter selects the clients of a listing. Unset fields match every client.
+type ClientFilter struct {
+	// NamePrefix matches clients whose name starts with it.
+	NamePrefix  string   `form:"name"`
+	Version     *int     `form:"version"`
+	Image       string   `form:"image"`
+	PriorityMin *float64 `form:"priority_min"`
+	PriorityMax *float64 `form:"priority_max"`
+	// Algorithm matches clients that have the algorithm type enabled.
+	Algorithm   string `form:"algorithm"`
+	NeedRestart *bool  `form:"need_restart"`
+}
+
+// ClientListRequest holds the query parameters of the client listing.
+type ClientListRequest struct {
+	ClientFilter
+	// Sort is the sort key, id by default.
+	Sort string `form:"sort"`
+	// Order is asc or desc, asc by default.
+	Order string `form:"order"`
+	// Limit is the page size.
+	Limit int `form:"limit"`
+	// Cursor is the next_cursor of the previous page.
+	Cursor string `form:"cursor"`
+}
+
+// ClientQuery is a validated page request of the client listing.
+type ClientQuery struct {
+	Filter     ClientFilter
+	Sort       string
+	Descending bool
+	// After is the position of the last client of the previous page, nil for the first page.
+	After *ClientCursor
+	Limit int
+	// Deleted lists the deleted clients instead of the live ones.
+	Deleted bool
+}
+
+// ClientCursor is the position of a client in a listing sorted by Sort and Order:
+// the value of the sort key and the client ID, which breaks ties.
+type ClientCursor struct {
+	Sort  string      `json:"s"`
+	Order string      `json:"o"`
+	Value interface{} `json:"v"`
+	ID    int64       `json:"id"`
+}
+
+// ClientPage is a page of the client listing.
+type ClientPage struct {
+	Items []Client `json:"items"`
+	// Total is the number of clients matching the filter across all pages.
+	Total int64 `json:"total"`
+	// NextCursor requests the next page, empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,60 @@
+package models
+
+// Bounds of the priority of a client.
+const (
+	MinPriority = 0
+	MaxPriority = 100
+)
+
+// ClientCreateRequest is the request body for creating a client.
+// The ID, revision and timestamps of the client are set by the server.
+type ClientCreateRequest struct {
+	ClientName string `json:"client_name" binding:"required,notblank,max=255"`
+	Version    int    `json:"version" binding:"min=0"`
+	// Image is a container image reference such as "registry.example.com/algo/twap:1.2".
+	Image string `json:"image" binding:"required,image_ref"`
+	// CPU and Memory are Kubernetes resource quantities; empty means no limit.
+	CPU         string  `json:"cpu" binding:"omitempty,quantity"`
+	Memory      string  `json:"memory" binding:"omitempty,quantity"`
+	Priority    float64 `json:"priority" binding:"min=0,max=100"`
+	NeedRestart bool    `json:"need_restart"`
+	// ClusterID is the cluster the client is deployed to, the default cluster if null.
+	ClusterID *int64 `json:"cluster_id" binding:"omitempty,min=1"`
+}
+
+// Client returns the client described by the request.
+func (r ClientCreateRequest) Client() *Client {
+	return &Client{
+		ClientName:  r.ClientName,
+		Version:     r.Version,
+		Image:       r.Image,
+		CPU:         r.CPU,
+		Memory:      r.Memory,
+		Priority:    r.Priority,
+		NeedRestart: r.NeedRestart,
+		ClusterID:   r.ClusterID,
+	}
+}
+
+// AlgorithmStatusPatch is a partial update of the algorithm status of a client.
+// Only the algorithms that are set are changed.
+type AlgorithmStatusPatch struct {
+	VWAP *bool `json:"vwap"`
+	TWAP *bool `json:"twap"`
+	HFT  *bool `json:"hft"`
+}
+
+// Columns returns the set fields of the patch keyed by their column name.
+func (p AlgorithmStatusPatch) Columns() map[string]interface{} {
+	columns := make(map[string]interface{})
+	if p.VWAP != nil {
+		columns[AlgorithmVWAP] = *p.VWAP
+	}
+	if p.TWAP != nil {
+		columns[AlgorithmTWAP] = *p.TWAP
+	}
+	if p.HFT != nil {
+		columns[AlgorithmHFT] = *p.HFT
+	}
+	return columns
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Cluster represents a Kubernetes cluster algorithm pods can be deployed to.
+type Cluster struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Context is the kubeconfig context used to reach the cluster.
+	Context string `json:"context"`
+	// APIEndpoint is the address of the cluster API server.
+	APIEndpoint string `json:"api_endpoint"`
+	// CredentialsRef is the path to the kubeconfig file holding the cluster credentials.
+	CredentialsRef string    `json:"credentials_ref"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ClusterHealth represents the result of a cluster health check.
+type ClusterHealth struct {
+	// ClusterID is nil for the default cluster.
+	ClusterID *int64    `json:"cluster_id"`
+	Name      string    `json:"name"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ClientMigration is the request body for moving a client to another cluster.
+type ClientMigration struct {
+	// ClusterID is the target cluster, or null for the default cluster.
+	ClusterID *int64 `json:"cluster_id"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is a request made with an idempotency key and, once it is done, its response.
+// Retries with the same key and body get the stored response instead of being processed again.
+type IdempotencyRecord struct {
+	// Scope is the endpoint the key was used with.
+	Scope string
+	Key   string
+	// RequestHash is the hex SHA-256 hash of the request body.
+	RequestHash string
+	// StatusCode is the status of the response, zero while the request is in progress.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	// ExpiresAt is when the key can be used for another request.
+	ExpiresAt time.Time
+}
+
+// Done reports whether the response of the request is stored.
+func (r *IdempotencyRecord) Done() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// KillSwitch represents an emergency stop of an algorithm type across all clients.
+// While a kill switch is engaged the algorithm cannot be enabled for any client.
+type KillSwitch struct {
+	ID        int64     `json:"id"`
+	Algorithm string    `json:"algorithm"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	EngagedAt time.Time `json:"engaged_at"`
+	// ReleasedBy and ReleasedAt are nil while the kill switch is engaged.
+	ReleasedBy *string    `json:"released_by"`
+	ReleasedAt *time.Time `json:"released_at"`
+}
+
+// KillSwitchRequest is the request body for engaging or releasing kill switches.
+type KillSwitchRequest struct {
+	// Algorithms is the algorithm filter, empty means every algorithm type.
+	Algorithms []string `json:"algorithms" binding:"dive,oneof=vwap twap hft"`
+	Actor      string   `json:"actor" binding:"required"`
+	Reason     string   `json:"reason"`
+}
+
+// KillSwitchResult reports the outcome of engaging kill switches.
+type KillSwitchResult struct {
+	// KillSwitches are the engaged kill switches of the requested algorithms.
+	KillSwitches []KillSwitch `json:"kill_switches"`
+	// DisabledClients is the number of clients that had a matching algorithm enabled.
+	DisabledClients int `json:"disabled_clients"`
+	DeletedPods     int `json:"deleted_pods"`
+	// Errors lists the pods that could not be deleted; they are retried by the synchronization.
+	Errors []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AlgorithmParameters represents the strategy parameters document of a client algorithm,
+// such as participation rate, slice interval and risk limits.
+type AlgorithmParameters struct {
+	ClientID   int64           `json:"client_id"`
+	Algorithm  string          `json:"algorithm"`
+	Parameters json.RawMessage `json:"parameters" swaggertype:"object"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// ParametersUpdate reports how a parameters change was rolled out.
+type ParametersUpdate struct {
+	AlgorithmParameters
+	// Applied is true if the ConfigMap of the running pod was updated in place.
+	Applied bool `json:"applied"`
+	// Restarted is true if the pod was recreated to pick up the change.
+	Restarted bool `json:"restarted"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ClientPause represents a client the reconciler must not touch, for example
+// while its pods are being debugged manually in the cluster.
+type ClientPause struct {
+	ClientID int64     `json:"client_id"`
+	Reason   string    `json:"reason"`
+	Actor    string    `json:"actor"`
+	PausedAt time.Time `json:"paused_at"`
+	// ExpiresAt is when the pause ends automatically, nil pauses until the client is resumed.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Active reports whether the pause is in effect at the given time.
+func (p *ClientPause) Active(now time.Time) bool {
+	return p != nil && (p.ExpiresAt == nil || p.ExpiresAt.After(now))
+}
+
+// PauseRequest is the request body for pausing a client.
+type PauseRequest struct {
+	Reason string `json:"reason" binding:"required"`
+	Actor  string `json:"actor" binding:"required"`
+	// ExpiresAt is an optional RFC 3339 time at which the pause ends.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Statuses of a scheduled change.
+const (
+	ScheduledChangePending   = "pending"
+	ScheduledChangeApplying  = "applying"
+	ScheduledChangeApplied   = "applied"
+	ScheduledChangeFailed    = "failed"
+	ScheduledChangeCancelled = "cancelled"
+)
+
+// ScheduledChange is a one-off change of an algorithm flag of a client that is
+// applied by the scheduler once ApplyAt is reached.
+type ScheduledChange struct {
+	ID        int64     `json:"id"`
+	ClientID  int64     `json:"client_id"`
+	Algorithm string    `json:"algorithm"`
+	Enabled   bool      `json:"enabled"`
+	ApplyAt   time.Time `json:"apply_at"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"`
+	// AppliedAt is set once the change was applied or failed.
+	AppliedAt *time.Time `json:"applied_at"`
+	// Error is the reason a failed change could not be applied.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduledChangeRequest is the request body for scheduling a change.
+type ScheduledChangeRequest struct {
+	Algorithm string    `json:"algorithm" binding:"required,oneof=vwap twap hft"`
+	Enabled   *bool     `json:"enabled" binding:"required"`
+	ApplyAt   time.Time `json:"apply_at" binding:"required"`
+	Actor     string    `json:"actor" binding:"required"`
+	Reason    string    `json:"reason"`
+}
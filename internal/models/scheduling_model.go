@@ -0,0 +1,54 @@
+package models
+
+// Scheduling represents the placement constraints of an algorithm pod.
+type Scheduling struct {
+	NodeSelector map[string]string         `json:"node_selector,omitempty" mapstructure:"node_selector"`
+	NodeAffinity []NodeSelectorRequirement `json:"node_affinity,omitempty" mapstructure:"node_affinity"`
+	Tolerations  []Toleration              `json:"tolerations,omitempty" mapstructure:"tolerations"`
+}
+
+// NodeSelectorRequirement is a node label requirement the pod must be scheduled on.
+type NodeSelectorRequirement struct {
+	Key      string   `json:"key" mapstructure:"key"`
+	Operator string   `json:"operator" mapstructure:"operator"`
+	Values   []string `json:"values,omitempty" mapstructure:"values"`
+}
+
+// Toleration allows the pod to be scheduled on nodes with a matching taint.
+type Toleration struct {
+	Key               string `json:"key,omitempty" mapstructure:"key"`
+	Operator          string `json:"operator,omitempty" mapstructure:"operator"`
+	Value             string `json:"value,omitempty" mapstructure:"value"`
+	Effect            string `json:"effect,omitempty" mapstructure:"effect"`
+	TolerationSeconds *int64 `json:"toleration_seconds,omitempty" mapstructure:"toleration_seconds"`
+}
+
+// Merge returns the scheduling constraints with the override applied.
+// Node selector labels are merged with the override taking precedence,
+// while node affinity and tolerations are replaced when the override sets them.
+func (s Scheduling) Merge(override Scheduling) Scheduling {
+	merged := Scheduling{
+		NodeAffinity: s.NodeAffinity,
+		Tolerations:  s.Tolerations,
+	}
+
+	if len(s.NodeSelector) > 0 || len(override.NodeSelector) > 0 {
+		merged.NodeSelector = make(map[string]string, len(s.NodeSelector)+len(override.NodeSelector))
+		for k, v := range s.NodeSelector {
+			merged.NodeSelector[k] = v
+		}
+		for k, v := range override.NodeSelector {
+			merged.NodeSelector[k] = v
+		}
+	}
+
+	if len(override.NodeAffinity) > 0 {
+		merged.NodeAffinity = override.NodeAffinity
+	}
+
+	if len(override.Tolerations) > 0 {
+		merged.Tolerations = override.Tolerations
+	}
+
+	return merged
+}
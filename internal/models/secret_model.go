@@ -0,0 +1,53 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Secret injection modes.
+const (
+	// SecretInjectionEnv sets the secret as a plain environment variable in the pod manifest.
+	SecretInjectionEnv = "env"
+	// SecretInjectionSecret stores the secret in a Kubernetes Secret the pod reads it from.
+	SecretInjectionSecret = "secret"
+)
+
+// ReservedEnvPrefix is the prefix of environment variables set by the service itself.
+const ReservedEnvPrefix = "ALGOSYNC_"
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsSecretName reports whether name can be used as a secret name.
+// Secret names become environment variable names in the algorithm container.
+func IsSecretName(name string) bool {
+	return secretNamePattern.MatchString(name) && !strings.HasPrefix(name, ReservedEnvPrefix)
+}
+
+// IsSecretInjection reports whether injection is a known secret injection mode.
+func IsSecretInjection(injection string) bool {
+	return injection == SecretInjectionEnv || injection == SecretInjectionSecret
+}
+
+// ClientSecret represents a secret of a client, such as an exchange API key.
+// The secret value is never serialized.
+type ClientSecret struct {
+	ID        int64     `json:"id"`
+	ClientID  int64     `json:"client_id"`
+	Name      string    `json:"name"`
+	Injection string    `json:"injection"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Ciphertext is the encrypted secret value as stored in the database.
+	Ciphertext []byte `json:"-"`
+	// Value is the decrypted secret value, only set when the secret is injected into pods.
+	Value string `json:"-"`
+}
+
+// SecretValue is the request body for setting a client secret.
+type SecretValue struct {
+	Value string `json:"value" binding:"required"`
+	// Injection is either "env" or "secret", defaults to "secret".
+	Injection string `json:"injection"`
+}
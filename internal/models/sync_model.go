@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// SyncMetrics describes the progress and latency of the algorithm synchronization.
+type SyncMetrics struct {
+	// Workers is the number of clients reconciled concurrently.
+	Workers int `json:"workers"`
+	// QueueDepth is the number of clients waiting for a worker in the current cycle.
+	QueueDepth int `json:"queue_depth"`
+	// InFlight is the number of clients being reconciled right now.
+	InFlight int `json:"in_flight"`
+	// DeployerCalls is the number of kubectl calls running right now.
+	DeployerCalls int `json:"deployer_calls"`
+	// Cycles is the number of completed synchronization cycles.
+	Cycles int64 `json:"cycles"`
+	// LastCycleStartedAt is when the latest cycle started.
+	LastCycleStartedAt *time.Time `json:"last_cycle_started_at"`
+	// LastCycleDuration is how long the latest completed cycle took.
+	LastCycleDuration time.Duration `json:"last_cycle_duration" swaggertype:"integer"`
+	// ClientLatency summarizes per-client reconciliation time in the latest completed cycle.
+	ClientLatency LatencySummary `json:"client_latency"`
+	// Clients holds the latest reconciliation time per client ID.
+	Clients map[int64]time.Duration `json:"clients" swaggertype:"object"`
+}
+
+// LatencySummary summarizes a set of durations.
+type LatencySummary struct {
+	Count int           `json:"count"`
+	Avg   time.Duration `json:"avg" swaggertype:"integer"`
+	P95   time.Duration `json:"p95" swaggertype:"integer"`
+	Max   time.Duration `json:"max" swaggertype:"integer"`
+}
+
+// Outcomes of a client in a sync run.
+const (
+	SyncStatusSynced  = "synced"
+	SyncStatusPaused  = "paused"
+	SyncStatusSkipped = "skipped"
+)
+
+// SyncRun reports the outcome of a synchronization cycle for every client.
+type SyncRun struct {
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt *time.Time         `json:"finished_at"`
+	Synced     int                `json:"synced"`
+	Paused     int                `json:"paused"`
+	Skipped    int                `json:"skipped"`
+	Clients    []ClientSyncResult `json:"clients"`
+}
+
+// ClientSyncResult is the outcome of a client in a sync run.
+type ClientSyncResult struct {
+	ClientID int64  `json:"client_id"`
+	Status   string `json:"status"`
+	// Message explains why the client was skipped.
+	Message  string        `json:"message,omitempty"`
+	Pause    *ClientPause  `json:"pause,omitempty"`
+	Duration time.Duration `json:"duration" swaggertype:"integer"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Actions of a sync plan.
+const (
+	PlanActionCreate  = "create"
+	PlanActionDelete  = "delete"
+	PlanActionReplace = "replace"
+)
+
+// Statuses of a sync plan.
+const (
+	// PlanPending means the plan can be applied.
+	PlanPending = "pending"
+	// PlanApplying means the plan is being applied.
+	PlanApplying = "applying"
+	// PlanApplied means the actions of the plan were executed.
+	PlanApplied = "applied"
+	// PlanStale means the plan was refused because the state changed after it was computed.
+	PlanStale = "stale"
+)
+
+// SyncPlan is the list of pod actions the synchronization would take, computed from
+// the desired and observed state of every client. A pending plan can be applied as
+// long as that state did not change.
+type SyncPlan struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	// Fingerprint identifies the state the plan was computed from.
+	Fingerprint string       `json:"-"`
+	Actions     []PlanAction `json:"actions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	AppliedAt   *time.Time   `json:"applied_at"`
+}
+
+// PlanAction is a pod change of a client algorithm in a sync plan.
+type PlanAction struct {
+	ClientID  int64  `json:"client_id"`
+	Algorithm string `json:"algorithm"`
+	Action    string `json:"action"`
+	PodName   string `json:"pod_name"`
+	Cluster   string `json:"cluster"`
+	Reason    string `json:"reason"`
+	// Error is set once the plan is applied if the action failed.
+	Error string `json:"error,omitempty"`
+}
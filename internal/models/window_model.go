@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AlgorithmWindow restricts when an enabled algorithm of a client runs.
+// Exactly one of Cron and Calendar is set. Outside its window the pod of the
+// algorithm is deleted even though the algorithm stays enabled.
+type AlgorithmWindow struct {
+	ClientID  int64  `json:"client_id"`
+	Algorithm string `json:"algorithm"`
+	// Cron is a five-field cron expression matching the minutes during which
+	// the algorithm runs, e.g. "30-59 9 * * MON-FRI" and "* 10-15 * * MON-FRI".
+	Cron string `json:"cron,omitempty"`
+	// Timezone is the IANA time zone Cron is evaluated in, UTC if empty.
+	Timezone string `json:"timezone,omitempty"`
+	// Calendar is the name of a trading calendar the algorithm runs during the sessions of.
+	// Calendars are loaded from a local file.
+	Calendar string `json:"calendar,omitempty"`
+	// StartBefore is how many minutes before the window opens the pod is started.
+	StartBefore int `json:"start_before"`
+	// StopAfter is how many minutes after the window closes the pod is stopped.
+	StopAfter int       `json:"stop_after"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -9,3 +9,21 @@ type Response struct {
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
+
+// Problem is an RFC 7807 problem details response describing a failed request.
+// Code is a stable error code callers can branch on; Detail is omitted for internal errors.
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField describes why the value of a request field is invalid.
+type ProblemField struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+)
+
+type AuditRepository interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	AuditEntries(ctx context.Context, clientID int64, limit int) ([]models.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	log := logger.GetLogger()
+	return &auditRepository{db: db, log: log}
+}
+
+// Record appends an entry to the audit history and sets its ID and creation time.
+// Entries without details are stored with an empty details object.
+func (ar *auditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	const op = "repository.audit.Record"
+
+	details := string(entry.Details)
+	if details == "" {
+		details = "{}"
+	}
+
+	query := `
+		INSERT INTO audit_log (actor, action, client_id, algorithm, details)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := ar.db.QueryRowContext(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.ClientID,
+		entry.Algorithm,
+		details,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		ar.log.Errorf("%s: failed to insert audit entry: %v", op, err)
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// AuditEntries retrieves the latest limit audit entries of a client, newest first.
+func (ar *auditRepository) AuditEntries(ctx context.Context, clientID int64, limit int) ([]models.AuditEntry, error) {
+	const op = "repository.audit.AuditEntries"
+
+	query := `
+		SELECT id, actor, action, client_id, algorithm, details, created_at
+		FROM audit_log
+		WHERE client_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := ar.db.QueryContext(ctx, query, clientID, limit)
+	if err != nil {
+		ar.log.Errorf("%s: failed to retrieve audit entries: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var details []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.ClientID,
+			&entry.Algorithm,
+			&details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			ar.log.Errorf("%s: failed to scan audit entry row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan audit entry row: %w", err)
+		}
+		entry.Details = details
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		ar.log.Errorf("%s: error during iteration over audit entries: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecordAuditEntry tests that entries without details are stored with an empty object.
+func TestRecordAuditEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("INSERT INTO audit_log \\(actor, action, client_id, algorithm, details\\)").
+		WithArgs("alice", models.AuditScheduledChangeApplied, int64(42), "twap", "{}").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+
+	entry := &models.AuditEntry{Actor: "alice", Action: models.AuditScheduledChangeApplied, ClientID: 42, Algorithm: "twap"}
+	err = repo.Record(context.Background(), entry)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(3), entry.ID)
+	assert.Equal(t, now, entry.CreatedAt)
+}
+
+// TestAuditEntries tests retrieving the audit history of a client.
+func TestAuditEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM audit_log WHERE client_id = \\$1 ORDER BY created_at DESC, id DESC LIMIT \\$2").
+		WithArgs(int64(42), 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor", "action", "client_id", "algorithm", "details", "created_at"}).
+			AddRow(3, "alice", models.AuditScheduledChangeApplied, 42, "twap", []byte(`{"enabled":true}`), now))
+
+	entries, err := repo.AuditEntries(context.Background(), 42, 50)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []models.AuditEntry{
+		{ID: 3, Actor: "alice", Action: models.AuditScheduledChangeApplied, ClientID: 42, Algorithm: "twap", Details: json.RawMessage(`{"enabled":true}`), CreatedAt: now},
+	}, entries)
+}
@@ -3,23 +3,83 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"test-task/internal/domain"
 	"test-task/internal/models"
 	"test-task/pkg/util/logger"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrClientNotFound is returned when no client with the given ID exists.
+	ErrClientNotFound = domain.New(domain.NotFound, "client_not_found", "client not found")
+	// ErrAlgorithmStatusNotFound is returned when no algorithm status with the given ID
+	// or client ID exists.
+	ErrAlgorithmStatusNotFound = domain.New(domain.NotFound, "algorithm_status_not_found", "algorithm status not found")
+	// ErrDeletedClientNotFound is returned when no deleted client with the given ID exists.
+	ErrDeletedClientNotFound = domain.New(domain.NotFound, "deleted_client_not_found", "deleted client not found")
+	// ErrClientNameTaken is returned when another client that is not deleted has the same
+	// name, compared regardless of case.
+	ErrClientNameTaken = domain.New(domain.Conflict, "client_name_taken", "client name is already taken")
 )
 
+// clientNameIndex is the unique index on the lower-case names of the clients that are not deleted.
+const clientNameIndex = "idx_clients_client_name_unique"
+
+// isKillSwitchViolation reports whether err was raised by the check_kill_switches trigger,
+// which rejects enabling an algorithm whose kill switch is engaged.
+func isKillSwitchViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23514" && pqErr.Message == "kill switch engaged"
+}
+
+// isClientNameConflict reports whether err is a violation of the unique client name index.
+func isClientNameConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == clientNameIndex
+}
+
 type ClientRepository interface {
 	Create(client *models.Client, algorithm *models.AlgorithmStatus) (int64, error)
 	ClientByID(id int64) (*models.Client, error)
+	ClientByName(ctx context.Context, name string) (*models.Client, error)
 	Update(id int64, updateParams map[string]interface{}) error
+	UpdateIfRevision(id, revision int64, updateParams map[string]interface{}) (bool, error)
 	Delete(id int64) error
+	DeleteIfRevision(id, revision int64) (bool, error)
+	Restore(id int64) error
+	PurgeDeletedClients(ctx context.Context, deletedBefore time.Time) (int64, error)
+	DeletedClientsWithPods(ctx context.Context) ([]models.Client, error)
 	Clients() ([]models.Client, error)
+	ListClients(ctx context.Context, query models.ClientQuery) ([]models.Client, int64, error)
 	AlgorithmStatuses() ([]models.AlgorithmStatus, error)
 	AlgorithmByClientID(ctx context.Context, clientID int64) (*models.AlgorithmStatus, error)
+	DesiredStates(ctx context.Context, afterID int64, limit int) ([]models.DesiredState, error)
 	UpdateAlgorithmStatus(id int64, status map[string]interface{}) error
+	AlgorithmStates(ctx context.Context, clientID int64) ([]models.AlgorithmState, error)
+	AlgorithmStatesByClients(ctx context.Context, clientIDs []int64) (map[int64][]models.AlgorithmState, error)
+	SaveAlgorithmState(ctx context.Context, state *models.AlgorithmState) error
+	ResetAlgorithmFailures(ctx context.Context, algorithmID int64, algorithms []string) error
+	SchedulingOverrides(ctx context.Context, clientID int64) (map[string]models.Scheduling, error)
+	SchedulingOverridesByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.Scheduling, error)
+	SaveSchedulingOverride(ctx context.Context, clientID int64, algorithm string, scheduling models.Scheduling) error
+	DeleteSchedulingOverride(ctx context.Context, clientID int64, algorithm string) error
+	AlgorithmParameters(ctx context.Context, clientID int64) (map[string]models.AlgorithmParameters, error)
+	AlgorithmParametersByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmParameters, error)
+	SaveAlgorithmParameters(ctx context.Context, params *models.AlgorithmParameters) error
+	Pause(ctx context.Context, clientID int64) (*models.ClientPause, error)
+	SavePause(ctx context.Context, pause *models.ClientPause) error
+	DeletePause(ctx context.Context, clientID int64) error
+	AlgorithmWindows(ctx context.Context, clientID int64) (map[string]models.AlgorithmWindow, error)
+	AlgorithmWindowsByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmWindow, error)
+	SaveAlgorithmWindow(ctx context.Context, window *models.AlgorithmWindow) error
+	DeleteAlgorithmWindow(ctx context.Context, clientID int64, algorithm string) error
 }
 
 type clientRepository struct {
@@ -34,6 +94,8 @@ func NewClientRepository(db *sql.DB) ClientRepository {
 
 // Create creates a new client record along with its associated algorithm status.
 // It uses a transaction to ensure atomicity and returns the ID of the newly created client.
+// The ID and revision of the client are set to the stored ones.
+// It returns ErrClientNameTaken if another client has the same name.
 func (cr *clientRepository) Create(client *models.Client, algorithm *models.AlgorithmStatus) (int64, error) {
 	const op = "repository.client.Create"
 
@@ -52,9 +114,9 @@ func (cr *clientRepository) Create(client *models.Client, algorithm *models.Algo
 	}()
 
 	queryClient := `
-		INSERT INTO clients (client_name, version, image, cpu, memory, priority, need_restart, spawned_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id
+		INSERT INTO clients (client_name, version, image, cpu, memory, priority, need_restart, cluster_id, spawned_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, revision
 	`
 	stmtClient, err := tx.Prepare(queryClient)
 	if err != nil {
@@ -72,10 +134,15 @@ func (cr *clientRepository) Create(client *models.Client, algorithm *models.Algo
 		client.Memory,
 		client.Priority,
 		client.NeedRestart,
+		client.ClusterID,
 		client.SpawnedAt,
 		client.CreatedAt,
 		client.UpdatedAt,
-	).Scan(&clientID)
+	).Scan(&clientID, &client.Revision)
+	if isClientNameConflict(err) {
+		cr.log.Debugf("%s: client name %q is already taken", op, client.ClientName)
+		return 0, fmt.Errorf("%w: %q", ErrClientNameTaken, client.ClientName)
+	}
 	if err != nil {
 		cr.log.Errorf("%s: failed to insert client: %v", op, err)
 		return 0, fmt.Errorf("failed to insert client: %w", err)
@@ -114,18 +181,20 @@ func (cr *clientRepository) Create(client *models.Client, algorithm *models.Algo
 
 	cr.log.Infof("%s: transaction committed successfully", op)
 
+	client.ID = clientID
+
 	return clientID, nil
 }
 
 // ClientByID retrieves a client by its ID from the database.
-// It returns a pointer to the client object if found, or nil if not found.
+// It returns ErrClientNotFound if the client does not exist or is deleted.
 func (cr *clientRepository) ClientByID(id int64) (*models.Client, error) {
 	const op = "repository.client.ClientByID"
 
 	query := `
-		SELECT id, client_name, version, image, cpu, memory, priority, need_restart, spawned_at, created_at, updated_at
+		SELECT id, client_name, version, image, cpu, memory, priority, need_restart, cluster_id, spawned_at, created_at, updated_at, revision
 		FROM clients
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var client models.Client
@@ -138,14 +207,16 @@ func (cr *clientRepository) ClientByID(id int64) (*models.Client, error) {
 		&client.Memory,
 		&client.Priority,
 		&client.NeedRestart,
+		&client.ClusterID,
 		&client.SpawnedAt,
 		&client.CreatedAt,
 		&client.UpdatedAt,
+		&client.Revision,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			cr.log.Debugf("%s: client with ID %d not found", op, id)
-			return nil, nil
+			return nil, fmt.Errorf("%w: %d", ErrClientNotFound, id)
 		}
 		cr.log.Errorf("%s: failed to get client: %v", op, err)
 		return nil, fmt.Errorf("failed to get client: %w", err)
@@ -156,70 +227,297 @@ func (cr *clientRepository) ClientByID(id int64) (*models.Client, error) {
 	return &client, nil
 }
 
+// ClientByName retrieves the client that is not deleted with the given name, compared regardless of case.
+// It returns ErrClientNotFound if no such client exists.
+func (cr *clientRepository) ClientByName(ctx context.Context, name string) (*models.Client, error) {
+	const op = "repository.client.ClientByName"
+
+	query := `
+		SELECT id, client_name, version, image, cpu, memory, priority, need_restart, cluster_id, spawned_at, created_at, updated_at, revision
+		FROM clients
+		WHERE lower(client_name) = lower($1) AND deleted_at IS NULL
+	`
+
+	var client models.Client
+	err := cr.db.QueryRowContext(ctx, query, name).Scan(
+		&client.ID,
+		&client.ClientName,
+		&client.Version,
+		&client.Image,
+		&client.CPU,
+		&client.Memory,
+		&client.Priority,
+		&client.NeedRestart,
+		&client.ClusterID,
+		&client.SpawnedAt,
+		&client.CreatedAt,
+		&client.UpdatedAt,
+		&client.Revision,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cr.log.Debugf("%s: client with name %q not found", op, name)
+			return nil, fmt.Errorf("%w: %q", ErrClientNotFound, name)
+		}
+		cr.log.Errorf("%s: failed to get client: %v", op, err)
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	cr.log.Infof("%s: retrieved client with name %q", op, name)
+
+	return &client, nil
+}
+
+// updatableColumns are the client columns Update may change.
+var updatableColumns = map[string]bool{
+	"client_name":  true,
+	"version":      true,
+	"image":        true,
+	"cpu":          true,
+	"memory":       true,
+	"priority":     true,
+	"need_restart": true,
+	"cluster_id":   true,
+}
+
 // Update updates a client record identified by the given ID with the provided update parameters.
 // It accepts a map of update parameters where keys represent column names
-// and values represent new values for those columns.
+// and values represent new values for those columns. Only updatableColumns are accepted,
+// and columns are set in alphabetical order. The revision of the client is incremented.
+// It returns ErrClientNotFound if the client does not exist or is deleted, and
+// ErrClientNameTaken if another client has the new name.
 func (cr *clientRepository) Update(id int64, updateParams map[string]interface{}) error {
 	const op = "repository.client.Update"
 
+	updated, err := cr.update(op, id, nil, updateParams)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		cr.log.Debugf("%s: client with ID %d not found", op, id)
+		return fmt.Errorf("%w: %d", ErrClientNotFound, id)
+	}
+
+	cr.log.Infof("%s: client with ID %d updated successfully", op, id)
+
+	return nil
+}
+
+// UpdateIfRevision updates a client record like Update, but only if its revision equals
+// the given revision. It returns false if no client with the ID and revision exists.
+func (cr *clientRepository) UpdateIfRevision(id, revision int64, updateParams map[string]interface{}) (bool, error) {
+	const op = "repository.client.UpdateIfRevision"
+
+	updated, err := cr.update(op, id, &revision, updateParams)
+	if err != nil {
+		return false, err
+	}
+	if !updated {
+		cr.log.Debugf("%s: client with ID %d and revision %d not found", op, id, revision)
+		return false, nil
+	}
+
+	cr.log.Infof("%s: client with ID %d updated successfully at revision %d", op, id, revision)
+
+	return true, nil
+}
+
+// update sets the given columns of a client that is not deleted and increments its revision.
+// If revision is not nil, the client is only updated if its revision matches.
+// It returns whether a client was updated.
+func (cr *clientRepository) update(op string, id int64, revision *int64, updateParams map[string]interface{}) (bool, error) {
 	if len(updateParams) == 0 {
 		cr.log.Errorf("%s: no updates provided", op)
-		return fmt.Errorf("no updates provided")
+		return false, fmt.Errorf("no updates provided")
 	}
 
-	setClauses := make([]string, 0, len(updateParams))
-	args := make([]interface{}, 0, len(updateParams)+2)
-	i := 1
+	columns := make([]string, 0, len(updateParams))
+	for column := range updateParams {
+		if !updatableColumns[column] {
+			cr.log.Errorf("%s: column %q cannot be updated", op, column)
+			return false, fmt.Errorf("column %q cannot be updated", column)
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
 
-	for column, value := range updateParams {
-		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, i))
-		args = append(args, value)
-		i++
+	setClauses := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns)+3)
+	for i, column := range columns {
+		// Column names come from the whitelist above.
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, i+1))
+		args = append(args, updateParams[column])
 	}
 
+	i := len(columns) + 1
 	setClause := strings.Join(setClauses, ", ")
-	query := fmt.Sprintf("UPDATE clients SET %s, updated_at = $%d WHERE id = $%d", setClause, i, i+1)
+	query := fmt.Sprintf("UPDATE clients SET %s, revision = revision + 1, updated_at = $%d WHERE id = $%d", setClause, i, i+1)
 	args = append(args, time.Now(), id)
+	if revision != nil {
+		query += fmt.Sprintf(" AND revision = $%d", i+2)
+		args = append(args, *revision)
+	}
+	query += " AND deleted_at IS NULL"
 
-	_, err := cr.db.Exec(query, args...)
+	result, err := cr.db.Exec(query, args...)
+	if isClientNameConflict(err) {
+		cr.log.Debugf("%s: client name %q is already taken", op, updateParams["client_name"])
+		return false, fmt.Errorf("%w: %q", ErrClientNameTaken, updateParams["client_name"])
+	}
 	if err != nil {
 		cr.log.Errorf("%s: failed to update client: %v", op, err)
-		return fmt.Errorf("failed to update client: %w", err)
+		return false, fmt.Errorf("failed to update client: %w", err)
 	}
 
-	cr.log.Infof("%s: client with ID %d updated successfully", op, id)
+	affected, err := result.RowsAffected()
+	if err != nil {
+		cr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
 
-	return nil
+	return affected > 0, nil
 }
 
-// Delete deletes a client record identified by the given ID from the database.
+// Delete marks a client record identified by the given ID as deleted and increments
+// its revision. The record is kept until PurgeDeletedClients removes it.
+// It returns ErrClientNotFound if the client does not exist or is already deleted.
 func (cr *clientRepository) Delete(id int64) error {
 	const op = "repository.client.Delete"
 
+	deleted, err := cr.softDelete(op, id, nil)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		cr.log.Debugf("%s: client with ID %d not found", op, id)
+		return fmt.Errorf("%w: %d", ErrClientNotFound, id)
+	}
+
+	cr.log.Infof("%s: client with ID %d deleted successfully", op, id)
+
+	return nil
+}
+
+// DeleteIfRevision deletes a client record like Delete, but only if its revision equals
+// the given revision. It returns false if no client with the ID and revision exists.
+func (cr *clientRepository) DeleteIfRevision(id, revision int64) (bool, error) {
+	const op = "repository.client.DeleteIfRevision"
+
+	deleted, err := cr.softDelete(op, id, &revision)
+	if err != nil {
+		return false, err
+	}
+	if !deleted {
+		cr.log.Debugf("%s: client with ID %d and revision %d not found", op, id, revision)
+		return false, nil
+	}
+
+	cr.log.Infof("%s: client with ID %d deleted successfully at revision %d", op, id, revision)
+
+	return true, nil
+}
+
+// softDelete sets the deletion time of a client that is not deleted yet and increments its revision.
+// If revision is not nil, the client is only deleted if its revision matches.
+// It returns whether a client was deleted.
+func (cr *clientRepository) softDelete(op string, id int64, revision *int64) (bool, error) {
+	query := "UPDATE clients SET deleted_at = $1, revision = revision + 1, updated_at = $1 WHERE id = $2"
+	args := []interface{}{time.Now(), id}
+	if revision != nil {
+		query += " AND revision = $3"
+		args = append(args, *revision)
+	}
+	query += " AND deleted_at IS NULL"
+
+	result, err := cr.db.Exec(query, args...)
+	if err != nil {
+		cr.log.Errorf("%s: failed to delete client: %v", op, err)
+		return false, fmt.Errorf("failed to delete client: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		cr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Restore clears the deletion time of a deleted client and increments its revision.
+// It returns ErrDeletedClientNotFound if no deleted client with the given ID exists, and
+// ErrClientNameTaken if a client with the same name was created since the deletion.
+func (cr *clientRepository) Restore(id int64) error {
+	const op = "repository.client.Restore"
+
 	query := `
-		DELETE FROM clients 
-		WHERE id = $1
+		UPDATE clients
+		SET deleted_at = NULL, revision = revision + 1, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NOT NULL
 	`
 
-	_, err := cr.db.Exec(query, id)
+	result, err := cr.db.Exec(query, time.Now(), id)
+	if isClientNameConflict(err) {
+		cr.log.Debugf("%s: name of client with ID %d is already taken", op, id)
+		return fmt.Errorf("%w: client %d", ErrClientNameTaken, id)
+	}
 	if err != nil {
-		cr.log.Errorf("%s: failed to delete client: %v", op, err)
-		return fmt.Errorf("failed to delete client: %w", err)
+		cr.log.Errorf("%s: failed to restore client: %v", op, err)
+		return fmt.Errorf("failed to restore client: %w", err)
 	}
 
-	cr.log.Infof("%s: client with ID %d deleted successfully", op, id)
+	affected, err := result.RowsAffected()
+	if err != nil {
+		cr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		cr.log.Debugf("%s: deleted client with ID %d not found", op, id)
+		return fmt.Errorf("%w: %d", ErrDeletedClientNotFound, id)
+	}
+
+	cr.log.Infof("%s: client with ID %d restored successfully", op, id)
 
 	return nil
 }
 
-// Clients retrieves all clients stored in the database.
+// PurgeDeletedClients permanently removes the clients deleted before deletedBefore together
+// with their algorithm status, state, secrets and other dependent records.
+// It returns the number of clients removed.
+func (cr *clientRepository) PurgeDeletedClients(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	const op = "repository.client.PurgeDeletedClients"
+
+	query := `
+		DELETE FROM clients
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`
+
+	result, err := cr.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		cr.log.Errorf("%s: failed to purge deleted clients: %v", op, err)
+		return 0, fmt.Errorf("failed to purge deleted clients: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		cr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	cr.log.Infof("%s: purged %d clients deleted before %v", op, purged, deletedBefore)
+
+	return purged, nil
+}
+
+// Clients retrieves all clients stored in the database that are not deleted.
 // It returns a slice of client objects or an error if the operation fails.
 func (cr *clientRepository) Clients() ([]models.Client, error) {
 	const op = "repository.client.Clients"
 
 	query := `
-		SELECT id, client_name, version, image, cpu, memory, priority, need_restart, spawned_at, created_at, updated_at
+		SELECT id, client_name, version, image, cpu, memory, priority, need_restart, cluster_id, spawned_at, created_at, updated_at, revision
 		FROM clients
+		WHERE deleted_at IS NULL
 	`
 
 	rows, err := cr.db.Query(query)
@@ -241,9 +539,11 @@ func (cr *clientRepository) Clients() ([]models.Client, error) {
 			&client.Memory,
 			&client.Priority,
 			&client.NeedRestart,
+			&client.ClusterID,
 			&client.SpawnedAt,
 			&client.CreatedAt,
 			&client.UpdatedAt,
+			&client.Revision,
 		)
 		if err != nil {
 			cr.log.Errorf("%s: failed to scan client row: %v", op, err)
@@ -262,7 +562,187 @@ func (cr *clientRepository) Clients() ([]models.Client, error) {
 	return clients, nil
 }
 
-// AlgorithmStatuses retrieves all algorithm statuses stored in the database.
+// DeletedClientsWithPods retrieves the deleted clients that still have an algorithm state
+// other than deleted, i.e. whose pods may still be running.
+func (cr *clientRepository) DeletedClientsWithPods(ctx context.Context) ([]models.Client, error) {
+	const op = "repository.client.DeletedClientsWithPods"
+
+	query := `
+		SELECT c.id, c.client_name, c.version, c.image, c.cpu, c.memory, c.priority, c.need_restart, c.cluster_id, c.spawned_at, c.created_at, c.updated_at, c.revision, c.deleted_at
+		FROM clients c
+		WHERE c.deleted_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM algorithm_state s WHERE s.client_id = c.id AND s.phase <> $1)
+		ORDER BY c.id
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, models.PhaseDeleted)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve deleted clients: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve deleted clients: %w", err)
+	}
+	defer rows.Close()
+
+	clients := make([]models.Client, 0)
+	for rows.Next() {
+		var client models.Client
+		err := rows.Scan(
+			&client.ID,
+			&client.ClientName,
+			&client.Version,
+			&client.Image,
+			&client.CPU,
+			&client.Memory,
+			&client.Priority,
+			&client.NeedRestart,
+			&client.ClusterID,
+			&client.SpawnedAt,
+			&client.CreatedAt,
+			&client.UpdatedAt,
+			&client.Revision,
+			&client.DeletedAt,
+		)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan client row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan client row: %w", err)
+		}
+		clients = append(clients, client)
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over deleted clients: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over deleted clients: %w", err)
+	}
+
+	cr.log.Debugf("%s: retrieved %d deleted clients with pods", op, len(clients))
+
+	return clients, nil
+}
+
+// clientSortColumns maps the sort keys of the client listing to their indexed columns.
+var clientSortColumns = map[string]string{
+	models.ClientSortID:         "c.id",
+	models.ClientSortClientName: "c.client_name",
+	models.ClientSortVersion:    "c.version",
+	models.ClientSortPriority:   "c.priority",
+}
+
+// ListClients retrieves up to query.Limit clients matching the filter, sorted by the sort key
+// with the client ID breaking ties, starting after query.After. It also returns the number of
+// clients matching the filter across all pages. Only deleted clients are listed if query.Deleted
+// is set, otherwise only the clients that are not deleted.
+func (cr *clientRepository) ListClients(ctx context.Context, query models.ClientQuery) ([]models.Client, int64, error) {
+	const op = "repository.client.ListClients"
+
+	sortColumn, ok := clientSortColumns[query.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown sort key %q", query.Sort)
+	}
+
+	conditions := []string{"c.deleted_at IS NULL"}
+	if query.Deleted {
+		conditions[0] = "c.deleted_at IS NOT NULL"
+	}
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	filter := query.Filter
+	if filter.NamePrefix != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.NamePrefix)
+		conditions = append(conditions, "c.client_name LIKE "+arg(escaped+"%"))
+	}
+	if filter.Version != nil {
+		conditions = append(conditions, "c.version = "+arg(*filter.Version))
+	}
+	if filter.Image != "" {
+		conditions = append(conditions, "c.image = "+arg(filter.Image))
+	}
+	if filter.PriorityMin != nil {
+		conditions = append(conditions, "c.priority >= "+arg(*filter.PriorityMin))
+	}
+	if filter.PriorityMax != nil {
+		conditions = append(conditions, "c.priority <= "+arg(*filter.PriorityMax))
+	}
+	if filter.NeedRestart != nil {
+		conditions = append(conditions, "c.need_restart = "+arg(*filter.NeedRestart))
+	}
+	if filter.Algorithm != "" {
+		if !models.IsAlgorithm(filter.Algorithm) {
+			return nil, 0, fmt.Errorf("unknown algorithm %q", filter.Algorithm)
+		}
+		// The column name comes from the algorithm whitelist above.
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM algorithm_status a WHERE a.client_id = c.id AND a.%s)", filter.Algorithm))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := cr.db.QueryRowContext(ctx, "SELECT count(*) FROM clients c "+where, args...).Scan(&total); err != nil {
+		cr.log.Errorf("%s: failed to count clients: %v", op, err)
+		return nil, 0, fmt.Errorf("failed to count clients: %w", err)
+	}
+
+	order, compare := "ASC", ">"
+	if query.Descending {
+		order, compare = "DESC", "<"
+	}
+	if query.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, c.id) %s (%s, %s)", sortColumn, compare, arg(query.After.Value), arg(query.After.ID)))
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	list := fmt.Sprintf(`
+		SELECT c.id, c.client_name, c.version, c.image, c.cpu, c.memory, c.priority, c.need_restart, c.cluster_id, c.spawned_at, c.created_at, c.updated_at, c.revision, c.deleted_at
+		FROM clients c
+		%s
+		ORDER BY %s %s, c.id %s
+		LIMIT %s
+	`, where, sortColumn, order, order, arg(query.Limit))
+
+	rows, err := cr.db.QueryContext(ctx, list, args...)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve clients: %v", op, err)
+		return nil, 0, fmt.Errorf("failed to retrieve clients: %w", err)
+	}
+	defer rows.Close()
+
+	clients := make([]models.Client, 0, query.Limit)
+	for rows.Next() {
+		var client models.Client
+		err := rows.Scan(
+			&client.ID,
+			&client.ClientName,
+			&client.Version,
+			&client.Image,
+			&client.CPU,
+			&client.Memory,
+			&client.Priority,
+			&client.NeedRestart,
+			&client.ClusterID,
+			&client.SpawnedAt,
+			&client.CreatedAt,
+			&client.UpdatedAt,
+			&client.Revision,
+			&client.DeletedAt,
+		)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan client row: %v", op, err)
+			return nil, 0, fmt.Errorf("failed to scan client row: %w", err)
+		}
+		clients = append(clients, client)
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over clients: %v", op, err)
+		return nil, 0, fmt.Errorf("error during iteration over clients: %w", err)
+	}
+
+	return clients, total, nil
+}
+
+// AlgorithmStatuses retrieves the algorithm statuses of all clients that are not deleted.
 // It returns a slice of algorithm status objects or an error if the operation fails.
 func (cr *clientRepository) AlgorithmStatuses() ([]models.AlgorithmStatus, error) {
 	const op = "repository.client.AlgorithmStatuses"
@@ -270,6 +750,7 @@ func (cr *clientRepository) AlgorithmStatuses() ([]models.AlgorithmStatus, error
 	query := `
 		SELECT id, client_id, vwap, twap, hft
 		FROM algorithm_status
+		WHERE client_id IN (SELECT id FROM clients WHERE deleted_at IS NULL)
 	`
 
 	rows, err := cr.db.Query(query)
@@ -309,6 +790,11 @@ func (cr *clientRepository) AlgorithmStatuses() ([]models.AlgorithmStatus, error
 // UpdateAlgorithmStatus updates the algorithm status identified by the given ID.
 // It accepts a map of status updates where keys represent column names in the
 // algorithm_status table and values represent new values for those columns.
+// Only the algorithm flag columns are accepted.
+// Enabling algorithms takes the kill switch lock shared and only updates the row while
+// none of their kill switches is engaged, so it cannot race with Engage.
+// It returns ErrKillSwitchEngaged if one of the enabled algorithms has an engaged kill switch
+// and ErrAlgorithmStatusNotFound if the algorithm status does not exist or its client is deleted.
 func (cr *clientRepository) UpdateAlgorithmStatus(id int64, status map[string]interface{}) error {
 	const op = "repository.client.UpdateAlgorithmStatus"
 
@@ -319,13 +805,21 @@ func (cr *clientRepository) UpdateAlgorithmStatus(id int64, status map[string]in
 
 	setClauses := make([]string, 0, len(status))
 	args := make([]interface{}, 0, len(status)+1)
+	enabled := make([]string, 0, len(status))
 	i := 1
 
 	for column, value := range status {
+		if !models.IsAlgorithm(column) {
+			cr.log.Errorf("%s: column %q cannot be updated", op, column)
+			return fmt.Errorf("column %q cannot be updated", column)
+		}
 		switch v := value.(type) {
 		case bool:
 			setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, i))
 			args = append(args, v)
+			if v {
+				enabled = append(enabled, column)
+			}
 		default:
 			cr.log.Errorf("%s: unsupported type for column %s: %T", op, column, v)
 			return fmt.Errorf("unsupported type for column %s", column)
@@ -334,29 +828,81 @@ func (cr *clientRepository) UpdateAlgorithmStatus(id int64, status map[string]in
 	}
 
 	setClause := strings.Join(setClauses, ", ")
-	query := fmt.Sprintf("UPDATE algorithm_status SET %s WHERE id = $%d", setClause, i)
+	query := fmt.Sprintf("UPDATE algorithm_status SET %s WHERE id = $%d AND client_id IN (SELECT id FROM clients WHERE deleted_at IS NULL)", setClause, i)
 	args = append(args, id)
 
-	_, err := cr.db.Exec(query, args...)
+	sort.Strings(enabled)
+	if len(enabled) > 0 {
+		query += fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM kill_switches WHERE released_at IS NULL AND algorithm = ANY($%d))", i+1)
+		args = append(args, pq.Array(enabled))
+	}
+
+	tx, err := cr.db.Begin()
+	if err != nil {
+		cr.log.Errorf("%s: failed to begin transaction: %v", op, err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(enabled) > 0 {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock_shared($1)", killSwitchLockKey); err != nil {
+			cr.log.Errorf("%s: failed to acquire kill switch lock: %v", op, err)
+			return fmt.Errorf("failed to acquire kill switch lock: %w", err)
+		}
+	}
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
+		if isKillSwitchViolation(err) {
+			cr.log.Debugf("%s: kill switch engaged for %v", op, enabled)
+			return fmt.Errorf("%w for %s", ErrKillSwitchEngaged, strings.Join(enabled, ", "))
+		}
 		cr.log.Errorf("%s: failed to update algorithm status: %v", op, err)
 		return fmt.Errorf("failed to update algorithm status: %w", err)
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		cr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		if len(enabled) > 0 {
+			var engaged bool
+			err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM kill_switches WHERE released_at IS NULL AND algorithm = ANY($1))", pq.Array(enabled)).Scan(&engaged)
+			if err != nil {
+				cr.log.Errorf("%s: failed to check kill switches: %v", op, err)
+				return fmt.Errorf("failed to check kill switches: %w", err)
+			}
+			if engaged {
+				cr.log.Debugf("%s: kill switch engaged for %v", op, enabled)
+				return fmt.Errorf("%w for %s", ErrKillSwitchEngaged, strings.Join(enabled, ", "))
+			}
+		}
+		cr.log.Debugf("%s: algorithm status with ID %d not found", op, id)
+		return fmt.Errorf("%w: %d", ErrAlgorithmStatusNotFound, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		cr.log.Errorf("%s: failed to commit transaction: %v", op, err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	cr.log.Infof("%s: algorithm status with ID %d updated successfully", op, id)
 
 	return nil
 }
 
 // AlgorithmByClientID retrieves the algorithm status associated with a client ID.
-// It returns a pointer to the algorithm status object if found, or nil if not found.
+// It returns ErrAlgorithmStatusNotFound if the client has no algorithm status or is deleted.
 func (cr *clientRepository) AlgorithmByClientID(ctx context.Context, clientID int64) (*models.AlgorithmStatus, error) {
 	const op = "repository.client.AlgorithmByClientID"
 
 	query := `
-		SELECT id, client_id, vwap, twap, hft 
-		FROM algorithm_status
-		WHERE client_id = $1
+		SELECT a.id, a.client_id, a.vwap, a.twap, a.hft
+		FROM algorithm_status a
+		JOIN clients c ON c.id = a.client_id
+		WHERE a.client_id = $1 AND c.deleted_at IS NULL
 	`
 
 	var algorithm models.AlgorithmStatus
@@ -370,7 +916,7 @@ func (cr *clientRepository) AlgorithmByClientID(ctx context.Context, clientID in
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			cr.log.Debugf("%s: algorithm status not found for client ID %d", op, clientID)
-			return nil, nil
+			return nil, fmt.Errorf("%w: client %d", ErrAlgorithmStatusNotFound, clientID)
 		}
 		cr.log.Errorf("%s: failed to retrieve algorithm status: %v", op, err)
 		return nil, fmt.Errorf("failed to retrieve algorithm status: %w", err)
@@ -380,3 +926,770 @@ func (cr *clientRepository) AlgorithmByClientID(ctx context.Context, clientID in
 
 	return &algorithm, nil
 }
+
+// DesiredStates retrieves the clients that are not deleted together with their algorithm status and active pause
+// in a single query, ordered by client ID. Only clients with an ID greater than afterID are returned, so the
+// ID of the last client of a page is the cursor of the next page. A limit of zero or less
+// returns all remaining clients.
+func (cr *clientRepository) DesiredStates(ctx context.Context, afterID int64, limit int) ([]models.DesiredState, error) {
+	const op = "repository.client.DesiredStates"
+
+	query := `
+		SELECT c.id, c.client_name, c.version, c.image, c.cpu, c.memory, c.priority, c.need_restart, c.cluster_id, c.spawned_at, c.created_at, c.updated_at, c.revision,
+			a.id, a.vwap, a.twap, a.hft,
+			p.reason, p.actor, p.paused_at, p.expires_at
+		FROM clients c
+		LEFT JOIN algorithm_status a ON a.client_id = c.id
+		LEFT JOIN client_pauses p ON p.client_id = c.id AND (p.expires_at IS NULL OR p.expires_at > now())
+		WHERE c.id > $1 AND c.deleted_at IS NULL
+		ORDER BY c.id
+		LIMIT $2
+	`
+
+	var pageSize interface{}
+	if limit > 0 {
+		pageSize = limit
+	}
+
+	rows, err := cr.db.QueryContext(ctx, query, afterID, pageSize)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve desired states: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve desired states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]models.DesiredState, 0)
+	for rows.Next() {
+		var client models.Client
+		var algorithmID sql.NullInt64
+		var vwap, twap, hft sql.NullBool
+		var pauseReason, pauseActor sql.NullString
+		var pausedAt, pauseExpiresAt sql.NullTime
+		err := rows.Scan(
+			&client.ID,
+			&client.ClientName,
+			&client.Version,
+			&client.Image,
+			&client.CPU,
+			&client.Memory,
+			&client.Priority,
+			&client.NeedRestart,
+			&client.ClusterID,
+			&client.SpawnedAt,
+			&client.CreatedAt,
+			&client.UpdatedAt,
+			&client.Revision,
+			&algorithmID,
+			&vwap,
+			&twap,
+			&hft,
+			&pauseReason,
+			&pauseActor,
+			&pausedAt,
+			&pauseExpiresAt,
+		)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan desired state row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan desired state row: %w", err)
+		}
+
+		state := models.DesiredState{Client: client}
+		if algorithmID.Valid {
+			state.Algorithm = &models.AlgorithmStatus{
+				ID:       algorithmID.Int64,
+				ClientID: client.ID,
+				VWAP:     vwap.Bool,
+				TWAP:     twap.Bool,
+				HFT:      hft.Bool,
+			}
+		}
+		if pausedAt.Valid {
+			state.Pause = &models.ClientPause{
+				ClientID: client.ID,
+				Reason:   pauseReason.String,
+				Actor:    pauseActor.String,
+				PausedAt: pausedAt.Time,
+			}
+			if pauseExpiresAt.Valid {
+				state.Pause.ExpiresAt = &pauseExpiresAt.Time
+			}
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over desired states: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over desired states: %w", err)
+	}
+
+	cr.log.Debugf("%s: retrieved %d desired states after client ID %d", op, len(states), afterID)
+
+	return states, nil
+}
+
+// AlgorithmStates retrieves the observed state of the algorithm pods of a client.
+// It returns an empty slice if the client has not been synchronized yet.
+func (cr *clientRepository) AlgorithmStates(ctx context.Context, clientID int64) ([]models.AlgorithmState, error) {
+	const op = "repository.client.AlgorithmStates"
+
+	query := `
+		SELECT client_id, algorithm, phase, ready, pod_name, image, reason, started_at, restart_count,
+			last_error, last_synced_at, failed_at, restart_window_started_at, restart_window_base
+		FROM algorithm_state
+		WHERE client_id = $1
+		ORDER BY algorithm
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve algorithm states: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve algorithm states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]models.AlgorithmState, 0)
+	for rows.Next() {
+		state, err := scanAlgorithmState(rows)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan algorithm state row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan algorithm state row: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over algorithm states: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over algorithm states: %w", err)
+	}
+
+	cr.log.Debugf("%s: retrieved %d algorithm states for client ID %d", op, len(states), clientID)
+
+	return states, nil
+}
+
+// AlgorithmStatesByClients retrieves the observed state of the algorithm pods of the given clients
+// with a single query, keyed by client ID. Clients that have not been synchronized yet are missing.
+func (cr *clientRepository) AlgorithmStatesByClients(ctx context.Context, clientIDs []int64) (map[int64][]models.AlgorithmState, error) {
+	const op = "repository.client.AlgorithmStatesByClients"
+
+	query := `
+		SELECT client_id, algorithm, phase, ready, pod_name, image, reason, started_at, restart_count,
+			last_error, last_synced_at, failed_at, restart_window_started_at, restart_window_base
+		FROM algorithm_state
+		WHERE client_id = ANY($1)
+		ORDER BY client_id, algorithm
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, pq.Array(clientIDs))
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve algorithm states: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve algorithm states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[int64][]models.AlgorithmState, len(clientIDs))
+	for rows.Next() {
+		state, err := scanAlgorithmState(rows)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan algorithm state row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan algorithm state row: %w", err)
+		}
+		states[state.ClientID] = append(states[state.ClientID], state)
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over algorithm states: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over algorithm states: %w", err)
+	}
+
+	cr.log.Debugf("%s: retrieved algorithm states of %d clients", op, len(states))
+
+	return states, nil
+}
+
+// scanAlgorithmState scans an algorithm state row selected by AlgorithmStates.
+func scanAlgorithmState(rows *sql.Rows) (models.AlgorithmState, error) {
+	var state models.AlgorithmState
+	var startedAt, failedAt, windowStart sql.NullTime
+	err := rows.Scan(
+		&state.ClientID,
+		&state.Algorithm,
+		&state.Phase,
+		&state.Ready,
+		&state.PodName,
+		&state.Image,
+		&state.Reason,
+		&startedAt,
+		&state.RestartCount,
+		&state.LastError,
+		&state.LastSyncedAt,
+		&failedAt,
+		&windowStart,
+		&state.RestartWindowBase,
+	)
+	if err != nil {
+		return state, err
+	}
+	if startedAt.Valid {
+		state.StartedAt = &startedAt.Time
+	}
+	if failedAt.Valid {
+		state.FailedAt = &failedAt.Time
+	}
+	if windowStart.Valid {
+		state.RestartWindowStart = &windowStart.Time
+	}
+
+	return state, nil
+}
+
+// SaveAlgorithmState inserts or replaces the observed state of a client algorithm.
+// There is at most one state row per client and algorithm type.
+func (cr *clientRepository) SaveAlgorithmState(ctx context.Context, state *models.AlgorithmState) error {
+	const op = "repository.client.SaveAlgorithmState"
+
+	query := `
+		INSERT INTO algorithm_state (client_id, algorithm, phase, ready, pod_name, image, reason, started_at, restart_count,
+			last_error, last_synced_at, failed_at, restart_window_started_at, restart_window_base)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (client_id, algorithm) DO UPDATE SET
+			phase = EXCLUDED.phase,
+			ready = EXCLUDED.ready,
+			pod_name = EXCLUDED.pod_name,
+			image = EXCLUDED.image,
+			reason = EXCLUDED.reason,
+			started_at = EXCLUDED.started_at,
+			restart_count = EXCLUDED.restart_count,
+			last_error = EXCLUDED.last_error,
+			last_synced_at = EXCLUDED.last_synced_at,
+			failed_at = EXCLUDED.failed_at,
+			restart_window_started_at = EXCLUDED.restart_window_started_at,
+			restart_window_base = EXCLUDED.restart_window_base
+	`
+
+	_, err := cr.db.ExecContext(ctx, query,
+		state.ClientID,
+		state.Algorithm,
+		state.Phase,
+		state.Ready,
+		state.PodName,
+		state.Image,
+		state.Reason,
+		state.StartedAt,
+		state.RestartCount,
+		state.LastError,
+		state.LastSyncedAt,
+		state.FailedAt,
+		state.RestartWindowStart,
+		state.RestartWindowBase,
+	)
+	if err != nil {
+		cr.log.Errorf("%s: failed to save algorithm state: %v", op, err)
+		return fmt.Errorf("failed to save algorithm state: %w", err)
+	}
+
+	cr.log.Debugf("%s: saved %s state for client ID %d", op, state.Algorithm, state.ClientID)
+
+	return nil
+}
+
+// ResetAlgorithmFailures clears the crash-loop failure marks of the given algorithm types
+// for the client that owns the algorithm status identified by algorithmID,
+// allowing the synchronization to create their pods again.
+func (cr *clientRepository) ResetAlgorithmFailures(ctx context.Context, algorithmID int64, algorithms []string) error {
+	const op = "repository.client.ResetAlgorithmFailures"
+
+	query := `
+		UPDATE algorithm_state
+		SET failed_at = NULL, restart_window_started_at = NULL, restart_window_base = 0
+		WHERE client_id = (SELECT client_id FROM algorithm_status WHERE id = $1)
+			AND algorithm = ANY($2)
+			AND failed_at IS NOT NULL
+	`
+
+	result, err := cr.db.ExecContext(ctx, query, algorithmID, pq.Array(algorithms))
+	if err != nil {
+		cr.log.Errorf("%s: failed to reset algorithm failures: %v", op, err)
+		return fmt.Errorf("failed to reset algorithm failures: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n > 0 {
+		cr.log.Infof("%s: re-enabled %d failed algorithms for algorithm status ID %d", op, n, algorithmID)
+	}
+
+	return nil
+}
+
+// SchedulingOverrides retrieves the per-client scheduling overrides keyed by algorithm type.
+func (cr *clientRepository) SchedulingOverrides(ctx context.Context, clientID int64) (map[string]models.Scheduling, error) {
+	const op = "repository.client.SchedulingOverrides"
+
+	query := `
+		SELECT algorithm, scheduling
+		FROM client_scheduling
+		WHERE client_id = $1
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve scheduling overrides: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve scheduling overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make(map[string]models.Scheduling)
+	for rows.Next() {
+		var algorithm string
+		var data []byte
+		if err := rows.Scan(&algorithm, &data); err != nil {
+			cr.log.Errorf("%s: failed to scan scheduling override row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan scheduling override row: %w", err)
+		}
+
+		scheduling, err := decodeScheduling(data)
+		if err != nil {
+			cr.log.Errorf("%s: failed to decode scheduling override for %s: %v", op, algorithm, err)
+			return nil, err
+		}
+		overrides[algorithm] = scheduling
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over scheduling overrides: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over scheduling overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// SchedulingOverridesByClients retrieves the scheduling overrides of the given clients with a
+// single query, keyed by client ID and algorithm type. Clients without overrides are missing.
+func (cr *clientRepository) SchedulingOverridesByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.Scheduling, error) {
+	const op = "repository.client.SchedulingOverridesByClients"
+
+	query := `
+		SELECT client_id, algorithm, scheduling
+		FROM client_scheduling
+		WHERE client_id = ANY($1)
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, pq.Array(clientIDs))
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve scheduling overrides: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve scheduling overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make(map[int64]map[string]models.Scheduling)
+	for rows.Next() {
+		var clientID int64
+		var algorithm string
+		var data []byte
+		if err := rows.Scan(&clientID, &algorithm, &data); err != nil {
+			cr.log.Errorf("%s: failed to scan scheduling override row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan scheduling override row: %w", err)
+		}
+
+		scheduling, err := decodeScheduling(data)
+		if err != nil {
+			cr.log.Errorf("%s: failed to decode scheduling override for %s of client %d: %v", op, algorithm, clientID, err)
+			return nil, err
+		}
+		if overrides[clientID] == nil {
+			overrides[clientID] = make(map[string]models.Scheduling)
+		}
+		overrides[clientID][algorithm] = scheduling
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over scheduling overrides: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over scheduling overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// decodeScheduling decodes a scheduling override stored as JSON.
+func decodeScheduling(data []byte) (models.Scheduling, error) {
+	var scheduling models.Scheduling
+	if err := json.Unmarshal(data, &scheduling); err != nil {
+		return scheduling, fmt.Errorf("failed to decode scheduling override: %w", err)
+	}
+	return scheduling, nil
+}
+
+// SaveSchedulingOverride inserts or replaces the scheduling override of a client algorithm.
+func (cr *clientRepository) SaveSchedulingOverride(ctx context.Context, clientID int64, algorithm string, scheduling models.Scheduling) error {
+	const op = "repository.client.SaveSchedulingOverride"
+
+	data, err := json.Marshal(scheduling)
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduling override: %w", err)
+	}
+
+	query := `
+		INSERT INTO client_scheduling (client_id, algorithm, scheduling, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (client_id, algorithm) DO UPDATE SET
+			scheduling = EXCLUDED.scheduling,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, clientID, algorithm, data, time.Now()); err != nil {
+		cr.log.Errorf("%s: failed to save scheduling override: %v", op, err)
+		return fmt.Errorf("failed to save scheduling override: %w", err)
+	}
+
+	cr.log.Infof("%s: saved %s scheduling override for client ID %d", op, algorithm, clientID)
+
+	return nil
+}
+
+// DeleteSchedulingOverride removes the scheduling override of a client algorithm,
+// so the defaults of the algorithm type apply again.
+func (cr *clientRepository) DeleteSchedulingOverride(ctx context.Context, clientID int64, algorithm string) error {
+	const op = "repository.client.DeleteSchedulingOverride"
+
+	query := `
+		DELETE FROM client_scheduling
+		WHERE client_id = $1 AND algorithm = $2
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, clientID, algorithm); err != nil {
+		cr.log.Errorf("%s: failed to delete scheduling override: %v", op, err)
+		return fmt.Errorf("failed to delete scheduling override: %w", err)
+	}
+
+	cr.log.Infof("%s: deleted %s scheduling override for client ID %d", op, algorithm, clientID)
+
+	return nil
+}
+
+// AlgorithmParameters retrieves the parameters documents of a client keyed by algorithm type.
+func (cr *clientRepository) AlgorithmParameters(ctx context.Context, clientID int64) (map[string]models.AlgorithmParameters, error) {
+	const op = "repository.client.AlgorithmParameters"
+
+	query := `
+		SELECT client_id, algorithm, parameters, updated_at
+		FROM algorithm_parameters
+		WHERE client_id = $1
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve algorithm parameters: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve algorithm parameters: %w", err)
+	}
+	defer rows.Close()
+
+	parameters := make(map[string]models.AlgorithmParameters)
+	for rows.Next() {
+		var params models.AlgorithmParameters
+		if err := rows.Scan(&params.ClientID, &params.Algorithm, &params.Parameters, &params.UpdatedAt); err != nil {
+			cr.log.Errorf("%s: failed to scan algorithm parameters row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan algorithm parameters row: %w", err)
+		}
+		parameters[params.Algorithm] = params
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over algorithm parameters: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over algorithm parameters: %w", err)
+	}
+
+	return parameters, nil
+}
+
+// AlgorithmParametersByClients retrieves the parameters documents of the given clients with a
+// single query, keyed by client ID and algorithm type. Clients without parameters are missing.
+func (cr *clientRepository) AlgorithmParametersByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmParameters, error) {
+	const op = "repository.client.AlgorithmParametersByClients"
+
+	query := `
+		SELECT client_id, algorithm, parameters, updated_at
+		FROM algorithm_parameters
+		WHERE client_id = ANY($1)
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, pq.Array(clientIDs))
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve algorithm parameters: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve algorithm parameters: %w", err)
+	}
+	defer rows.Close()
+
+	parameters := make(map[int64]map[string]models.AlgorithmParameters)
+	for rows.Next() {
+		var params models.AlgorithmParameters
+		if err := rows.Scan(&params.ClientID, &params.Algorithm, &params.Parameters, &params.UpdatedAt); err != nil {
+			cr.log.Errorf("%s: failed to scan algorithm parameters row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan algorithm parameters row: %w", err)
+		}
+		if parameters[params.ClientID] == nil {
+			parameters[params.ClientID] = make(map[string]models.AlgorithmParameters)
+		}
+		parameters[params.ClientID][params.Algorithm] = params
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over algorithm parameters: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over algorithm parameters: %w", err)
+	}
+
+	return parameters, nil
+}
+
+// SaveAlgorithmParameters inserts or replaces the parameters document of a client algorithm.
+// The update time is written back to params.
+func (cr *clientRepository) SaveAlgorithmParameters(ctx context.Context, params *models.AlgorithmParameters) error {
+	const op = "repository.client.SaveAlgorithmParameters"
+
+	params.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO algorithm_parameters (client_id, algorithm, parameters, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (client_id, algorithm) DO UPDATE SET
+			parameters = EXCLUDED.parameters,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, params.ClientID, params.Algorithm, []byte(params.Parameters), params.UpdatedAt); err != nil {
+		cr.log.Errorf("%s: failed to save algorithm parameters: %v", op, err)
+		return fmt.Errorf("failed to save algorithm parameters: %w", err)
+	}
+
+	cr.log.Infof("%s: saved %s parameters for client ID %d", op, params.Algorithm, params.ClientID)
+
+	return nil
+}
+
+// Pause retrieves the active pause of a client.
+// It returns nil if the client is not paused or its pause has expired.
+func (cr *clientRepository) Pause(ctx context.Context, clientID int64) (*models.ClientPause, error) {
+	const op = "repository.client.Pause"
+
+	query := `
+		SELECT client_id, reason, actor, paused_at, expires_at
+		FROM client_pauses
+		WHERE client_id = $1 AND (expires_at IS NULL OR expires_at > now())
+	`
+
+	var pause models.ClientPause
+	err := cr.db.QueryRowContext(ctx, query, clientID).Scan(
+		&pause.ClientID,
+		&pause.Reason,
+		&pause.Actor,
+		&pause.PausedAt,
+		&pause.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		cr.log.Errorf("%s: failed to retrieve pause: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve pause: %w", err)
+	}
+
+	return &pause, nil
+}
+
+// SavePause pauses a client, replacing any previous pause of the client.
+func (cr *clientRepository) SavePause(ctx context.Context, pause *models.ClientPause) error {
+	const op = "repository.client.SavePause"
+
+	query := `
+		INSERT INTO client_pauses (client_id, reason, actor, paused_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (client_id) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			actor = EXCLUDED.actor,
+			paused_at = EXCLUDED.paused_at,
+			expires_at = EXCLUDED.expires_at
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, pause.ClientID, pause.Reason, pause.Actor, pause.PausedAt, pause.ExpiresAt); err != nil {
+		cr.log.Errorf("%s: failed to save pause: %v", op, err)
+		return fmt.Errorf("failed to save pause: %w", err)
+	}
+
+	cr.log.Infof("%s: client ID %d paused by %s: %s", op, pause.ClientID, pause.Actor, pause.Reason)
+
+	return nil
+}
+
+// DeletePause resumes a client. Deleting the pause of a client that is not paused is a no-op.
+func (cr *clientRepository) DeletePause(ctx context.Context, clientID int64) error {
+	const op = "repository.client.DeletePause"
+
+	query := `
+		DELETE FROM client_pauses
+		WHERE client_id = $1
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, clientID); err != nil {
+		cr.log.Errorf("%s: failed to delete pause: %v", op, err)
+		return fmt.Errorf("failed to delete pause: %w", err)
+	}
+
+	cr.log.Infof("%s: resumed client ID %d", op, clientID)
+
+	return nil
+}
+
+// AlgorithmWindows retrieves the run windows of a client keyed by algorithm type.
+func (cr *clientRepository) AlgorithmWindows(ctx context.Context, clientID int64) (map[string]models.AlgorithmWindow, error) {
+	const op = "repository.client.AlgorithmWindows"
+
+	query := `
+		SELECT client_id, algorithm, cron, timezone, calendar, start_before, stop_after, updated_at
+		FROM algorithm_windows
+		WHERE client_id = $1
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve algorithm windows: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve algorithm windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := make(map[string]models.AlgorithmWindow)
+	for rows.Next() {
+		window, err := scanAlgorithmWindow(rows)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan algorithm window row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan algorithm window row: %w", err)
+		}
+		windows[window.Algorithm] = window
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over algorithm windows: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over algorithm windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// AlgorithmWindowsByClients retrieves the run windows of the given clients with a single query,
+// keyed by client ID and algorithm type. Clients without run windows are missing.
+func (cr *clientRepository) AlgorithmWindowsByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmWindow, error) {
+	const op = "repository.client.AlgorithmWindowsByClients"
+
+	query := `
+		SELECT client_id, algorithm, cron, timezone, calendar, start_before, stop_after, updated_at
+		FROM algorithm_windows
+		WHERE client_id = ANY($1)
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query, pq.Array(clientIDs))
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve algorithm windows: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve algorithm windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := make(map[int64]map[string]models.AlgorithmWindow)
+	for rows.Next() {
+		window, err := scanAlgorithmWindow(rows)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan algorithm window row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan algorithm window row: %w", err)
+		}
+		if windows[window.ClientID] == nil {
+			windows[window.ClientID] = make(map[string]models.AlgorithmWindow)
+		}
+		windows[window.ClientID][window.Algorithm] = window
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over algorithm windows: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over algorithm windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// scanAlgorithmWindow scans an algorithm window row selected by AlgorithmWindows.
+func scanAlgorithmWindow(rows *sql.Rows) (models.AlgorithmWindow, error) {
+	var window models.AlgorithmWindow
+	var cron, calendar sql.NullString
+	err := rows.Scan(
+		&window.ClientID,
+		&window.Algorithm,
+		&cron,
+		&window.Timezone,
+		&calendar,
+		&window.StartBefore,
+		&window.StopAfter,
+		&window.UpdatedAt,
+	)
+	window.Cron = cron.String
+	window.Calendar = calendar.String
+
+	return window, err
+}
+
+// SaveAlgorithmWindow inserts or replaces the run window of a client algorithm.
+// The update time is written back to the window.
+func (cr *clientRepository) SaveAlgorithmWindow(ctx context.Context, window *models.AlgorithmWindow) error {
+	const op = "repository.client.SaveAlgorithmWindow"
+
+	query := `
+		INSERT INTO algorithm_windows (client_id, algorithm, cron, timezone, calendar, start_before, stop_after, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (client_id, algorithm) DO UPDATE SET
+			cron = EXCLUDED.cron,
+			timezone = EXCLUDED.timezone,
+			calendar = EXCLUDED.calendar,
+			start_before = EXCLUDED.start_before,
+			stop_after = EXCLUDED.stop_after,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	window.UpdatedAt = time.Now()
+	_, err := cr.db.ExecContext(ctx, query,
+		window.ClientID,
+		window.Algorithm,
+		nullString(window.Cron),
+		window.Timezone,
+		nullString(window.Calendar),
+		window.StartBefore,
+		window.StopAfter,
+		window.UpdatedAt,
+	)
+	if err != nil {
+		cr.log.Errorf("%s: failed to save algorithm window: %v", op, err)
+		return fmt.Errorf("failed to save algorithm window: %w", err)
+	}
+
+	cr.log.Infof("%s: saved %s window for client ID %d", op, window.Algorithm, window.ClientID)
+
+	return nil
+}
+
+// DeleteAlgorithmWindow removes the run window of a client algorithm,
+// so the algorithm runs whenever it is enabled.
+func (cr *clientRepository) DeleteAlgorithmWindow(ctx context.Context, clientID int64, algorithm string) error {
+	const op = "repository.client.DeleteAlgorithmWindow"
+
+	query := `
+		DELETE FROM algorithm_windows
+		WHERE client_id = $1 AND algorithm = $2
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, clientID, algorithm); err != nil {
+		cr.log.Errorf("%s: failed to delete algorithm window: %v", op, err)
+		return fmt.Errorf("failed to delete algorithm window: %w", err)
+	}
+
+	cr.log.Infof("%s: deleted %s window for client ID %d", op, algorithm, clientID)
+
+	return nil
+}
+
+// nullString converts an empty string to NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
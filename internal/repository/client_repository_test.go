@@ -2,6 +2,8 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
+	"test-task/internal/domain"
 	"test-task/internal/models"
 	"test-task/internal/repository"
 	"testing"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,7 +48,7 @@ func TestCreate(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO clients").
-		WithArgs(client.ClientName, client.Version, client.Image, client.CPU, client.Memory, client.Priority, client.NeedRestart, client.SpawnedAt, client.CreatedAt, client.UpdatedAt).
+		WithArgs(client.ClientName, client.Version, client.Image, client.CPU, client.Memory, client.Priority, client.NeedRestart, client.ClusterID, client.SpawnedAt, client.CreatedAt, client.UpdatedAt).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectQuery("INSERT INTO algorithm_status").
 		WithArgs(1, algorithm.VWAP, algorithm.TWAP, algorithm.HFT).
@@ -89,8 +92,8 @@ func TestClientByID(t *testing.T) {
 	mock.ExpectQuery("SELECT \\* from clients WHERE id = \\$1").
 		WithArgs(1).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "spawned_at", "created_at", "updated_at"}).
-				AddRow(expectedClient.ID, expectedClient.ClientName, expectedClient.Version, expectedClient.Image, expectedClient.CPU, expectedClient.Memory, expectedClient.Priority, expectedClient.NeedRestart, expectedClient.SpawnedAt, expectedClient.CreatedAt, expectedClient.UpdatedAt))
+			sqlmock.NewRows([]string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "cluster_id", "spawned_at", "created_at", "updated_at", "revision"}).
+				AddRow(expectedClient.ID, expectedClient.ClientName, expectedClient.Version, expectedClient.Image, expectedClient.CPU, expectedClient.Memory, expectedClient.Priority, expectedClient.NeedRestart, expectedClient.ClusterID, expectedClient.SpawnedAt, expectedClient.CreatedAt, expectedClient.UpdatedAt, expectedClient.Revision))
 
 	client, err := repo.ClientByID(1)
 	assert.NoError(t, err)
@@ -98,6 +101,80 @@ func TestClientByID(t *testing.T) {
 	mock.ExpectationsWereMet()
 }
 
+// TestClientByName tests fetching a client by its name regardless of case.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that names are compared
+// in lower case among the clients that are not deleted and that a missing name is reported as not found.
+func TestClientByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	query := "SELECT (.+) FROM clients WHERE lower\\(client_name\\) = lower\\(\\$1\\) AND deleted_at IS NULL"
+	mock.ExpectQuery(query).
+		WithArgs("test client").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "cluster_id", "spawned_at", "created_at", "updated_at", "revision"}).
+			AddRow(1, "Test Client", 1, "image1", "1", "1Gi", 1.0, false, nil, now, now, now, 1))
+	mock.ExpectQuery(query).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	client, err := repo.ClientByName(context.Background(), "test client")
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Client", client.ClientName)
+
+	_, err = repo.ClientByName(context.Background(), "missing")
+	assert.ErrorIs(t, err, repository.ErrClientNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestClientNameTaken tests that a violation of the unique client name index is reported as a conflict.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that creating, renaming and
+// restoring a client with a taken name return ErrClientNameTaken, while other errors are passed on.
+func TestClientNameTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	taken := &pq.Error{Code: "23505", Constraint: "idx_clients_client_name_unique"}
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO clients").
+		ExpectQuery().
+		WillReturnError(taken)
+	mock.ExpectRollback()
+	mock.ExpectExec("UPDATE clients SET client_name = \\$1").
+		WithArgs("Test Client", sqlmock.AnyArg(), 2).
+		WillReturnError(taken)
+	mock.ExpectExec("UPDATE clients SET deleted_at = NULL").
+		WithArgs(sqlmock.AnyArg(), 3).
+		WillReturnError(taken)
+	mock.ExpectExec("UPDATE clients SET client_name = \\$1").
+		WithArgs("Other", sqlmock.AnyArg(), 2).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "clients_pkey"})
+
+	_, err = repo.Create(&models.Client{ClientName: "test client"}, &models.AlgorithmStatus{})
+	assert.ErrorIs(t, err, repository.ErrClientNameTaken)
+	assert.ErrorIs(t, err, domain.Conflict)
+
+	err = repo.Update(2, map[string]interface{}{"client_name": "Test Client"})
+	assert.ErrorIs(t, err, repository.ErrClientNameTaken)
+
+	err = repo.Restore(3)
+	assert.ErrorIs(t, err, repository.ErrClientNameTaken)
+
+	err = repo.Update(2, map[string]interface{}{"client_name": "Other"})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrClientNameTaken)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestUpdate tests updating client information in the database.
 //
 // It mocks SQL database interactions using sqlmock. The test verifies the correct execution
@@ -114,7 +191,7 @@ func TestUpdate(t *testing.T) {
 		"priority":    2,
 	}
 
-	mock.ExpectExec("UPDATE clients SET client_name = \\$1, priority = \\$2, updated_at = \\$3 WHERE id = \\$4").
+	mock.ExpectExec("UPDATE clients SET client_name = \\$1, priority = \\$2, revision = revision \\+ 1, updated_at = \\$3 WHERE id = \\$4").
 		WithArgs("UpdatedClient", 2, sqlmock.AnyArg(), 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -123,10 +200,104 @@ func TestUpdate(t *testing.T) {
 	mock.ExpectationsWereMet()
 }
 
+// TestUpdateIfRevision tests updating a client only at the expected revision.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the revision
+// is part of the WHERE clause and that an update affecting no rows reports false.
+func TestUpdateIfRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	query := "UPDATE clients SET image = \\$1, revision = revision \\+ 1, updated_at = \\$2 WHERE id = \\$3 AND revision = \\$4"
+	mock.ExpectExec(query).
+		WithArgs("image:v2", sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).
+		WithArgs("image:v2", sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	updated, err := repo.UpdateIfRevision(1, 3, map[string]interface{}{"image": "image:v2"})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = repo.UpdateIfRevision(1, 3, map[string]interface{}{"image": "image:v2"})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdate_RejectsUnknownColumns tests that columns outside the whitelist are rejected
+// before a query is sent, so that map keys cannot inject SQL.
+func TestUpdate_RejectsUnknownColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	err = repo.Update(1, map[string]interface{}{"client_name = 'x', id": 2})
+	assert.Error(t, err)
+
+	err = repo.Update(1, map[string]interface{}{"created_at": time.Now()})
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestListClients tests listing a filtered page of clients after a cursor.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the total is
+// counted without the cursor, that the page is ordered by the sort key with the ID breaking
+// ties, and that LIKE wildcards in the name prefix are escaped.
+func TestListClients(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	priorityMin := 1.5
+	needRestart := false
+	query := models.ClientQuery{
+		Filter: models.ClientFilter{
+			NamePrefix:  "acme_",
+			PriorityMin: &priorityMin,
+			NeedRestart: &needRestart,
+			Algorithm:   models.AlgorithmTWAP,
+		},
+		Sort:       models.ClientSortPriority,
+		Descending: true,
+		After:      &models.ClientCursor{Value: 7.0, ID: 3},
+		Limit:      3,
+	}
+
+	filter := "WHERE c.deleted_at IS NULL AND c.client_name LIKE \\$1 AND c.priority >= \\$2 AND c.need_restart = \\$3 AND EXISTS \\(SELECT 1 FROM algorithm_status a WHERE a.client_id = c.id AND a.twap\\)"
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM clients c "+filter).
+		WithArgs(`acme\_%`, 1.5, false).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery("SELECT (.+) FROM clients c "+filter+" AND \\(c.priority, c.id\\) < \\(\\$4, \\$5\\) ORDER BY c.priority DESC, c.id DESC LIMIT \\$6").
+		WithArgs(`acme\_%`, 1.5, false, 7.0, int64(3), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "cluster_id", "spawned_at", "created_at", "updated_at", "revision", "deleted_at"}).
+			AddRow(5, "acme_eu", 1, "image1", "1", "1Gi", 7.0, false, nil, now, now, now, 2, nil))
+
+	clients, total, err := repo.ListClients(context.Background(), query)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(12), total)
+	assert.Equal(t, []models.Client{
+		{ID: 5, ClientName: "acme_eu", Version: 1, Image: "image1", CPU: "1", Memory: "1Gi", Priority: 7.0, SpawnedAt: now, CreatedAt: now, UpdatedAt: now, Revision: 2},
+	}, clients)
+}
+
 // TestDelete tests deleting a client from the database.
 //
-// It mocks SQL database interactions using sqlmock. The test verifies the correct execution
-// of a delete query to remove a client record by its ID.
+// It mocks SQL database interactions using sqlmock. The test verifies that the client record
+// is kept and marked as deleted by its ID.
 func TestDelete(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -134,8 +305,8 @@ func TestDelete(t *testing.T) {
 
 	repo := repository.NewClientRepository(db)
 
-	mock.ExpectExec("DELETE FROM clients WHERE id = \\$1").
-		WithArgs(1).
+	mock.ExpectExec("UPDATE clients SET deleted_at = \\$1, revision = revision \\+ 1, updated_at = \\$1 WHERE id = \\$2 AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	err = repo.Delete(1)
@@ -143,6 +314,148 @@ func TestDelete(t *testing.T) {
 	mock.ExpectationsWereMet()
 }
 
+// TestNotFound tests that a missing client or algorithm status is reported as a typed not-found error.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that lookups without
+// rows and updates or deletions affecting no rows return errors of the NotFound kind.
+func TestNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM clients WHERE id = \\$1").
+		WithArgs(7).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_status a JOIN clients c ON c.id = a.client_id WHERE a.client_id = \\$1 AND c.deleted_at IS NULL").
+		WithArgs(7).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE clients SET priority = \\$1, revision = revision \\+ 1, updated_at = \\$2 WHERE id = \\$3").
+		WithArgs(2, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE clients SET deleted_at = (.+) WHERE id = \\$2 AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock_shared\\(\\$1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE algorithm_status SET hft = \\$1 WHERE id = \\$2 (.+) AND NOT EXISTS").
+		WithArgs(true, 7, pq.Array([]string{"hft"})).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM kill_switches").
+		WithArgs(pq.Array([]string{"hft"})).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	client, err := repo.ClientByID(7)
+	assert.Nil(t, client)
+	assert.ErrorIs(t, err, repository.ErrClientNotFound)
+
+	algorithm, err := repo.AlgorithmByClientID(context.Background(), 7)
+	assert.Nil(t, algorithm)
+	assert.ErrorIs(t, err, repository.ErrAlgorithmStatusNotFound)
+
+	assert.ErrorIs(t, repo.Update(7, map[string]interface{}{"priority": 2}), repository.ErrClientNotFound)
+	assert.ErrorIs(t, repo.Delete(7), domain.NotFound)
+	assert.ErrorIs(t, repo.UpdateAlgorithmStatus(7, map[string]interface{}{"hft": true}), repository.ErrAlgorithmStatusNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteIfRevision tests deleting a client only at the expected revision.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that a deletion
+// affecting no rows reports false.
+func TestDeleteIfRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	mock.ExpectExec("UPDATE clients SET deleted_at = (.+) WHERE id = \\$2 AND revision = \\$3 AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.DeleteIfRevision(1, 3)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRestore tests restoring a deleted client.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that only a deleted
+// client is restored and that restoring a client that is not deleted is reported as not found.
+func TestRestore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	query := "UPDATE clients SET deleted_at = NULL, revision = revision \\+ 1, updated_at = \\$1 WHERE id = \\$2 AND deleted_at IS NOT NULL"
+	mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Restore(1))
+	assert.ErrorIs(t, repo.Restore(2), repository.ErrDeletedClientNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPurgeDeletedClients tests removing the clients deleted before the retention cutoff.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that only deleted
+// clients older than the cutoff are removed and that their number is returned.
+func TestPurgeDeletedClients(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	mock.ExpectExec("DELETE FROM clients WHERE deleted_at IS NOT NULL AND deleted_at < \\$1").
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	purged, err := repo.PurgeDeletedClients(context.Background(), cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeletedClientsWithPods tests fetching the deleted clients whose pods may still run.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that only deleted
+// clients with an algorithm state other than deleted are read, including their deletion time.
+func TestDeletedClientsWithPods(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(append(append([]string(nil), clientColumns...), "deleted_at")).
+		AddRow(4, "Client4", 1, "image", "2", "1Gi", 1, false, nil, now, now, now, 3, now)
+	mock.ExpectQuery("SELECT (.+) FROM clients c WHERE c.deleted_at IS NOT NULL AND EXISTS \\(SELECT 1 FROM algorithm_state s WHERE s.client_id = c.id AND s.phase <> \\$1\\)").
+		WithArgs(models.PhaseDeleted).
+		WillReturnRows(rows)
+
+	clients, err := repo.DeletedClientsWithPods(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Client{
+		{ID: 4, ClientName: "Client4", Version: 1, Image: "image", CPU: "2", Memory: "1Gi", Priority: 1, SpawnedAt: now, CreatedAt: now, UpdatedAt: now, Revision: 3, DeletedAt: &now},
+	}, clients)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestClients tests fetching a list of clients from the database.
 //
 // It mocks SQL database interactions using sqlmock. The test verifies the correct retrieval
@@ -191,12 +504,12 @@ func TestClients(t *testing.T) {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "spawned_at", "created_at", "updated_at"})
+	rows := sqlmock.NewRows([]string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "cluster_id", "spawned_at", "created_at", "updated_at", "revision"})
 	for _, client := range expectedClients {
-		rows.AddRow(client.ID, client.ClientName, client.Version, client.Image, client.CPU, client.Memory, client.Priority, client.NeedRestart, client.SpawnedAt, client.CreatedAt, client.UpdatedAt)
+		rows.AddRow(client.ID, client.ClientName, client.Version, client.Image, client.CPU, client.Memory, client.Priority, client.NeedRestart, client.ClusterID, client.SpawnedAt, client.CreatedAt, client.UpdatedAt, client.Revision)
 	}
 
-	mock.ExpectQuery("SELECT id, client_name, version, image, cpu, memory, priority, need_restart, spawned_at, created_at, updated_at FROM clients").
+	mock.ExpectQuery("SELECT id, client_name, version, image, cpu, memory, priority, need_restart, cluster_id, spawned_at, created_at, updated_at, revision FROM clients").
 		WillReturnRows(rows)
 
 	clients, err := repo.Clients()
@@ -266,11 +579,260 @@ func TestUpdateAlgorithmStatus(t *testing.T) {
 		"vwap": true,
 	}
 
-	mock.ExpectExec("UPDATE algorithm_status SET vwap = \\$1 WHERE id = \\$2").
-		WithArgs(true, 1).
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock_shared\\(\\$1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE algorithm_status SET vwap = \\$1 WHERE id = \\$2 (.+) AND NOT EXISTS \\(SELECT 1 FROM kill_switches WHERE released_at IS NULL AND algorithm = ANY\\(\\$3\\)\\)").
+		WithArgs(true, 1, pq.Array([]string{"vwap"})).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	err = repo.UpdateAlgorithmStatus(1, updateParams)
 	assert.NoError(t, err)
-	mock.ExpectationsWereMet()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateAlgorithmStatus_KillSwitch tests that enabling an algorithm whose kill switch
+// is engaged is rejected inside the update, while disabling it needs no kill switch lock.
+func TestUpdateAlgorithmStatus_KillSwitch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock_shared\\(\\$1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE algorithm_status SET hft = \\$1 WHERE id = \\$2 (.+) AND NOT EXISTS").
+		WithArgs(true, 1, pq.Array([]string{"hft"})).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM kill_switches").
+		WithArgs(pq.Array([]string{"hft"})).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err = repo.UpdateAlgorithmStatus(1, map[string]interface{}{"hft": true})
+	assert.ErrorIs(t, err, repository.ErrKillSwitchEngaged)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock_shared\\(\\$1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE algorithm_status SET twap = \\$1 WHERE id = \\$2").
+		WithArgs(true, 1, pq.Array([]string{"twap"})).
+		WillReturnError(&pq.Error{Code: "23514", Message: "kill switch engaged"})
+	mock.ExpectRollback()
+
+	err = repo.UpdateAlgorithmStatus(1, map[string]interface{}{"twap": true})
+	assert.ErrorIs(t, err, repository.ErrKillSwitchEngaged)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE algorithm_status SET hft = \\$1 WHERE id = \\$2 AND client_id IN \\(SELECT id FROM clients WHERE deleted_at IS NULL\\)$").
+		WithArgs(false, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.UpdateAlgorithmStatus(1, map[string]interface{}{"hft": false})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAlgorithmStates tests fetching the observed algorithm state of a client.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that state rows are
+// scanned correctly, including NULL timestamps for pods that never started or never failed.
+func TestAlgorithmStates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	startedAt := time.Now()
+	syncedAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"client_id", "algorithm", "phase", "ready", "pod_name", "image", "reason", "started_at", "restart_count", "last_error", "last_synced_at", "failed_at", "restart_window_started_at", "restart_window_base"}).
+		AddRow(1, "hft", "Failed", false, "hft-1", "image1", "RestartThresholdExceeded", nil, 7, "pod restarted 7 times", syncedAt, syncedAt, startedAt, 0).
+		AddRow(1, "vwap", "Running", true, "vwap-1", "image1", "", startedAt, 0, "", syncedAt, nil, nil, 0)
+
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_state WHERE client_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	states, err := repo.AlgorithmStates(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []models.AlgorithmState{
+		{ClientID: 1, Algorithm: "hft", Phase: "Failed", PodName: "hft-1", Image: "image1", Reason: "RestartThresholdExceeded", RestartCount: 7, LastError: "pod restarted 7 times", LastSyncedAt: syncedAt, FailedAt: &syncedAt, RestartWindowStart: &startedAt},
+		{ClientID: 1, Algorithm: "vwap", Phase: "Running", Ready: true, PodName: "vwap-1", Image: "image1", StartedAt: &startedAt, LastSyncedAt: syncedAt},
+	}, states)
+}
+
+// TestSaveAlgorithmState tests upserting the observed state of a client algorithm.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the state is
+// written with an INSERT ... ON CONFLICT statement keyed by client and algorithm.
+func TestSaveAlgorithmState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	state := &models.AlgorithmState{
+		ClientID:     1,
+		Algorithm:    "vwap",
+		Phase:        "Running",
+		Ready:        true,
+		PodName:      "vwap-1",
+		Image:        "image1",
+		LastSyncedAt: time.Now(),
+	}
+
+	mock.ExpectExec("INSERT INTO algorithm_state (.+) ON CONFLICT \\(client_id, algorithm\\) DO UPDATE").
+		WithArgs(state.ClientID, state.Algorithm, state.Phase, state.Ready, state.PodName, state.Image, state.Reason, state.StartedAt,
+			state.RestartCount, state.LastError, state.LastSyncedAt, state.FailedAt, state.RestartWindowStart, state.RestartWindowBase).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveAlgorithmState(context.Background(), state)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestResetAlgorithmFailures tests clearing crash-loop failure marks when algorithms are re-enabled.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that only the given
+// algorithm types of the client owning the algorithm status are reset.
+func TestResetAlgorithmFailures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	mock.ExpectExec("UPDATE algorithm_state SET failed_at = NULL").
+		WithArgs(1, pq.Array([]string{"hft"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.ResetAlgorithmFailures(context.Background(), 1, []string{"hft"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveAlgorithmParameters tests upserting the parameters document of a client algorithm.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the document is
+// written with an INSERT ... ON CONFLICT statement keyed by client and algorithm.
+func TestSaveAlgorithmParameters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	params := &models.AlgorithmParameters{ClientID: 1, Algorithm: "vwap", Parameters: []byte(`{"participation_rate":0.1}`)}
+
+	mock.ExpectExec("INSERT INTO algorithm_parameters (.+) ON CONFLICT \\(client_id, algorithm\\) DO UPDATE").
+		WithArgs(1, "vwap", []byte(`{"participation_rate":0.1}`), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveAlgorithmParameters(context.Background(), params)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.False(t, params.UpdatedAt.IsZero())
+}
+
+// TestPause tests that only an active pause of a client is returned.
+func TestPause(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM client_pauses WHERE client_id = \\$1 AND \\(expires_at IS NULL OR expires_at > now\\(\\)\\)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"client_id", "reason", "actor", "paused_at", "expires_at"}).
+			AddRow(1, "debugging", "alice", now, nil))
+	mock.ExpectQuery("SELECT (.+) FROM client_pauses").
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	pause, err := repo.Pause(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice", PausedAt: now}, pause)
+
+	pause, err = repo.Pause(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Nil(t, pause)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSavePause tests that pausing a client replaces its previous pause.
+func TestSavePause(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	pause := &models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice", PausedAt: now, ExpiresAt: &expiresAt}
+
+	mock.ExpectExec("INSERT INTO client_pauses (.+) ON CONFLICT \\(client_id\\) DO UPDATE").
+		WithArgs(1, "debugging", "alice", now, &expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SavePause(context.Background(), pause)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveAlgorithmWindow tests upserting the run window of a client algorithm.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the unset one
+// of cron and calendar is written as NULL.
+func TestSaveAlgorithmWindow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	window := &models.AlgorithmWindow{ClientID: 1, Algorithm: "twap", Calendar: "nyse", StartBefore: 15, StopAfter: 5}
+
+	mock.ExpectExec("INSERT INTO algorithm_windows (.+) ON CONFLICT \\(client_id, algorithm\\) DO UPDATE").
+		WithArgs(1, "twap", nil, "", "nyse", 15, 5, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveAlgorithmWindow(context.Background(), window)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.False(t, window.UpdatedAt.IsZero())
+}
+
+// TestAlgorithmWindows tests fetching the run windows of a client keyed by algorithm.
+func TestAlgorithmWindows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_windows WHERE client_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"client_id", "algorithm", "cron", "timezone", "calendar", "start_before", "stop_after", "updated_at"}).
+			AddRow(1, "vwap", "* 9-15 * * MON-FRI", "America/New_York", nil, 0, 0, now).
+			AddRow(1, "twap", nil, "", "nyse", 15, 5, now))
+
+	windows, err := repo.AlgorithmWindows(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, map[string]models.AlgorithmWindow{
+		"vwap": {ClientID: 1, Algorithm: "vwap", Cron: "* 9-15 * * MON-FRI", Timezone: "America/New_York", UpdatedAt: now},
+		"twap": {ClientID: 1, Algorithm: "twap", Calendar: "nyse", StartBefore: 15, StopAfter: 5, UpdatedAt: now},
+	}, windows)
 }
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+)
+
+type ClusterRepository interface {
+	Create(ctx context.Context, cluster *models.Cluster) (int64, error)
+	ClusterByID(ctx context.Context, id int64) (*models.Cluster, error)
+	Clusters(ctx context.Context) ([]models.Cluster, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type clusterRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewClusterRepository(db *sql.DB) ClusterRepository {
+	log := logger.GetLogger()
+	return &clusterRepository{db: db, log: log}
+}
+
+// Create inserts a new deployment target cluster and returns its ID.
+func (cr *clusterRepository) Create(ctx context.Context, cluster *models.Cluster) (int64, error) {
+	const op = "repository.cluster.Create"
+
+	query := `
+		INSERT INTO clusters (name, context, api_endpoint, credentials_ref)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := cr.db.QueryRowContext(ctx, query,
+		cluster.Name,
+		cluster.Context,
+		cluster.APIEndpoint,
+		cluster.CredentialsRef,
+	).Scan(&cluster.ID, &cluster.CreatedAt, &cluster.UpdatedAt)
+	if err != nil {
+		cr.log.Errorf("%s: failed to insert cluster: %v", op, err)
+		return 0, fmt.Errorf("failed to insert cluster: %w", err)
+	}
+
+	cr.log.Infof("%s: cluster %s created with ID %d", op, cluster.Name, cluster.ID)
+
+	return cluster.ID, nil
+}
+
+// ClusterByID retrieves a cluster by its ID.
+// It returns a pointer to the cluster object if found, or nil if not found.
+func (cr *clusterRepository) ClusterByID(ctx context.Context, id int64) (*models.Cluster, error) {
+	const op = "repository.cluster.ClusterByID"
+
+	query := `
+		SELECT id, name, context, api_endpoint, credentials_ref, created_at, updated_at
+		FROM clusters
+		WHERE id = $1
+	`
+
+	var cluster models.Cluster
+	err := cr.db.QueryRowContext(ctx, query, id).Scan(
+		&cluster.ID,
+		&cluster.Name,
+		&cluster.Context,
+		&cluster.APIEndpoint,
+		&cluster.CredentialsRef,
+		&cluster.CreatedAt,
+		&cluster.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cr.log.Debugf("%s: cluster with ID %d not found", op, id)
+			return nil, nil
+		}
+		cr.log.Errorf("%s: failed to get cluster: %v", op, err)
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	return &cluster, nil
+}
+
+// Clusters retrieves all clusters ordered by name.
+func (cr *clusterRepository) Clusters(ctx context.Context) ([]models.Cluster, error) {
+	const op = "repository.cluster.Clusters"
+
+	query := `
+		SELECT id, name, context, api_endpoint, credentials_ref, created_at, updated_at
+		FROM clusters
+		ORDER BY name
+	`
+
+	rows, err := cr.db.QueryContext(ctx, query)
+	if err != nil {
+		cr.log.Errorf("%s: failed to retrieve clusters: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve clusters: %w", err)
+	}
+	defer rows.Close()
+
+	clusters := make([]models.Cluster, 0)
+	for rows.Next() {
+		var cluster models.Cluster
+		err := rows.Scan(
+			&cluster.ID,
+			&cluster.Name,
+			&cluster.Context,
+			&cluster.APIEndpoint,
+			&cluster.CredentialsRef,
+			&cluster.CreatedAt,
+			&cluster.UpdatedAt,
+		)
+		if err != nil {
+			cr.log.Errorf("%s: failed to scan cluster row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan cluster row: %w", err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	if err := rows.Err(); err != nil {
+		cr.log.Errorf("%s: error during iteration over clusters: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over clusters: %w", err)
+	}
+
+	cr.log.Debugf("%s: retrieved %d clusters", op, len(clusters))
+
+	return clusters, nil
+}
+
+// Delete deletes a cluster. It fails while clients are still assigned to the cluster.
+func (cr *clusterRepository) Delete(ctx context.Context, id int64) error {
+	const op = "repository.cluster.Delete"
+
+	query := `
+		DELETE FROM clusters
+		WHERE id = $1
+	`
+
+	if _, err := cr.db.ExecContext(ctx, query, id); err != nil {
+		cr.log.Errorf("%s: failed to delete cluster: %v", op, err)
+		return fmt.Errorf("failed to delete cluster: %w", err)
+	}
+
+	cr.log.Infof("%s: cluster with ID %d deleted successfully", op, id)
+
+	return nil
+}
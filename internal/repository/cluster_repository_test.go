@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateCluster tests registering a deployment cluster.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the cluster
+// is inserted and that the generated ID and timestamps are written back to the cluster.
+func TestCreateCluster(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClusterRepository(db)
+
+	now := time.Now()
+	cluster := &models.Cluster{Name: "eu-west", Context: "eu-west-admin", APIEndpoint: "https://10.0.0.1:6443", CredentialsRef: "/etc/algosync/eu-west.kubeconfig"}
+
+	mock.ExpectQuery("INSERT INTO clusters").
+		WithArgs(cluster.Name, cluster.Context, cluster.APIEndpoint, cluster.CredentialsRef).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, now, now))
+
+	id, err := repo.Create(context.Background(), cluster)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(3), id)
+	assert.Equal(t, now, cluster.CreatedAt)
+}
+
+// TestClusters tests fetching all registered clusters.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies the correct retrieval
+// of cluster records ordered by name.
+func TestClusters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClusterRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "context", "api_endpoint", "credentials_ref", "created_at", "updated_at"}).
+		AddRow(2, "eu-west", "eu-west-admin", "", "", now, now).
+		AddRow(1, "us-east", "", "https://10.1.0.1:6443", "/etc/algosync/us-east.kubeconfig", now, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM clusters ORDER BY name").WillReturnRows(rows)
+
+	clusters, err := repo.Clusters(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []models.Cluster{
+		{ID: 2, Name: "eu-west", Context: "eu-west-admin", CreatedAt: now, UpdatedAt: now},
+		{ID: 1, Name: "us-east", APIEndpoint: "https://10.1.0.1:6443", CredentialsRef: "/etc/algosync/us-east.kubeconfig", CreatedAt: now, UpdatedAt: now},
+	}, clusters)
+}
@@ -0,0 +1,295 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var clientColumns = []string{"id", "client_name", "version", "image", "cpu", "memory", "priority", "need_restart", "cluster_id", "spawned_at", "created_at", "updated_at", "revision"}
+
+var desiredStateColumns = append(append([]string(nil), clientColumns...), "id", "vwap", "twap", "hft", "reason", "actor", "paused_at", "expires_at")
+
+// benchmarkRoundTrip simulates the network round trip of a database query.
+const benchmarkRoundTrip = 200 * time.Microsecond
+
+// TestDesiredStates tests fetching clients joined with their algorithm status and pause.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that clients, their
+// algorithm statuses and active pauses are read with a single query after the cursor, and
+// that clients without an algorithm status or pause are returned with nil ones.
+func TestDesiredStates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(desiredStateColumns).
+		AddRow(11, "Client11", 1, "image1", "2", "1Gi", 1, false, nil, now, now, now, 2, 5, true, false, true, nil, nil, nil, nil).
+		AddRow(12, "Client12", 1, "image2", "2", "1Gi", 1, false, 3, now, now, now, 1, nil, nil, nil, nil, "debugging", "alice", now, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM clients c LEFT JOIN algorithm_status a ON a.client_id = c.id LEFT JOIN client_pauses p ON (.+) WHERE c.id > \\$1 AND c.deleted_at IS NULL ORDER BY c.id LIMIT \\$2").
+		WithArgs(10, 2).
+		WillReturnRows(rows)
+
+	states, err := repo.DesiredStates(context.Background(), 10, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	clusterID := int64(3)
+	assert.Equal(t, []models.DesiredState{
+		{
+			Client:    models.Client{ID: 11, ClientName: "Client11", Version: 1, Image: "image1", CPU: "2", Memory: "1Gi", Priority: 1, SpawnedAt: now, CreatedAt: now, UpdatedAt: now, Revision: 2},
+			Algorithm: &models.AlgorithmStatus{ID: 5, ClientID: 11, VWAP: true, HFT: true},
+		},
+		{
+			Client: models.Client{ID: 12, ClientName: "Client12", Version: 1, Image: "image2", CPU: "2", Memory: "1Gi", Priority: 1, ClusterID: &clusterID, SpawnedAt: now, CreatedAt: now, UpdatedAt: now, Revision: 1},
+			Pause:  &models.ClientPause{ClientID: 12, Reason: "debugging", Actor: "alice", PausedAt: now},
+		},
+	}, states)
+}
+
+// TestDesiredStates_NoLimit tests that a zero limit reads all remaining clients.
+func TestDesiredStates_NoLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM clients c LEFT JOIN algorithm_status a").
+		WithArgs(0, nil).
+		WillReturnRows(sqlmock.NewRows(desiredStateColumns))
+
+	states, err := repo.DesiredStates(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, states)
+}
+
+func clientRow(id int64, now time.Time) []driver.Value {
+	return []driver.Value{id, "Client", 1, "image", "2", "1Gi", 1, false, nil, now, now, now, 1}
+}
+
+// TestClientRecordsByClients tests reading the records of a page of clients with one query per kind.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the records are
+// read with the client IDs as an array and are grouped by client ID, leaving out clients
+// without records.
+func TestClientRecordsByClients(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+	secrets := repository.NewSecretRepository(db)
+	ctx := context.Background()
+	ids := []int64{1, 2}
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_state WHERE client_id = ANY\\(\\$1\\) ORDER BY client_id, algorithm").
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows(algorithmStateColumns).
+			AddRow(1, "hft", "Running", true, "hft-1", "image", "", now, 0, "", now, nil, nil, 0).
+			AddRow(1, "vwap", "Failed", false, "vwap-1", "image", "", nil, 4, "crash", now, now, now, 1))
+	expectClientRecords(mock, pq.Array(ids), 2, true, now)
+
+	states, err := repo.AlgorithmStatesByClients(ctx, ids)
+	assert.NoError(t, err)
+	assert.Len(t, states[1], 2)
+	assert.Equal(t, "vwap", states[1][1].Algorithm)
+	assert.Equal(t, now, *states[1][1].FailedAt)
+	assert.NotContains(t, states, int64(2))
+
+	scheduling, err := repo.SchedulingOverridesByClients(ctx, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]map[string]models.Scheduling{2: {"vwap": {NodeSelector: map[string]string{"pool": "algo"}}}}, scheduling)
+
+	clientSecrets, err := secrets.SecretsByClients(ctx, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ClientSecret{{ID: 2, ClientID: 2, Name: "API_KEY", Ciphertext: []byte("ciphertext"), Injection: "secret", CreatedAt: now, UpdatedAt: now}}, clientSecrets[2])
+	assert.NotContains(t, clientSecrets, int64(1))
+
+	parameters, err := repo.AlgorithmParametersByClients(ctx, ids)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"slices":10}`, string(parameters[2]["vwap"].Parameters))
+
+	windows, err := repo.AlgorithmWindowsByClients(ctx, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, "* 9-16 * * 1-5", windows[2]["vwap"].Cron)
+	assert.Empty(t, windows[2]["vwap"].Calendar)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// BenchmarkDesiredState_NPlusOne measures reading the desired state the way the
+// reconciler used to: one query for all clients, then per client one query each for its
+// algorithm status, observed state, scheduling overrides, secrets, parameters and run windows.
+func BenchmarkDesiredState_NPlusOne(b *testing.B) {
+	benchmarkDesiredState(b, func(b *testing.B, repo repository.ClientRepository, secrets repository.SecretRepository, mock sqlmock.Sqlmock, clients int, now time.Time) {
+		rows := sqlmock.NewRows(clientColumns)
+		for id := int64(1); id <= int64(clients); id++ {
+			rows.AddRow(clientRow(id, now)...)
+		}
+		mock.ExpectQuery("SELECT (.+) FROM clients").WillReturnRows(rows).WillDelayFor(benchmarkRoundTrip)
+		for id := int64(1); id <= int64(clients); id++ {
+			mock.ExpectQuery("SELECT (.+) FROM algorithm_status").
+				WithArgs(id).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "client_id", "vwap", "twap", "hft"}).AddRow(id, id, true, false, true)).
+				WillDelayFor(benchmarkRoundTrip)
+			expectAlgorithmState(mock, id, now)
+			expectClientRecords(mock, id, id, false, now)
+		}
+
+		b.StartTimer()
+		list, err := repo.Clients()
+		if err != nil {
+			b.Fatal(err)
+		}
+		ctx := context.Background()
+		for _, client := range list {
+			if _, err := repo.AlgorithmByClientID(ctx, client.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := repo.AlgorithmStates(ctx, client.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := repo.SchedulingOverrides(ctx, client.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := secrets.Secrets(ctx, client.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := repo.AlgorithmParameters(ctx, client.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := repo.AlgorithmWindows(ctx, client.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+	})
+}
+
+// BenchmarkDesiredState_Joined measures reading the desired state the way the reconciler
+// does: the joined query and one query per kind of client record for the whole page. The
+// observed state is still read per client, once the client is locked, because the
+// reconciler writes it back and must not work from a copy read before the lock.
+func BenchmarkDesiredState_Joined(b *testing.B) {
+	benchmarkDesiredState(b, func(b *testing.B, repo repository.ClientRepository, secrets repository.SecretRepository, mock sqlmock.Sqlmock, clients int, now time.Time) {
+		rows := sqlmock.NewRows(desiredStateColumns)
+		ids := make([]int64, 0, clients)
+		for id := int64(1); id <= int64(clients); id++ {
+			rows.AddRow(append(clientRow(id, now), id, true, false, true, nil, nil, nil, nil)...)
+			ids = append(ids, id)
+		}
+		mock.ExpectQuery("SELECT (.+) FROM clients c LEFT JOIN algorithm_status a").WillReturnRows(rows).WillDelayFor(benchmarkRoundTrip)
+		expectClientRecords(mock, pq.Array(ids), 1, true, now)
+		for id := int64(1); id <= int64(clients); id++ {
+			expectAlgorithmState(mock, id, now)
+		}
+
+		b.StartTimer()
+		ctx := context.Background()
+		states, err := repo.DesiredStates(ctx, 0, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.SchedulingOverridesByClients(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := secrets.SecretsByClients(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.AlgorithmParametersByClients(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.AlgorithmWindowsByClients(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+		for _, state := range states {
+			if _, err := repo.AlgorithmStates(ctx, state.Client.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+	})
+}
+
+var algorithmStateColumns = []string{"client_id", "algorithm", "phase", "ready", "pod_name", "image", "reason", "started_at", "restart_count",
+	"last_error", "last_synced_at", "failed_at", "restart_window_started_at", "restart_window_base"}
+
+// expectAlgorithmState expects the query of the observed state of a client, returning one state.
+func expectAlgorithmState(mock sqlmock.Sqlmock, clientID int64, now time.Time) {
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_state").
+		WithArgs(clientID).
+		WillReturnRows(sqlmock.NewRows(algorithmStateColumns).
+			AddRow(clientID, "vwap", "Running", true, "vwap", "image", "", now, 0, "", now, nil, nil, 0)).
+		WillDelayFor(benchmarkRoundTrip)
+}
+
+// expectClientRecords expects the queries of the scheduling overrides, secrets, parameters
+// and run windows with arg as their argument, returning a row for the client with ID clientID each.
+// The scheduling overrides are selected with their client ID only by the batch query.
+func expectClientRecords(mock sqlmock.Sqlmock, arg driver.Value, clientID int64, batch bool, now time.Time) {
+	override := []byte(`{"node_selector":{"pool":"algo"}}`)
+	scheduling := sqlmock.NewRows([]string{"algorithm", "scheduling"}).AddRow("vwap", override)
+	if batch {
+		scheduling = sqlmock.NewRows([]string{"client_id", "algorithm", "scheduling"}).AddRow(clientID, "vwap", override)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM client_scheduling").
+		WithArgs(arg).
+		WillReturnRows(scheduling).
+		WillDelayFor(benchmarkRoundTrip)
+	mock.ExpectQuery("SELECT (.+) FROM client_secrets").
+		WithArgs(arg).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_id", "name", "ciphertext", "injection", "created_at", "updated_at"}).
+			AddRow(clientID, clientID, "API_KEY", []byte("ciphertext"), "secret", now, now)).
+		WillDelayFor(benchmarkRoundTrip)
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_parameters").
+		WithArgs(arg).
+		WillReturnRows(sqlmock.NewRows([]string{"client_id", "algorithm", "parameters", "updated_at"}).
+			AddRow(clientID, "vwap", []byte(`{"slices":10}`), now)).
+		WillDelayFor(benchmarkRoundTrip)
+	mock.ExpectQuery("SELECT (.+) FROM algorithm_windows").
+		WithArgs(arg).
+		WillReturnRows(sqlmock.NewRows([]string{"client_id", "algorithm", "cron", "timezone", "calendar", "start_before", "stop_after", "updated_at"}).
+			AddRow(clientID, "vwap", "* 9-16 * * 1-5", "UTC", nil, 0, 0, now)).
+		WillDelayFor(benchmarkRoundTrip)
+}
+
+// benchmarkDesiredState runs read for 200 clients per iteration, excluding the
+// time spent registering the mocked queries.
+func benchmarkDesiredState(b *testing.B, read func(b *testing.B, repo repository.ClientRepository, secrets repository.SecretRepository, mock sqlmock.Sqlmock, clients int, now time.Time)) {
+	const clients = 200
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	repo := repository.NewClientRepository(db)
+	secrets := repository.NewSecretRepository(db)
+	now := time.Now()
+
+	b.StopTimer()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		read(b, repo, secrets, mock, clients, now)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, record *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Refresh(ctx context.Context, record *models.IdempotencyRecord, now time.Time) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	log := logger.GetLogger()
+	return &idempotencyRepository{db: db, log: log}
+}
+
+// Claim stores an in-progress record for the scope and key of the given record, unless another
+// record holds them. Expired records and records still in progress that were last refreshed
+// before staleBefore, whose request was abandoned, are replaced.
+// It returns nil if the key was claimed, or the record holding the key otherwise.
+func (ir *idempotencyRepository) Claim(ctx context.Context, record *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	const op = "repository.idempotency.Claim"
+
+	claim := `
+		INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at, locked_at)
+		VALUES ($1, $2, $3, $4, $5, $4)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, response_body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at, locked_at = EXCLUDED.locked_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_at < $6)
+	`
+
+	result, err := ir.db.ExecContext(ctx, claim, record.Scope, record.Key, record.RequestHash, record.CreatedAt, record.ExpiresAt, staleBefore)
+	if err != nil {
+		ir.log.Errorf("%s: failed to claim idempotency key: %v", op, err)
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		ir.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected > 0 {
+		ir.log.Debugf("%s: idempotency key %q of %s claimed", op, record.Key, record.Scope)
+		return nil, nil
+	}
+
+	query := `
+		SELECT request_hash, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`
+
+	existing := models.IdempotencyRecord{Scope: record.Scope, Key: record.Key}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	err = ir.db.QueryRowContext(ctx, query, record.Scope, record.Key).Scan(
+		&existing.RequestHash,
+		&statusCode,
+		&contentType,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The record was released or purged since the claim, the caller may claim the key again.
+		return nil, fmt.Errorf("idempotency key %q of %s was released concurrently", record.Key, record.Scope)
+	}
+	if err != nil {
+		ir.log.Errorf("%s: failed to retrieve idempotency key: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve idempotency key: %w", err)
+	}
+	existing.StatusCode = int(statusCode.Int64)
+	existing.ContentType = contentType.String
+
+	return &existing, nil
+}
+
+// Complete stores the response of the claimed record with the scope and key of the given record.
+func (ir *idempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	const op = "repository.idempotency.Complete"
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, response_body = $3
+		WHERE scope = $4 AND key = $5 AND request_hash = $6
+	`
+
+	_, err := ir.db.ExecContext(ctx, query, record.StatusCode, record.ContentType, record.Body, record.Scope, record.Key, record.RequestHash)
+	if err != nil {
+		ir.log.Errorf("%s: failed to store idempotent response: %v", op, err)
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// Refresh marks the claimed record with the scope and key of the given record as still in progress
+// at now, so that it is not taken for abandoned. Records of other requests and completed records are left unchanged.
+func (ir *idempotencyRepository) Refresh(ctx context.Context, record *models.IdempotencyRecord, now time.Time) error {
+	const op = "repository.idempotency.Refresh"
+
+	query := `
+		UPDATE idempotency_keys
+		SET locked_at = $1
+		WHERE scope = $2 AND key = $3 AND request_hash = $4 AND status_code IS NULL
+	`
+
+	if _, err := ir.db.ExecContext(ctx, query, now, record.Scope, record.Key, record.RequestHash); err != nil {
+		ir.log.Errorf("%s: failed to refresh idempotency key: %v", op, err)
+		return fmt.Errorf("failed to refresh idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release removes the record with the given scope and key, so that the key can be used again.
+func (ir *idempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	const op = "repository.idempotency.Release"
+
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`
+
+	if _, err := ir.db.ExecContext(ctx, query, scope, key); err != nil {
+		ir.log.Errorf("%s: failed to release idempotency key: %v", op, err)
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes the records that expired before now and returns their number.
+func (ir *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "repository.idempotency.DeleteExpired"
+
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= $1
+	`
+
+	result, err := ir.db.ExecContext(ctx, query, now)
+	if err != nil {
+		ir.log.Errorf("%s: failed to delete expired idempotency keys: %v", op, err)
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		ir.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,100 @@
+package repository_test
+
+import (
+	"context"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdempotencyClaim tests claiming an idempotency key.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that a free, expired or
+// abandoned key is claimed by an upsert, and that the record holding a key is returned otherwise.
+func TestIdempotencyClaim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewIdempotencyRepository(db)
+
+	now := time.Now()
+	staleBefore := now.Add(-time.Minute)
+	record := &models.IdempotencyRecord{Scope: "client.add", Key: "k1", RequestHash: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	claim := "INSERT INTO idempotency_keys (.+) ON CONFLICT \\(scope, key\\) DO UPDATE (.+) WHERE idempotency_keys.expires_at <= EXCLUDED.created_at OR \\(idempotency_keys.status_code IS NULL AND idempotency_keys.locked_at < \\$6\\)"
+	mock.ExpectExec(claim).
+		WithArgs("client.add", "k1", "abc", now, record.ExpiresAt, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(claim).
+		WithArgs("client.add", "k1", "abc", now, record.ExpiresAt, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM idempotency_keys WHERE scope = \\$1 AND key = \\$2").
+		WithArgs("client.add", "k1").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at"}).
+			AddRow("abc", 201, "application/json", []byte(`{"id":7}`), now, record.ExpiresAt))
+
+	existing, err := repo.Claim(context.Background(), record, staleBefore)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = repo.Claim(context.Background(), record, staleBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.IdempotencyRecord{
+		Scope:       "client.add",
+		Key:         "k1",
+		RequestHash: "abc",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":7}`),
+		CreatedAt:   now,
+		ExpiresAt:   record.ExpiresAt,
+	}, existing)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestIdempotencyComplete tests storing the response of a claimed idempotency key.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the response is
+// only stored for the record of the same request.
+func TestIdempotencyComplete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewIdempotencyRepository(db)
+
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code = \\$1, content_type = \\$2, response_body = \\$3 WHERE scope = \\$4 AND key = \\$5 AND request_hash = \\$6").
+		WithArgs(201, "application/json", []byte(`{"id":7}`), "client.add", "k1", "abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Complete(context.Background(), &models.IdempotencyRecord{Scope: "client.add", Key: "k1", RequestHash: "abc", StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":7}`)})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestIdempotencyRefresh tests marking a claimed idempotency key as still in progress.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that only the in-progress
+// record of the same request is refreshed.
+func TestIdempotencyRefresh(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewIdempotencyRepository(db)
+
+	now := time.Now()
+	mock.ExpectExec("UPDATE idempotency_keys SET locked_at = \\$1 WHERE scope = \\$2 AND key = \\$3 AND request_hash = \\$4 AND status_code IS NULL").
+		WithArgs(now, "client.add", "k1", "abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Refresh(context.Background(), &models.IdempotencyRecord{Scope: "client.add", Key: "k1", RequestHash: "abc"}, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
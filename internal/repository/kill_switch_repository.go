@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+
+	"github.com/lib/pq"
+)
+
+// ErrKillSwitchEngaged is returned when enabling an algorithm whose kill switch is engaged.
+var ErrKillSwitchEngaged = domain.New(domain.Conflict, "kill_switch_engaged", "kill switch engaged")
+
+// killSwitchLockKey is the transaction-level advisory lock that serializes engaging kill
+// switches with enabling algorithms. Engage takes it exclusively and enabling takes it shared,
+// so an algorithm cannot be enabled while a kill switch for it is being engaged.
+const killSwitchLockKey int64 = 0x6b696c6c
+
+type KillSwitchRepository interface {
+	Engage(ctx context.Context, algorithms []string, actor, reason string) ([]models.KillSwitch, []models.Client, error)
+	Release(ctx context.Context, algorithms []string, actor string) ([]models.KillSwitch, error)
+	KillSwitches(ctx context.Context) ([]models.KillSwitch, error)
+}
+
+type killSwitchRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewKillSwitchRepository(db *sql.DB) KillSwitchRepository {
+	log := logger.GetLogger()
+	return &killSwitchRepository{db: db, log: log}
+}
+
+const killSwitchColumns = "id, algorithm, actor, reason, engaged_at, released_by, released_at"
+
+// Engage engages the kill switches of the given algorithms and disables the algorithms
+// for every client that is not deleted in a single transaction. Kill switches that are
+// already engaged are kept. It returns the engaged kill switches of the algorithms and the
+// clients that had one of the algorithms enabled.
+func (kr *killSwitchRepository) Engage(ctx context.Context, algorithms []string, actor, reason string) ([]models.KillSwitch, []models.Client, error) {
+	const op = "repository.killSwitch.Engage"
+
+	setClauses := make([]string, 0, len(algorithms))
+	enabled := make([]string, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		if !models.IsAlgorithm(algorithm) {
+			return nil, nil, fmt.Errorf("unknown algorithm %q", algorithm)
+		}
+		// Column names come from the algorithm whitelist above.
+		setClauses = append(setClauses, fmt.Sprintf("%s = false", algorithm))
+		enabled = append(enabled, "a."+algorithm)
+	}
+
+	tx, err := kr.db.BeginTx(ctx, nil)
+	if err != nil {
+		kr.log.Errorf("%s: failed to begin transaction: %v", op, err)
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", killSwitchLockKey); err != nil {
+		kr.log.Errorf("%s: failed to acquire kill switch lock: %v", op, err)
+		return nil, nil, fmt.Errorf("failed to acquire kill switch lock: %w", err)
+	}
+
+	insert := `
+		INSERT INTO kill_switches (algorithm, actor, reason)
+		SELECT unnest($1::text[]), $2, $3
+		ON CONFLICT (algorithm) WHERE released_at IS NULL DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insert, pq.Array(algorithms), actor, reason); err != nil {
+		kr.log.Errorf("%s: failed to engage kill switches: %v", op, err)
+		return nil, nil, fmt.Errorf("failed to engage kill switches: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM kill_switches
+		WHERE released_at IS NULL AND algorithm = ANY($1)
+		ORDER BY algorithm
+	`, killSwitchColumns), pq.Array(algorithms))
+	if err != nil {
+		kr.log.Errorf("%s: failed to retrieve engaged kill switches: %v", op, err)
+		return nil, nil, fmt.Errorf("failed to retrieve engaged kill switches: %w", err)
+	}
+	switches, err := kr.scanKillSwitches(op, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	update := fmt.Sprintf(`
+		UPDATE algorithm_status a
+		SET %s
+		FROM clients c
+		WHERE c.id = a.client_id AND c.deleted_at IS NULL AND (%s)
+		RETURNING c.id, c.client_name, c.image, c.cluster_id
+	`, strings.Join(setClauses, ", "), strings.Join(enabled, " OR "))
+	rows, err = tx.QueryContext(ctx, update)
+	if err != nil {
+		kr.log.Errorf("%s: failed to disable algorithms: %v", op, err)
+		return nil, nil, fmt.Errorf("failed to disable algorithms: %w", err)
+	}
+	defer rows.Close()
+
+	clients := make([]models.Client, 0)
+	for rows.Next() {
+		var client models.Client
+		if err := rows.Scan(&client.ID, &client.ClientName, &client.Image, &client.ClusterID); err != nil {
+			kr.log.Errorf("%s: failed to scan disabled client row: %v", op, err)
+			return nil, nil, fmt.Errorf("failed to scan disabled client row: %w", err)
+		}
+		clients = append(clients, client)
+	}
+	if err := rows.Err(); err != nil {
+		kr.log.Errorf("%s: error during iteration over disabled clients: %v", op, err)
+		return nil, nil, fmt.Errorf("error during iteration over disabled clients: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		kr.log.Errorf("%s: failed to commit transaction: %v", op, err)
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	kr.log.Warnf("%s: kill switch for %v engaged by %s, %d clients disabled", op, algorithms, actor, len(clients))
+
+	return switches, clients, nil
+}
+
+// Release releases the engaged kill switches of the given algorithms.
+// Released kill switches are kept as history. It returns the released kill switches.
+func (kr *killSwitchRepository) Release(ctx context.Context, algorithms []string, actor string) ([]models.KillSwitch, error) {
+	const op = "repository.killSwitch.Release"
+
+	query := fmt.Sprintf(`
+		UPDATE kill_switches
+		SET released_by = $2, released_at = now()
+		WHERE released_at IS NULL AND algorithm = ANY($1)
+		RETURNING %s
+	`, killSwitchColumns)
+
+	rows, err := kr.db.QueryContext(ctx, query, pq.Array(algorithms), actor)
+	if err != nil {
+		kr.log.Errorf("%s: failed to release kill switches: %v", op, err)
+		return nil, fmt.Errorf("failed to release kill switches: %w", err)
+	}
+
+	switches, err := kr.scanKillSwitches(op, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	kr.log.Warnf("%s: %d kill switches for %v released by %s", op, len(switches), algorithms, actor)
+
+	return switches, nil
+}
+
+// KillSwitches retrieves the engaged kill switches ordered by algorithm.
+func (kr *killSwitchRepository) KillSwitches(ctx context.Context) ([]models.KillSwitch, error) {
+	const op = "repository.killSwitch.KillSwitches"
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM kill_switches
+		WHERE released_at IS NULL
+		ORDER BY algorithm
+	`, killSwitchColumns)
+
+	rows, err := kr.db.QueryContext(ctx, query)
+	if err != nil {
+		kr.log.Errorf("%s: failed to retrieve kill switches: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve kill switches: %w", err)
+	}
+
+	return kr.scanKillSwitches(op, rows)
+}
+
+// scanKillSwitches reads and closes rows of killSwitchColumns.
+func (kr *killSwitchRepository) scanKillSwitches(op string, rows *sql.Rows) ([]models.KillSwitch, error) {
+	defer rows.Close()
+
+	switches := make([]models.KillSwitch, 0)
+	for rows.Next() {
+		var ks models.KillSwitch
+		err := rows.Scan(
+			&ks.ID,
+			&ks.Algorithm,
+			&ks.Actor,
+			&ks.Reason,
+			&ks.EngagedAt,
+			&ks.ReleasedBy,
+			&ks.ReleasedAt,
+		)
+		if err != nil {
+			kr.log.Errorf("%s: failed to scan kill switch row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan kill switch row: %w", err)
+		}
+		switches = append(switches, ks)
+	}
+
+	if err := rows.Err(); err != nil {
+		kr.log.Errorf("%s: error during iteration over kill switches: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over kill switches: %w", err)
+	}
+
+	return switches, nil
+}
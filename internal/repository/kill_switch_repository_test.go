@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var killSwitchColumns = []string{"id", "algorithm", "actor", "reason", "engaged_at", "released_by", "released_at"}
+
+// TestEngageKillSwitch tests engaging kill switches.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the kill switches
+// are inserted and the matching algorithms disabled in a single transaction, and that the
+// clients that had one of the algorithms enabled are returned.
+func TestEngageKillSwitch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewKillSwitchRepository(db)
+
+	now := time.Now()
+	algorithms := []string{models.AlgorithmTWAP, models.AlgorithmHFT}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock\\(\\$1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO kill_switches (.+) ON CONFLICT \\(algorithm\\) WHERE released_at IS NULL DO NOTHING").
+		WithArgs(pq.Array(algorithms), "alice", "market incident").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT (.+) FROM kill_switches WHERE released_at IS NULL AND algorithm = ANY\\(\\$1\\)").
+		WithArgs(pq.Array(algorithms)).
+		WillReturnRows(sqlmock.NewRows(killSwitchColumns).
+			AddRow(2, "hft", "alice", "market incident", now, nil, nil).
+			AddRow(1, "twap", "bob", "", now, nil, nil))
+	mock.ExpectQuery("UPDATE algorithm_status a SET twap = false, hft = false FROM clients c WHERE c.id = a.client_id AND c.deleted_at IS NULL AND \\(a.twap OR a.hft\\) RETURNING").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "client_name", "image", "cluster_id"}).
+			AddRow(1, "Client1", "image1", nil).
+			AddRow(2, "Client2", "image2", 7))
+	mock.ExpectCommit()
+
+	switches, clients, err := repo.Engage(context.Background(), algorithms, "alice", "market incident")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	clusterID := int64(7)
+	assert.Equal(t, []models.KillSwitch{
+		{ID: 2, Algorithm: "hft", Actor: "alice", Reason: "market incident", EngagedAt: now},
+		{ID: 1, Algorithm: "twap", Actor: "bob", EngagedAt: now},
+	}, switches)
+	assert.Equal(t, []models.Client{
+		{ID: 1, ClientName: "Client1", Image: "image1"},
+		{ID: 2, ClientName: "Client2", Image: "image2", ClusterID: &clusterID},
+	}, clients)
+}
+
+// TestEngageKillSwitch_RollsBack tests that no kill switch is engaged when the algorithms
+// cannot be disabled, and that unknown algorithms are rejected before touching the database.
+func TestEngageKillSwitch_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewKillSwitchRepository(db)
+
+	_, _, err = repo.Engage(context.Background(), []string{"hft; DROP TABLE clients"}, "alice", "")
+	assert.Error(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO kill_switches").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM kill_switches").WillReturnRows(sqlmock.NewRows(killSwitchColumns))
+	mock.ExpectQuery("UPDATE algorithm_status").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	_, _, err = repo.Engage(context.Background(), []string{models.AlgorithmHFT}, "alice", "")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestReleaseKillSwitch tests that released kill switches are kept with the releasing actor.
+func TestReleaseKillSwitch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewKillSwitchRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("UPDATE kill_switches SET released_by = \\$2, released_at = now\\(\\) WHERE released_at IS NULL AND algorithm = ANY\\(\\$1\\) RETURNING").
+		WithArgs(pq.Array([]string{models.AlgorithmHFT}), "bob").
+		WillReturnRows(sqlmock.NewRows(killSwitchColumns).AddRow(2, "hft", "alice", "market incident", now, "bob", now))
+
+	switches, err := repo.Release(context.Background(), []string{models.AlgorithmHFT}, "bob")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	releasedBy := "bob"
+	assert.Equal(t, []models.KillSwitch{
+		{ID: 2, Algorithm: "hft", Actor: "alice", Reason: "market incident", EngagedAt: now, ReleasedBy: &releasedBy, ReleasedAt: &now},
+	}, switches)
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+type ScheduledChangeRepository interface {
+	Create(ctx context.Context, change *models.ScheduledChange) error
+	ScheduledChanges(ctx context.Context, clientID int64) ([]models.ScheduledChange, error)
+	Cancel(ctx context.Context, clientID, id int64) (bool, error)
+	DueChanges(ctx context.Context, now time.Time, limit int) ([]models.ScheduledChange, error)
+	Claim(ctx context.Context, id int64) (bool, error)
+	Finish(ctx context.Context, change *models.ScheduledChange) error
+}
+
+type scheduledChangeRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewScheduledChangeRepository(db *sql.DB) ScheduledChangeRepository {
+	log := logger.GetLogger()
+	return &scheduledChangeRepository{db: db, log: log}
+}
+
+const scheduledChangeColumns = "id, client_id, algorithm, enabled, apply_at, actor, reason, status, applied_at, error, created_at"
+
+// Create inserts a pending scheduled change and sets its ID, status and creation time.
+func (sr *scheduledChangeRepository) Create(ctx context.Context, change *models.ScheduledChange) error {
+	const op = "repository.scheduledChange.Create"
+
+	query := `
+		INSERT INTO scheduled_changes (client_id, algorithm, enabled, apply_at, actor, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at
+	`
+
+	err := sr.db.QueryRowContext(ctx, query,
+		change.ClientID,
+		change.Algorithm,
+		change.Enabled,
+		change.ApplyAt,
+		change.Actor,
+		change.Reason,
+	).Scan(&change.ID, &change.Status, &change.CreatedAt)
+	if err != nil {
+		sr.log.Errorf("%s: failed to insert scheduled change: %v", op, err)
+		return fmt.Errorf("failed to insert scheduled change: %w", err)
+	}
+
+	return nil
+}
+
+// ScheduledChanges retrieves the scheduled changes of a client ordered by the time they apply at.
+func (sr *scheduledChangeRepository) ScheduledChanges(ctx context.Context, clientID int64) ([]models.ScheduledChange, error) {
+	const op = "repository.scheduledChange.ScheduledChanges"
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM scheduled_changes
+		WHERE client_id = $1
+		ORDER BY apply_at, id
+	`, scheduledChangeColumns)
+
+	rows, err := sr.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		sr.log.Errorf("%s: failed to retrieve scheduled changes: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve scheduled changes: %w", err)
+	}
+
+	return sr.scanScheduledChanges(op, rows)
+}
+
+// Cancel cancels a pending scheduled change of a client.
+// It reports false if there is no such pending change.
+func (sr *scheduledChangeRepository) Cancel(ctx context.Context, clientID, id int64) (bool, error) {
+	const op = "repository.scheduledChange.Cancel"
+
+	query := `
+		UPDATE scheduled_changes
+		SET status = 'cancelled'
+		WHERE id = $1 AND client_id = $2 AND status = 'pending'
+	`
+
+	result, err := sr.db.ExecContext(ctx, query, id, clientID)
+	if err != nil {
+		sr.log.Errorf("%s: failed to cancel scheduled change: %v", op, err)
+		return false, fmt.Errorf("failed to cancel scheduled change: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		sr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// DueChanges retrieves up to limit changes due at now that are not applied yet, oldest first.
+// Changes left applying by a scheduler that stopped are returned again.
+func (sr *scheduledChangeRepository) DueChanges(ctx context.Context, now time.Time, limit int) ([]models.ScheduledChange, error) {
+	const op = "repository.scheduledChange.DueChanges"
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM scheduled_changes
+		WHERE status IN ('pending', 'applying') AND apply_at <= $1
+		ORDER BY apply_at, id
+		LIMIT $2
+	`, scheduledChangeColumns)
+
+	rows, err := sr.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		sr.log.Errorf("%s: failed to retrieve due changes: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve due changes: %w", err)
+	}
+
+	return sr.scanScheduledChanges(op, rows)
+}
+
+// Claim marks a due change as applying, so that it can no longer be cancelled.
+// It reports false if the change was cancelled or finished in the meantime.
+func (sr *scheduledChangeRepository) Claim(ctx context.Context, id int64) (bool, error) {
+	const op = "repository.scheduledChange.Claim"
+
+	query := `
+		UPDATE scheduled_changes
+		SET status = 'applying'
+		WHERE id = $1 AND status IN ('pending', 'applying')
+	`
+
+	result, err := sr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		sr.log.Errorf("%s: failed to claim scheduled change: %v", op, err)
+		return false, fmt.Errorf("failed to claim scheduled change: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		sr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Finish stores the status, application time and error of an applied or failed change.
+func (sr *scheduledChangeRepository) Finish(ctx context.Context, change *models.ScheduledChange) error {
+	const op = "repository.scheduledChange.Finish"
+
+	query := `
+		UPDATE scheduled_changes
+		SET status = $2, applied_at = $3, error = $4
+		WHERE id = $1
+	`
+
+	if _, err := sr.db.ExecContext(ctx, query, change.ID, change.Status, change.AppliedAt, change.Error); err != nil {
+		sr.log.Errorf("%s: failed to finish scheduled change: %v", op, err)
+		return fmt.Errorf("failed to finish scheduled change: %w", err)
+	}
+
+	return nil
+}
+
+// scanScheduledChanges reads and closes rows of scheduledChangeColumns.
+func (sr *scheduledChangeRepository) scanScheduledChanges(op string, rows *sql.Rows) ([]models.ScheduledChange, error) {
+	defer rows.Close()
+
+	changes := make([]models.ScheduledChange, 0)
+	for rows.Next() {
+		var change models.ScheduledChange
+		err := rows.Scan(
+			&change.ID,
+			&change.ClientID,
+			&change.Algorithm,
+			&change.Enabled,
+			&change.ApplyAt,
+			&change.Actor,
+			&change.Reason,
+			&change.Status,
+			&change.AppliedAt,
+			&change.Error,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			sr.log.Errorf("%s: failed to scan scheduled change row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan scheduled change row: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		sr.log.Errorf("%s: error during iteration over scheduled changes: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over scheduled changes: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,112 @@
+package repository_test
+
+import (
+	"context"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var scheduledChangeColumns = []string{"id", "client_id", "algorithm", "enabled", "apply_at", "actor", "reason", "status", "applied_at", "error", "created_at"}
+
+// TestCreateScheduledChange tests that a scheduled change is inserted as pending.
+func TestCreateScheduledChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewScheduledChangeRepository(db)
+
+	now := time.Now()
+	change := &models.ScheduledChange{
+		ClientID:  42,
+		Algorithm: models.AlgorithmTWAP,
+		Enabled:   true,
+		ApplyAt:   now.Add(time.Hour),
+		Actor:     "alice",
+		Reason:    "open auction",
+	}
+
+	mock.ExpectQuery("INSERT INTO scheduled_changes \\(client_id, algorithm, enabled, apply_at, actor, reason\\)").
+		WithArgs(int64(42), "twap", true, change.ApplyAt, "alice", "open auction").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(7, "pending", now))
+
+	err = repo.Create(context.Background(), change)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(7), change.ID)
+	assert.Equal(t, models.ScheduledChangePending, change.Status)
+	assert.Equal(t, now, change.CreatedAt)
+}
+
+// TestDueChanges tests that pending changes and changes left applying are returned once due.
+func TestDueChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewScheduledChangeRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM scheduled_changes WHERE status IN \\('pending', 'applying'\\) AND apply_at <= \\$1 ORDER BY apply_at, id LIMIT \\$2").
+		WithArgs(now, 100).
+		WillReturnRows(sqlmock.NewRows(scheduledChangeColumns).
+			AddRow(1, 42, "twap", true, now.Add(-time.Minute), "alice", "", "applying", nil, "", now).
+			AddRow(2, 43, "hft", false, now, "bob", "close", "pending", nil, "", now))
+
+	changes, err := repo.DueChanges(context.Background(), now, 100)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []models.ScheduledChange{
+		{ID: 1, ClientID: 42, Algorithm: "twap", Enabled: true, ApplyAt: now.Add(-time.Minute), Actor: "alice", Status: "applying", CreatedAt: now},
+		{ID: 2, ClientID: 43, Algorithm: "hft", ApplyAt: now, Actor: "bob", Reason: "close", Status: "pending", CreatedAt: now},
+	}, changes)
+}
+
+// TestCancelScheduledChange tests that only pending changes of the client are cancelled.
+func TestCancelScheduledChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewScheduledChangeRepository(db)
+
+	mock.ExpectExec("UPDATE scheduled_changes SET status = 'cancelled' WHERE id = \\$1 AND client_id = \\$2 AND status = 'pending'").
+		WithArgs(int64(7), int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE scheduled_changes SET status = 'cancelled'").
+		WithArgs(int64(8), int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	cancelled, err := repo.Cancel(context.Background(), 42, 7)
+	assert.NoError(t, err)
+	assert.True(t, cancelled)
+
+	cancelled, err = repo.Cancel(context.Background(), 42, 8)
+	assert.NoError(t, err)
+	assert.False(t, cancelled)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestClaimScheduledChange tests that a cancelled change cannot be claimed.
+func TestClaimScheduledChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewScheduledChangeRepository(db)
+
+	mock.ExpectExec("UPDATE scheduled_changes SET status = 'applying' WHERE id = \\$1 AND status IN \\('pending', 'applying'\\)").
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	claimed, err := repo.Claim(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+
+	"github.com/lib/pq"
+)
+
+type SecretRepository interface {
+	Secrets(ctx context.Context, clientID int64) ([]models.ClientSecret, error)
+	SecretsByClients(ctx context.Context, clientIDs []int64) (map[int64][]models.ClientSecret, error)
+	SaveSecret(ctx context.Context, secret *models.ClientSecret) error
+	DeleteSecret(ctx context.Context, clientID int64, name string) error
+}
+
+type secretRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewSecretRepository(db *sql.DB) SecretRepository {
+	log := logger.GetLogger()
+	return &secretRepository{db: db, log: log}
+}
+
+// Secrets retrieves the encrypted secrets of a client ordered by name.
+func (sr *secretRepository) Secrets(ctx context.Context, clientID int64) ([]models.ClientSecret, error) {
+	const op = "repository.secret.Secrets"
+
+	query := `
+		SELECT id, client_id, name, ciphertext, injection, created_at, updated_at
+		FROM client_secrets
+		WHERE client_id = $1
+		ORDER BY name
+	`
+
+	rows, err := sr.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		sr.log.Errorf("%s: failed to retrieve secrets: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve secrets: %w", err)
+	}
+	defer rows.Close()
+
+	secrets := make([]models.ClientSecret, 0)
+	for rows.Next() {
+		secret, err := scanSecret(rows)
+		if err != nil {
+			sr.log.Errorf("%s: failed to scan secret row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan secret row: %w", err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	if err := rows.Err(); err != nil {
+		sr.log.Errorf("%s: error during iteration over secrets: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// SecretsByClients retrieves the encrypted secrets of the given clients with a single query,
+// keyed by client ID and ordered by name. Clients without secrets are missing.
+func (sr *secretRepository) SecretsByClients(ctx context.Context, clientIDs []int64) (map[int64][]models.ClientSecret, error) {
+	const op = "repository.secret.SecretsByClients"
+
+	query := `
+		SELECT id, client_id, name, ciphertext, injection, created_at, updated_at
+		FROM client_secrets
+		WHERE client_id = ANY($1)
+		ORDER BY client_id, name
+	`
+
+	rows, err := sr.db.QueryContext(ctx, query, pq.Array(clientIDs))
+	if err != nil {
+		sr.log.Errorf("%s: failed to retrieve secrets: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve secrets: %w", err)
+	}
+	defer rows.Close()
+
+	secrets := make(map[int64][]models.ClientSecret)
+	for rows.Next() {
+		secret, err := scanSecret(rows)
+		if err != nil {
+			sr.log.Errorf("%s: failed to scan secret row: %v", op, err)
+			return nil, fmt.Errorf("failed to scan secret row: %w", err)
+		}
+		secrets[secret.ClientID] = append(secrets[secret.ClientID], secret)
+	}
+
+	if err := rows.Err(); err != nil {
+		sr.log.Errorf("%s: error during iteration over secrets: %v", op, err)
+		return nil, fmt.Errorf("error during iteration over secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// scanSecret scans a secret row selected by Secrets.
+func scanSecret(rows *sql.Rows) (models.ClientSecret, error) {
+	var secret models.ClientSecret
+	err := rows.Scan(
+		&secret.ID,
+		&secret.ClientID,
+		&secret.Name,
+		&secret.Ciphertext,
+		&secret.Injection,
+		&secret.CreatedAt,
+		&secret.UpdatedAt,
+	)
+	return secret, err
+}
+
+// SaveSecret inserts or replaces the secret of a client with the same name.
+// The ID and timestamps are written back to the secret.
+func (sr *secretRepository) SaveSecret(ctx context.Context, secret *models.ClientSecret) error {
+	const op = "repository.secret.SaveSecret"
+
+	query := `
+		INSERT INTO client_secrets (client_id, name, ciphertext, injection)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (client_id, name) DO UPDATE SET
+			ciphertext = EXCLUDED.ciphertext,
+			injection = EXCLUDED.injection
+		RETURNING id, created_at, updated_at
+	`
+
+	err := sr.db.QueryRowContext(ctx, query,
+		secret.ClientID,
+		secret.Name,
+		secret.Ciphertext,
+		secret.Injection,
+	).Scan(&secret.ID, &secret.CreatedAt, &secret.UpdatedAt)
+	if err != nil {
+		sr.log.Errorf("%s: failed to save secret: %v", op, err)
+		return fmt.Errorf("failed to save secret: %w", err)
+	}
+
+	sr.log.Infof("%s: secret %s of client %d saved", op, secret.Name, secret.ClientID)
+
+	return nil
+}
+
+// DeleteSecret deletes the secret of a client with the given name.
+func (sr *secretRepository) DeleteSecret(ctx context.Context, clientID int64, name string) error {
+	const op = "repository.secret.DeleteSecret"
+
+	query := `
+		DELETE FROM client_secrets
+		WHERE client_id = $1 AND name = $2
+	`
+
+	if _, err := sr.db.ExecContext(ctx, query, clientID, name); err != nil {
+		sr.log.Errorf("%s: failed to delete secret: %v", op, err)
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+
+	sr.log.Infof("%s: secret %s of client %d deleted", op, name, clientID)
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository_test
+
+import (
+	"context"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSaveSecret tests upserting an encrypted client secret.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies that the ciphertext
+// is written with an INSERT ... ON CONFLICT statement keyed by client and secret name.
+func TestSaveSecret(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewSecretRepository(db)
+
+	now := time.Now()
+	secret := &models.ClientSecret{ClientID: 1, Name: "EXCHANGE_API_KEY", Injection: models.SecretInjectionSecret, Ciphertext: []byte{1, 2, 3}}
+
+	mock.ExpectQuery("INSERT INTO client_secrets (.+) ON CONFLICT \\(client_id, name\\) DO UPDATE").
+		WithArgs(secret.ClientID, secret.Name, secret.Ciphertext, secret.Injection).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(5, now, now))
+
+	err = repo.SaveSecret(context.Background(), secret)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(5), secret.ID)
+}
+
+// TestSecrets tests fetching the encrypted secrets of a client.
+//
+// It mocks SQL database interactions using sqlmock. The test verifies the correct retrieval
+// of secret records including their ciphertext.
+func TestSecrets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewSecretRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "client_id", "name", "ciphertext", "injection", "created_at", "updated_at"}).
+		AddRow(5, 1, "EXCHANGE_API_KEY", []byte{1, 2, 3}, "env", now, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM client_secrets WHERE client_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	secrets, err := repo.Secrets(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []models.ClientSecret{
+		{ID: 5, ClientID: 1, Name: "EXCHANGE_API_KEY", Injection: "env", Ciphertext: []byte{1, 2, 3}, CreatedAt: now, UpdatedAt: now},
+	}, secrets)
+}
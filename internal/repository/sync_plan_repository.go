@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"test-task/internal/models"
+	"test-task/pkg/util/logger"
+)
+
+type SyncPlanRepository interface {
+	Create(ctx context.Context, plan *models.SyncPlan) error
+	Plan(ctx context.Context, id int64) (*models.SyncPlan, error)
+	UpdateStatus(ctx context.Context, id int64, from, to string) (bool, error)
+	Finish(ctx context.Context, plan *models.SyncPlan) error
+}
+
+type syncPlanRepository struct {
+	db  *sql.DB
+	log logger.Logger
+}
+
+func NewSyncPlanRepository(db *sql.DB) SyncPlanRepository {
+	log := logger.GetLogger()
+	return &syncPlanRepository{db: db, log: log}
+}
+
+// Create stores a pending plan and sets its ID, status and creation time.
+func (pr *syncPlanRepository) Create(ctx context.Context, plan *models.SyncPlan) error {
+	const op = "repository.syncPlan.Create"
+
+	actions, err := json.Marshal(plan.Actions)
+	if err != nil {
+		pr.log.Errorf("%s: failed to encode plan actions: %v", op, err)
+		return fmt.Errorf("failed to encode plan actions: %w", err)
+	}
+
+	query := `
+		INSERT INTO sync_plans (fingerprint, actions)
+		VALUES ($1, $2)
+		RETURNING id, status, created_at
+	`
+
+	if err := pr.db.QueryRowContext(ctx, query, plan.Fingerprint, actions).Scan(&plan.ID, &plan.Status, &plan.CreatedAt); err != nil {
+		pr.log.Errorf("%s: failed to insert sync plan: %v", op, err)
+		return fmt.Errorf("failed to insert sync plan: %w", err)
+	}
+
+	return nil
+}
+
+// Plan retrieves a sync plan by its ID, or nil if it does not exist.
+func (pr *syncPlanRepository) Plan(ctx context.Context, id int64) (*models.SyncPlan, error) {
+	const op = "repository.syncPlan.Plan"
+
+	query := `
+		SELECT id, status, fingerprint, actions, created_at, applied_at
+		FROM sync_plans
+		WHERE id = $1
+	`
+
+	var plan models.SyncPlan
+	var actions []byte
+	err := pr.db.QueryRowContext(ctx, query, id).Scan(
+		&plan.ID,
+		&plan.Status,
+		&plan.Fingerprint,
+		&actions,
+		&plan.CreatedAt,
+		&plan.AppliedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		pr.log.Errorf("%s: failed to retrieve sync plan: %v", op, err)
+		return nil, fmt.Errorf("failed to retrieve sync plan: %w", err)
+	}
+
+	if err := json.Unmarshal(actions, &plan.Actions); err != nil {
+		pr.log.Errorf("%s: failed to decode plan actions: %v", op, err)
+		return nil, fmt.Errorf("failed to decode plan actions: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// UpdateStatus moves a plan from one status to another.
+// It reports false if the plan is not in the from status.
+func (pr *syncPlanRepository) UpdateStatus(ctx context.Context, id int64, from, to string) (bool, error) {
+	const op = "repository.syncPlan.UpdateStatus"
+
+	query := `
+		UPDATE sync_plans
+		SET status = $3
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := pr.db.ExecContext(ctx, query, id, from, to)
+	if err != nil {
+		pr.log.Errorf("%s: failed to update sync plan status: %v", op, err)
+		return false, fmt.Errorf("failed to update sync plan status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		pr.log.Errorf("%s: failed to get affected rows: %v", op, err)
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Finish stores the status, application time and action results of an applied plan.
+func (pr *syncPlanRepository) Finish(ctx context.Context, plan *models.SyncPlan) error {
+	const op = "repository.syncPlan.Finish"
+
+	actions, err := json.Marshal(plan.Actions)
+	if err != nil {
+		pr.log.Errorf("%s: failed to encode plan actions: %v", op, err)
+		return fmt.Errorf("failed to encode plan actions: %w", err)
+	}
+
+	query := `
+		UPDATE sync_plans
+		SET status = $2, actions = $3, applied_at = $4
+		WHERE id = $1
+	`
+
+	if _, err := pr.db.ExecContext(ctx, query, plan.ID, plan.Status, actions, plan.AppliedAt); err != nil {
+		pr.log.Errorf("%s: failed to finish sync plan: %v", op, err)
+		return fmt.Errorf("failed to finish sync plan: %w", err)
+	}
+
+	return nil
+}
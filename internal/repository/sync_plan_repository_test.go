@@ -0,0 +1,88 @@
+package repository_test
+
+import (
+	"context"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateSyncPlan tests that a plan is stored with its actions as JSON.
+func TestCreateSyncPlan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewSyncPlanRepository(db)
+
+	now := time.Now()
+	plan := &models.SyncPlan{
+		Fingerprint: "abc",
+		Actions:     []models.PlanAction{{ClientID: 1, Algorithm: "vwap", Action: "create", PodName: "vwap-1", Cluster: "default", Reason: "algorithm enabled, no pod running"}},
+	}
+
+	mock.ExpectQuery("INSERT INTO sync_plans \\(fingerprint, actions\\)").
+		WithArgs("abc", []byte(`[{"client_id":1,"algorithm":"vwap","action":"create","pod_name":"vwap-1","cluster":"default","reason":"algorithm enabled, no pod running"}]`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(7, "pending", now))
+
+	err = repo.Create(context.Background(), plan)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(7), plan.ID)
+	assert.Equal(t, models.PlanPending, plan.Status)
+}
+
+// TestSyncPlan tests retrieving a stored plan and that a missing plan is nil.
+func TestSyncPlan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewSyncPlanRepository(db)
+
+	now := time.Now()
+	columns := []string{"id", "status", "fingerprint", "actions", "created_at", "applied_at"}
+	mock.ExpectQuery("SELECT (.+) FROM sync_plans WHERE id = \\$1").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, "pending", "abc", []byte(`[{"client_id":1,"algorithm":"hft","action":"delete","pod_name":"hft-1"}]`), now, nil))
+	mock.ExpectQuery("SELECT (.+) FROM sync_plans WHERE id = \\$1").
+		WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	plan, err := repo.Plan(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.SyncPlan{
+		ID:          7,
+		Status:      "pending",
+		Fingerprint: "abc",
+		Actions:     []models.PlanAction{{ClientID: 1, Algorithm: "hft", Action: "delete", PodName: "hft-1"}},
+		CreatedAt:   now,
+	}, plan)
+
+	plan, err = repo.Plan(context.Background(), 8)
+	assert.NoError(t, err)
+	assert.Nil(t, plan)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateSyncPlanStatus tests that a plan only moves from the expected status.
+func TestUpdateSyncPlanStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewSyncPlanRepository(db)
+
+	mock.ExpectExec("UPDATE sync_plans SET status = \\$3 WHERE id = \\$1 AND status = \\$2").
+		WithArgs(int64(7), "pending", "applying").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	updated, err := repo.UpdateStatus(context.Background(), 7, models.PlanPending, models.PlanApplying)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"test-task/infra/k8s"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/jsonschema"
+	"time"
+)
+
+var (
+	// ErrParametersNotFound is returned when no parameters were set for a client algorithm.
+	ErrParametersNotFound = domain.New(domain.NotFound, "parameters_not_found", "parameters not found")
+	// ErrInvalidParameters is returned when a parameters document does not match the schema of its algorithm type.
+	ErrInvalidParameters = domain.New(domain.Validation, "invalid_parameters", "invalid parameters")
+	// ErrSchemaNotFound is returned when no parameters schema is registered for an algorithm type.
+	ErrSchemaNotFound = domain.New(domain.NotFound, "schema_not_found", "parameters schema not found")
+)
+
+// ParameterSchema returns the JSON schema registered for the parameters of an algorithm type.
+func (cs *clientService) ParameterSchema(algorithm string) (*jsonschema.Schema, error) {
+	if !models.IsAlgorithm(algorithm) {
+		return nil, fmt.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	schema, ok := cs.config.ParameterSchemas[algorithm]
+	if !ok {
+		return nil, ErrSchemaNotFound
+	}
+
+	return schema, nil
+}
+
+// Parameters returns the parameters document of a client algorithm.
+func (cs *clientService) Parameters(ctx context.Context, clientID int64, algorithm string) (*models.AlgorithmParameters, error) {
+	if !models.IsAlgorithm(algorithm) {
+		return nil, fmt.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	parameters, err := cs.repository.AlgorithmParameters(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	params, ok := parameters[algorithm]
+	if !ok {
+		return nil, ErrParametersNotFound
+	}
+
+	return &params, nil
+}
+
+// SetParameters validates and stores the parameters document of a client algorithm.
+// If the algorithm is enabled, its ConfigMap is updated in place so that the running pod
+// sees the new parameters file; with restart the pod is also recreated.
+// A disabled algorithm, or one of a paused client, picks up the parameters when its pod is created.
+func (cs *clientService) SetParameters(ctx context.Context, clientID int64, algorithm string, parameters json.RawMessage, restart bool) (*models.ParametersUpdate, error) {
+	const op = "service.client.SetParameters"
+
+	if !models.IsAlgorithm(algorithm) {
+		return nil, fmt.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	if err := cs.validateParameters(algorithm, parameters); err != nil {
+		return nil, err
+	}
+
+	client, err := cs.repository.ClientByID(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.AlgorithmParameters{ClientID: clientID, Algorithm: algorithm, Parameters: parameters}
+	if err := cs.repository.SaveAlgorithmParameters(ctx, &params); err != nil {
+		return nil, err
+	}
+
+	update := &models.ParametersUpdate{AlgorithmParameters: params}
+
+	defer cs.locks.lock(clientID)()
+
+	algoStatus, err := cs.repository.AlgorithmByClientID(ctx, clientID)
+	if errors.Is(err, repository.ErrAlgorithmStatusNotFound) {
+		return update, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !algoStatus.Enabled(algorithm) {
+		return update, nil
+	}
+
+	pause, err := cs.repository.Pause(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if pause.Active(time.Now()) {
+		cs.log.Infof("%s: %s parameters of client %d saved, not applied while paused", op, algorithm, clientID)
+		return update, nil
+	}
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deployer, err := cs.deployerFor(*client, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := podSpec(*client, algorithm, podName(clientID, algorithm), models.Scheduling{}, nil, string(parameters))
+	if err := deployer.ApplyConfigMap(spec); err != nil {
+		return nil, fmt.Errorf("parameters saved but not applied: %w", err)
+	}
+	update.Applied = true
+
+	if restart {
+		if err := deployer.DeletePod(spec.Name); err != nil {
+			return nil, fmt.Errorf("parameters applied but pod not restarted: %w", err)
+		}
+		cs.syncPodsForClient(ctx, deployer, *client, *algoStatus, nil)
+		update.Restarted = true
+	}
+
+	cs.log.Infof("%s: %s parameters of client %d applied (restarted: %t)", op, algorithm, clientID, update.Restarted)
+
+	return update, nil
+}
+
+// validateParameters checks a parameters document against the schema of its algorithm type.
+// Algorithm types without a schema accept any JSON object.
+func (cs *clientService) validateParameters(algorithm string, parameters json.RawMessage) error {
+	schema, ok := cs.config.ParameterSchemas[algorithm]
+	if !ok {
+		var document map[string]interface{}
+		if err := json.Unmarshal(parameters, &document); err != nil || document == nil {
+			return fmt.Errorf("%w: parameters must be a JSON object", ErrInvalidParameters)
+		}
+		return nil
+	}
+
+	if err := schema.Validate(parameters); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameters, err)
+	}
+
+	return nil
+}
+
+// parametersEnv returns the environment variable pointing the algorithm at its parameters file.
+func parametersEnv() k8s.EnvVar {
+	return k8s.EnvVar{Name: "ALGOSYNC_PARAMETERS_FILE", Value: k8s.ParametersDir + "/" + k8s.ParametersFile}
+}
@@ -0,0 +1,662 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"test-task/infra/k8s"
+	"test-task/internal/models"
+	"test-task/pkg/notify"
+	"time"
+)
+
+// Labels set on every algorithm pod.
+const (
+	appName        = "algosync"
+	labelApp       = "app"
+	labelClientID  = "algosync/client-id"
+	labelAlgorithm = "algosync/algorithm"
+)
+
+// maxReadyTimeout caps the wait for a created pod to become ready, so that slow pods
+// do not hold the sync workers; a pod that is not ready yet is observed again next cycle.
+const maxReadyTimeout = 30 * time.Second
+
+// reasonReadyTimeout is the reason of a pod that did not become ready within the wait.
+const reasonReadyTimeout = "ReadyTimeout"
+
+// StartAlgorithmSync initiates the algorithm synchronization process.
+// This function starts a goroutine that synchronizes algorithms every SyncConfig.Interval, 5 minutes by default.
+// A Ticker is used to trigger the synchronization at the specified intervals.
+// When the function completes, the Ticker is stopped to release resources.
+func (cs *clientService) StartAlgorithmSync() {
+	const op = "service.client.StartAlgorithmSync"
+
+	cs.log.Infof("%s: Starting synchronization process...", op)
+	interval := cs.config.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for range ticker.C {
+			cs.syncAlgorithms()
+		}
+	}()
+
+	cs.log.Infof("%s: Synchronization process started", op)
+}
+
+// syncAlgorithms fetches clients together with their algorithm status and synchronizes pods for each client.
+// Clients are read in pages of SyncConfig.BatchSize, together with the scheduling overrides, secrets,
+// parameters and run windows of the page with one query each, and reconciled concurrently by up to
+// SyncConfig.Workers workers; each client is handled by a single worker, so the pods of
+// one client are still reconciled in order.
+func (cs *clientService) syncAlgorithms() {
+	const op = "service.client.syncAlgorithms"
+
+	ctx := context.Background()
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		cs.log.Errorf("%s: Failed to fetch clusters from database: %v", op, err)
+		return
+	}
+
+	if err := cs.refreshKillSwitches(ctx); err != nil {
+		cs.log.Errorf("%s: Failed to fetch kill switches from database: %v", op, err)
+		return
+	}
+
+	workers := cs.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	type syncJob struct {
+		state  models.DesiredState
+		inputs *clientInputs
+	}
+	queue := make(chan syncJob, workers)
+	cs.metrics.startCycle(workers)
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				cs.metrics.dequeued()
+				synced := time.Now()
+				result := cs.syncClient(ctx, job.state, job.inputs, clusters, started)
+				result.Duration = time.Since(synced)
+				cs.metrics.clientSynced(result)
+			}
+		}()
+	}
+
+	clientIDs := make(map[int64]bool)
+	var cursor int64
+	for {
+		states, err := cs.repository.DesiredStates(ctx, cursor, cs.config.BatchSize)
+		if err != nil {
+			cs.log.Errorf("%s: Failed to fetch clients from database: %v", op, err)
+			break
+		}
+
+		pageIDs := make([]int64, len(states))
+		for i, state := range states {
+			pageIDs[i] = state.Client.ID
+		}
+		inputs, err := cs.clientInputsByClients(ctx, pageIDs)
+		if err != nil {
+			cs.log.Errorf("%s: Failed to fetch client records from database: %v", op, err)
+			break
+		}
+
+		for _, state := range states {
+			clientIDs[state.Client.ID] = true
+			cs.metrics.enqueued()
+			queue <- syncJob{state: state, inputs: inputs[state.Client.ID]}
+		}
+
+		if cs.config.BatchSize <= 0 || len(states) < cs.config.BatchSize {
+			break
+		}
+		cursor = states[len(states)-1].Client.ID
+	}
+	close(queue)
+	wg.Wait()
+
+	cs.syncDeletedClients(ctx, clusters)
+
+	cs.metrics.endCycle(clientIDs)
+	cs.log.Debugf("%s: Synchronized %d clients with %d workers", op, len(clientIDs), workers)
+}
+
+// syncClient reconciles the pods of a single client while holding its lock, building them
+// from inputs or, if inputs is nil, from the records read once the lock is held.
+// Paused clients are left untouched and reported as paused. Clients paused after
+// readAt, when the desired state was read, are checked again once the lock is held.
+func (cs *clientService) syncClient(ctx context.Context, state models.DesiredState, inputs *clientInputs, clusters map[int64]*models.Cluster, readAt time.Time) models.ClientSyncResult {
+	const op = "service.client.syncClient"
+
+	client := state.Client
+	result := models.ClientSyncResult{ClientID: client.ID, Status: models.SyncStatusSynced}
+
+	defer cs.locks.lock(client.ID)()
+
+	pause := state.Pause
+	if pause == nil && cs.pauses.pausedSince(client.ID, readAt) {
+		var err error
+		if pause, err = cs.repository.Pause(ctx, client.ID); err != nil {
+			cs.log.Errorf("%s: Failed to fetch pause of client %d: %v", op, client.ID, err)
+			result.Status = models.SyncStatusSkipped
+			result.Message = err.Error()
+			return result
+		}
+	}
+	if pause.Active(time.Now()) {
+		cs.log.Debugf("%s: Client %d is paused by %s: %s", op, client.ID, pause.Actor, pause.Reason)
+		result.Status = models.SyncStatusPaused
+		result.Message = pause.Reason
+		result.Pause = pause
+		return result
+	}
+
+	deployer, err := cs.deployerFor(client, clusters)
+	if err != nil {
+		cs.log.Errorf("%s: Failed to resolve cluster for client %d: %v", op, client.ID, err)
+		result.Status = models.SyncStatusSkipped
+		result.Message = err.Error()
+		return result
+	}
+
+	if state.Algorithm == nil {
+		cs.log.Debugf("%s: No algorithm status for client %d", op, client.ID)
+		result.Status = models.SyncStatusSkipped
+		result.Message = "no algorithm status"
+		return result
+	}
+	cs.syncPodsForClient(ctx, deployer, client, *state.Algorithm, inputs)
+
+	return result
+}
+
+// syncDeletedClients deletes the pods left running by deleted clients, because deleting them
+// failed or a synchronization that read the client before it was deleted created them again.
+func (cs *clientService) syncDeletedClients(ctx context.Context, clusters map[int64]*models.Cluster) {
+	const op = "service.client.syncDeletedClients"
+
+	clients, err := cs.repository.DeletedClientsWithPods(ctx)
+	if err != nil {
+		cs.log.Errorf("%s: Failed to fetch deleted clients from database: %v", op, err)
+		return
+	}
+
+	for _, client := range clients {
+		func() {
+			defer cs.locks.lock(client.ID)()
+			cs.deleteClientPods(ctx, client, clusters)
+		}()
+	}
+}
+
+// LastSyncRun returns the per-client results of the last finished synchronization cycle.
+func (cs *clientService) LastSyncRun() *models.SyncRun {
+	return cs.metrics.lastSyncRun()
+}
+
+// SyncMetrics returns the queue depth and per-client latency of the synchronization.
+func (cs *clientService) SyncMetrics() models.SyncMetrics {
+	metrics := cs.metrics.snapshot()
+	metrics.DeployerCalls = cs.deployers.InFlightCalls()
+	return metrics
+}
+
+// clusters returns all registered clusters keyed by ID.
+func (cs *clientService) clusters(ctx context.Context) (map[int64]*models.Cluster, error) {
+	list, err := cs.clusterRepository.Clusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make(map[int64]*models.Cluster, len(list))
+	for i := range list {
+		clusters[list[i].ID] = &list[i]
+	}
+
+	return clusters, nil
+}
+
+// deployerFor returns the deployer of the cluster the client is assigned to,
+// or the default deployer if the client is not assigned to a cluster.
+func (cs *clientService) deployerFor(client models.Client, clusters map[int64]*models.Cluster) (k8s.KubernetesDeployer, error) {
+	if client.ClusterID == nil {
+		return cs.deployers.Default(), nil
+	}
+
+	cluster, ok := clusters[*client.ClusterID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrClusterNotFound, *client.ClusterID)
+	}
+
+	return cs.deployers.Deployer(clusterTarget(cluster)), nil
+}
+
+// syncPodsForClient synchronizes Kubernetes pods for a given client based on their algorithm status.
+// It creates or deletes pods depending on the algorithm status flags VWAP, TWAP, and HFT.
+// For each algorithm type, a pod is created if the corresponding flag is true in algoStatus;
+// otherwise, the pod is deleted.
+// Pod names are generated based on the client's ID and algorithm type (e.g., "vwap-123").
+// Algorithms marked as failed after crash-looping are kept deleted until re-enabled,
+// algorithms whose kill switch is engaged are kept deleted until it is released, and
+// enabled algorithms are only deployed while their run window is open.
+// The pods are built from inputs, or from the records read now if inputs is nil. The observed
+// state is always read now, as it is written back and must not be overwritten with a stale copy.
+// The observed outcome, including any deployer error, is written back as the algorithm state.
+// Callers must hold the client lock.
+func (cs *clientService) syncPodsForClient(ctx context.Context, deployer k8s.KubernetesDeployer, client models.Client, algoStatus models.AlgorithmStatus, inputs *clientInputs) {
+	const op = "service.client.syncPodsForClient"
+
+	if inputs == nil {
+		var err error
+		if inputs, err = cs.clientInputs(ctx, client.ID); err != nil {
+			cs.log.Errorf("%s: Failed to fetch inputs for client %d: %v", op, client.ID, err)
+			return
+		}
+	}
+	if inputs.secretsErr != nil {
+		cs.log.Errorf("%s: Failed to fetch secrets for client %d: %v", op, client.ID, inputs.secretsErr)
+		return
+	}
+	scheduling, secrets, parameters, windows := inputs.scheduling, inputs.secrets, inputs.parameters, inputs.windows
+
+	states, err := cs.repository.AlgorithmStates(ctx, client.ID)
+	if err != nil {
+		cs.log.Errorf("%s: Failed to fetch algorithm states for client %d: %v", op, client.ID, err)
+		return
+	}
+
+	now := time.Now()
+	previous := make(map[string]*models.AlgorithmState, len(states))
+	for i := range states {
+		previous[states[i].Algorithm] = &states[i]
+	}
+
+	for _, algorithm := range models.Algorithms {
+		podName := podName(client.ID, algorithm)
+		label := strings.ToUpper(algorithm)
+		prev := previous[algorithm]
+		enabled := algoStatus.Enabled(algorithm) && !cs.killed.engaged(algorithm)
+		outsideWindow := enabled && !cs.windowOpen(windows, algorithm, now)
+
+		var state models.AlgorithmState
+		switch {
+		case prev != nil && prev.FailedAt != nil:
+			state = cs.deletePod(deployer, client, algorithm, podName, nil)
+			keepFailure(&state, prev)
+			cs.log.Debugf("%s: %s pod for client %d is disabled after crash-looping", op, label, client.ID)
+		case enabled && !outsideWindow:
+			state = cs.deployPod(deployer, podSpec(client, algorithm, podName, scheduling[algorithm], secrets, string(parameters[algorithm].Parameters)), client.ID, algorithm)
+			if state.Reason == reasonKillSwitch {
+				cs.log.Warnf("%s: %s pod for client %d deleted, kill switch engaged while deploying", op, label, client.ID)
+			} else if cs.detectCrashLoop(&state, prev) {
+				cs.disableCrashLoopingPod(deployer, client, &state)
+				cs.log.Errorf("%s: %s pod for client %d disabled: %s", op, label, client.ID, state.LastError)
+			} else if state.Reason == reasonReadyTimeout {
+				cs.log.Warnf("%s: %s pod for client %d not ready yet: %s", op, label, client.ID, state.LastError)
+			} else if state.Phase == models.PhaseFailed {
+				cs.log.Errorf("%s: Failed to deploy %s pod for client %d: %s", op, label, client.ID, state.LastError)
+			} else {
+				cs.log.Debugf("%s: %s pod deployed successfully for client %d", op, label, client.ID)
+			}
+		default:
+			state = cs.deletePod(deployer, client, algorithm, podName, prev)
+			if outsideWindow {
+				state.Reason = reasonOutsideWindow
+			}
+			if state.LastError != "" {
+				cs.log.Errorf("%s: Failed to delete %s pod for client %d: %s", op, label, client.ID, state.LastError)
+			} else {
+				cs.log.Debugf("%s: %s pod deleted successfully for client %d", op, label, client.ID)
+			}
+		}
+
+		if err := cs.repository.SaveAlgorithmState(ctx, &state); err != nil {
+			cs.log.Errorf("%s: Failed to save %s state for client %d: %v", op, label, client.ID, err)
+		}
+	}
+}
+
+// clientInputs holds the records of a client its pods are built from.
+type clientInputs struct {
+	scheduling map[string]models.Scheduling
+	secrets    []models.ClientSecret
+	secretsErr error
+	parameters map[string]models.AlgorithmParameters
+	windows    map[string]models.AlgorithmWindow
+}
+
+// clientInputs reads the records the pods of a client are built from.
+func (cs *clientService) clientInputs(ctx context.Context, clientID int64) (*clientInputs, error) {
+	scheduling, err := cs.Scheduling(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := cs.secrets.Decrypt(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, err := cs.repository.AlgorithmParameters(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := cs.repository.AlgorithmWindows(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientInputs{scheduling: scheduling, secrets: secrets, parameters: parameters, windows: windows}, nil
+}
+
+// clientInputsByClients reads the records the pods of the given clients are built from with one
+// query per kind of record, keyed by client ID. A client whose secrets cannot be decrypted gets
+// the error instead of its secrets, so that it does not fail the other clients.
+func (cs *clientService) clientInputsByClients(ctx context.Context, clientIDs []int64) (map[int64]*clientInputs, error) {
+	overrides, err := cs.repository.SchedulingOverridesByClients(ctx, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := cs.secrets.DecryptClients(ctx, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, err := cs.repository.AlgorithmParametersByClients(ctx, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := cs.repository.AlgorithmWindowsByClients(ctx, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make(map[int64]*clientInputs, len(clientIDs))
+	for _, clientID := range clientIDs {
+		inputs[clientID] = &clientInputs{
+			scheduling: cs.mergeScheduling(overrides[clientID]),
+			secrets:    secrets[clientID].Secrets,
+			secretsErr: secrets[clientID].Err,
+			parameters: parameters[clientID],
+			windows:    windows[clientID],
+		}
+	}
+
+	return inputs, nil
+}
+
+// podName returns the name of the pod of a client algorithm (e.g., "vwap-123").
+func podName(clientID int64, algorithm string) string {
+	return fmt.Sprintf("%s-%d", algorithm, clientID)
+}
+
+// podSpec builds the pod spec of a client algorithm with its labels, environment,
+// secrets, parameters, resources and placement constraints. CPU and memory values that are not valid
+// Kubernetes quantities are left out so that the pod can still be created.
+func podSpec(client models.Client, algorithm, podName string, scheduling models.Scheduling, secrets []models.ClientSecret, parameters string) k8s.PodSpec {
+	spec := k8s.PodSpec{
+		Name:  podName,
+		Image: client.Image,
+		Labels: map[string]string{
+			labelApp:       appName,
+			labelClientID:  strconv.FormatInt(client.ID, 10),
+			labelAlgorithm: algorithm,
+		},
+		Env: []k8s.EnvVar{
+			{Name: "ALGOSYNC_CLIENT_ID", Value: strconv.FormatInt(client.ID, 10)},
+			{Name: "ALGOSYNC_CLIENT_VERSION", Value: strconv.Itoa(client.Version)},
+			{Name: "ALGOSYNC_ALGORITHM", Value: algorithm},
+		},
+		Parameters:   parameters,
+		NodeSelector: scheduling.NodeSelector,
+	}
+
+	if parameters != "" {
+		spec.Env = append(spec.Env, parametersEnv())
+	}
+
+	for _, secret := range secrets {
+		env := k8s.EnvVar{Name: secret.Name, Value: secret.Value}
+		if secret.Injection == models.SecretInjectionEnv {
+			spec.Env = append(spec.Env, env)
+		} else {
+			spec.SecretEnv = append(spec.SecretEnv, env)
+		}
+	}
+
+	if k8s.IsQuantity(client.CPU) {
+		spec.Resources.CPU = client.CPU
+	}
+	if k8s.IsQuantity(client.Memory) {
+		spec.Resources.Memory = client.Memory
+	}
+
+	for _, r := range scheduling.NodeAffinity {
+		spec.NodeAffinity = append(spec.NodeAffinity, k8s.NodeSelectorRequirement{
+			Key:      r.Key,
+			Operator: r.Operator,
+			Values:   r.Values,
+		})
+	}
+
+	for _, t := range scheduling.Tolerations {
+		spec.Tolerations = append(spec.Tolerations, k8s.Toleration{
+			Key:               t.Key,
+			Operator:          t.Operator,
+			Value:             t.Value,
+			Effect:            t.Effect,
+			TolerationSeconds: t.TolerationSeconds,
+		})
+	}
+
+	return spec
+}
+
+// deployPod creates the algorithm pod, waits for it to become ready if readiness
+// waiting is enabled and converts the outcome into the observed algorithm state.
+// The wait is capped at maxReadyTimeout; a pod that is not ready by then keeps its
+// observed phase with reason ReadyTimeout and is not marked as failed.
+// When crash-loop detection is enabled without readiness waiting, the pod status
+// is still fetched to observe its restart count. If the kill switch of the algorithm is
+// engaged meanwhile, the pod is deleted again.
+func (cs *clientService) deployPod(deployer k8s.KubernetesDeployer, spec k8s.PodSpec, clientID int64, algorithm string) models.AlgorithmState {
+	podName := spec.Name
+	state := models.AlgorithmState{
+		ClientID:  clientID,
+		Algorithm: algorithm,
+		Phase:     models.PhaseCreated,
+		PodName:   podName,
+		Image:     spec.Image,
+	}
+
+	if err := deployer.CreatePod(spec); err != nil {
+		state.Phase = models.PhaseFailed
+		state.LastError = err.Error()
+		state.LastSyncedAt = time.Now()
+		return state
+	}
+
+	// The kill switch may have been engaged while the pod was created, after its pods were deleted.
+	if cs.killed.engaged(algorithm) {
+		return cs.killPod(deployer, state)
+	}
+
+	var status *k8s.PodStatus
+	var err error
+	if cs.config.ReadyTimeout > 0 {
+		status, err = deployer.WaitForPodReady(podName, min(cs.config.ReadyTimeout, maxReadyTimeout))
+	} else if cs.config.RestartThreshold > 0 {
+		status, err = deployer.PodStatus(podName)
+	}
+
+	if status != nil {
+		state.Phase = status.Phase
+		state.Ready = status.Ready
+		state.Reason = status.Reason
+		state.RestartCount = status.RestartCount
+		if status.Image != "" {
+			state.Image = status.Image
+		}
+		if !status.StartedAt.IsZero() {
+			startedAt := status.StartedAt
+			state.StartedAt = &startedAt
+		}
+	}
+	if errors.Is(err, k8s.ErrPodNotReady) {
+		// The pod may still start, the next cycle observes it again.
+		if status == nil {
+			state.Phase = models.PhaseCreated
+		}
+		state.Ready = false
+		state.Reason = reasonReadyTimeout
+		state.LastError = err.Error()
+	} else if err != nil {
+		state.Phase = models.PhaseFailed
+		state.Ready = false
+		state.LastError = err.Error()
+	}
+
+	if cs.killed.engaged(algorithm) {
+		return cs.killPod(deployer, state)
+	}
+
+	state.LastSyncedAt = time.Now()
+	return state
+}
+
+// killPod deletes a pod created while the kill switch of its algorithm was engaged
+// and returns the resulting algorithm state.
+func (cs *clientService) killPod(deployer k8s.KubernetesDeployer, created models.AlgorithmState) models.AlgorithmState {
+	state := models.AlgorithmState{
+		ClientID:  created.ClientID,
+		Algorithm: created.Algorithm,
+		Phase:     models.PhaseDeleted,
+		PodName:   created.PodName,
+		Image:     created.Image,
+		Reason:    reasonKillSwitch,
+	}
+
+	if err := deployer.DeletePod(state.PodName); err != nil {
+		state.Phase = models.PhaseUnknown
+		state.LastError = err.Error()
+	}
+
+	state.LastSyncedAt = time.Now()
+	return state
+}
+
+// deletePod removes the pod of a disabled algorithm and returns the resulting algorithm state.
+// The deployer is not called if the previous state shows that the pod was already deleted,
+// so that disabled algorithms do not cost a kubectl call on every synchronization.
+func (cs *clientService) deletePod(deployer k8s.KubernetesDeployer, client models.Client, algorithm, podName string, prev *models.AlgorithmState) models.AlgorithmState {
+	state := models.AlgorithmState{
+		ClientID:  client.ID,
+		Algorithm: algorithm,
+		Phase:     models.PhaseDeleted,
+		PodName:   podName,
+		Image:     client.Image,
+	}
+
+	if podDeleted(prev, podName) {
+		state.LastSyncedAt = time.Now()
+		return state
+	}
+
+	if err := deployer.DeletePod(podName); err != nil {
+		state.Phase = models.PhaseUnknown
+		state.LastError = err.Error()
+	}
+
+	state.LastSyncedAt = time.Now()
+	return state
+}
+
+// podDeleted reports whether the observed state shows that the pod was deleted without error.
+func podDeleted(prev *models.AlgorithmState, podName string) bool {
+	return prev != nil && prev.Phase == models.PhaseDeleted && prev.LastError == "" && prev.PodName == podName
+}
+
+// detectCrashLoop updates the restart window of the state and reports whether the pod
+// restarted more than the configured threshold within the window.
+// The window restarts when it expires or when the restart count drops, which means
+// the pod was recreated.
+func (cs *clientService) detectCrashLoop(state, prev *models.AlgorithmState) bool {
+	if cs.config.RestartThreshold <= 0 {
+		return false
+	}
+
+	now := state.LastSyncedAt
+	start, base := now, state.RestartCount
+	if prev != nil && prev.RestartWindowStart != nil && prev.RestartWindowBase <= state.RestartCount &&
+		(cs.config.RestartWindow <= 0 || now.Sub(*prev.RestartWindowStart) < cs.config.RestartWindow) {
+		start, base = *prev.RestartWindowStart, prev.RestartWindowBase
+	}
+
+	state.RestartWindowStart = &start
+	state.RestartWindowBase = base
+
+	return state.RestartCount-base > cs.config.RestartThreshold
+}
+
+// disableCrashLoopingPod deletes the pod of a crash-looping algorithm, marks the
+// algorithm as failed and notifies operators.
+func (cs *clientService) disableCrashLoopingPod(deployer k8s.KubernetesDeployer, client models.Client, state *models.AlgorithmState) {
+	const op = "service.client.disableCrashLoopingPod"
+
+	restarts := state.RestartCount - state.RestartWindowBase
+	failedAt := state.LastSyncedAt
+
+	state.Phase = models.PhaseFailed
+	state.Ready = false
+	state.Reason = models.ReasonCrashLoop
+	state.LastError = fmt.Sprintf("pod restarted %d times since %s", restarts, state.RestartWindowStart.Format(time.RFC3339))
+	state.FailedAt = &failedAt
+
+	if err := deployer.DeletePod(state.PodName); err != nil {
+		state.LastError = fmt.Sprintf("%s; failed to delete pod: %v", state.LastError, err)
+	}
+
+	event := notify.Event{
+		Type:      notify.EventAlgorithmCrashLoop,
+		ClientID:  client.ID,
+		Algorithm: state.Algorithm,
+		Message:   state.LastError,
+		Time:      failedAt,
+	}
+	if err := cs.notifier.Notify(event); err != nil {
+		cs.log.Errorf("%s: Failed to send notification for client %d: %v", op, client.ID, err)
+	}
+}
+
+// keepFailure carries the crash-loop failure mark of the previous state over to the new state.
+func keepFailure(state, prev *models.AlgorithmState) {
+	state.Phase = models.PhaseFailed
+	state.Reason = prev.Reason
+	state.RestartCount = prev.RestartCount
+	state.FailedAt = prev.FailedAt
+	if state.LastError == "" {
+		state.LastError = prev.LastError
+	}
+}
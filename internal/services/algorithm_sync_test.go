@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"test-task/infra/k8s"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/util/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCrashLoop(t *testing.T) {
+	cs := &clientService{config: SyncConfig{RestartThreshold: 3, RestartWindow: 10 * time.Minute}}
+	now := time.Now()
+	windowStart := now.Add(-5 * time.Minute)
+
+	tests := []struct {
+		name      string
+		prev      *models.AlgorithmState
+		restarts  int
+		crashLoop bool
+		base      int
+	}{
+		{name: "first observation", prev: nil, restarts: 10, crashLoop: false, base: 10},
+		{name: "below threshold", prev: &models.AlgorithmState{RestartWindowStart: &windowStart, RestartWindowBase: 2}, restarts: 5, crashLoop: false, base: 2},
+		{name: "above threshold", prev: &models.AlgorithmState{RestartWindowStart: &windowStart, RestartWindowBase: 2}, restarts: 6, crashLoop: true, base: 2},
+		{name: "pod recreated", prev: &models.AlgorithmState{RestartWindowStart: &windowStart, RestartWindowBase: 8}, restarts: 1, crashLoop: false, base: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &models.AlgorithmState{RestartCount: tt.restarts, LastSyncedAt: now}
+
+			assert.Equal(t, tt.crashLoop, cs.detectCrashLoop(state, tt.prev))
+			assert.Equal(t, tt.base, state.RestartWindowBase)
+		})
+	}
+}
+
+func TestDetectCrashLoop_WindowExpired(t *testing.T) {
+	cs := &clientService{config: SyncConfig{RestartThreshold: 3, RestartWindow: 10 * time.Minute}}
+	now := time.Now()
+	windowStart := now.Add(-15 * time.Minute)
+
+	state := &models.AlgorithmState{RestartCount: 20, LastSyncedAt: now}
+	prev := &models.AlgorithmState{RestartWindowStart: &windowStart, RestartWindowBase: 0}
+
+	assert.False(t, cs.detectCrashLoop(state, prev))
+	assert.Equal(t, now, *state.RestartWindowStart)
+	assert.Equal(t, 20, state.RestartWindowBase)
+}
+
+func TestSyncClient_Paused(t *testing.T) {
+	cs := &clientService{
+		deployers: k8s.NewStaticDeployerFactory(nil),
+		locks:     newClientLocks(),
+		pauses:    newPauseLog(),
+		log:       logger.GetLogger(),
+	}
+
+	pause := &models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice"}
+	state := models.DesiredState{Client: models.Client{ID: 1}, Pause: pause}
+
+	result := cs.syncClient(context.Background(), state, nil, nil, time.Now())
+	assert.Equal(t, models.ClientSyncResult{ClientID: 1, Status: models.SyncStatusPaused, Message: "debugging", Pause: pause}, result)
+
+	expired := time.Now().Add(-time.Minute)
+	state.Pause = &models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice", ExpiresAt: &expired}
+
+	result = cs.syncClient(context.Background(), state, nil, nil, time.Now())
+	assert.Equal(t, models.SyncStatusSkipped, result.Status)
+	assert.Nil(t, result.Pause)
+}
+
+// fakeDeployer is a deployer whose pod calls are given by functions, unset calls succeed.
+// Created and deleted pods are recorded.
+type fakeDeployer struct {
+	k8s.KubernetesDeployer
+	createPod       func(spec k8s.PodSpec) error
+	waitForPodReady func(name string, timeout time.Duration) (*k8s.PodStatus, error)
+
+	mu      sync.Mutex
+	created []k8s.PodSpec
+	deleted []string
+}
+
+func (d *fakeDeployer) CreatePod(spec k8s.PodSpec) error {
+	d.mu.Lock()
+	d.created = append(d.created, spec)
+	d.mu.Unlock()
+
+	if d.createPod == nil {
+		return nil
+	}
+	return d.createPod(spec)
+}
+
+func (d *fakeDeployer) WaitForPodReady(name string, timeout time.Duration) (*k8s.PodStatus, error) {
+	if d.waitForPodReady == nil {
+		return &k8s.PodStatus{Phase: "Running", Ready: true}, nil
+	}
+	return d.waitForPodReady(name, timeout)
+}
+
+func (d *fakeDeployer) DeletePod(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deleted = append(d.deleted, name)
+	return nil
+}
+
+func TestDeployPod_KillSwitchEngaged(t *testing.T) {
+	tests := []struct {
+		name    string
+		engage  func(cs *clientService, deployer *fakeDeployer)
+		deleted []string
+	}{
+		{
+			name:   "not engaged",
+			engage: func(cs *clientService, deployer *fakeDeployer) {},
+		},
+		{
+			name: "engaged while creating",
+			engage: func(cs *clientService, deployer *fakeDeployer) {
+				deployer.createPod = func(spec k8s.PodSpec) error {
+					cs.killed.engage([]string{models.AlgorithmHFT})
+					return nil
+				}
+				deployer.waitForPodReady = func(name string, timeout time.Duration) (*k8s.PodStatus, error) {
+					t.Fatal("waited for a killed pod")
+					return nil, nil
+				}
+			},
+			deleted: []string{"hft-1"},
+		},
+		{
+			name: "engaged while waiting",
+			engage: func(cs *clientService, deployer *fakeDeployer) {
+				deployer.waitForPodReady = func(name string, timeout time.Duration) (*k8s.PodStatus, error) {
+					cs.killed.engage([]string{models.AlgorithmHFT})
+					return &k8s.PodStatus{Phase: "Running", Ready: true}, nil
+				}
+			},
+			deleted: []string{"hft-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &clientService{config: SyncConfig{ReadyTimeout: time.Minute}, killed: newKilledAlgorithms()}
+			deployer := &fakeDeployer{}
+			tt.engage(cs, deployer)
+
+			state := cs.deployPod(deployer, k8s.PodSpec{Name: "hft-1", Image: "image"}, 1, models.AlgorithmHFT)
+
+			assert.Equal(t, tt.deleted, deployer.deleted)
+			if tt.deleted == nil {
+				assert.Equal(t, "Running", state.Phase)
+				assert.True(t, state.Ready)
+			} else {
+				assert.Equal(t, models.PhaseDeleted, state.Phase)
+				assert.Equal(t, reasonKillSwitch, state.Reason)
+				assert.False(t, state.Ready)
+			}
+		})
+	}
+}
+
+func TestDeployPod_ReadyWait(t *testing.T) {
+	pending := &k8s.PodStatus{Name: "vwap-1", Phase: "Pending", Reason: "ContainerCreating", Image: "image"}
+
+	tests := []struct {
+		name         string
+		readyTimeout time.Duration
+		status       *k8s.PodStatus
+		err          error
+		phase        string
+		reason       string
+		lastError    string
+	}{
+		{
+			name:         "ready",
+			readyTimeout: time.Minute,
+			status:       &k8s.PodStatus{Name: "vwap-1", Phase: "Running", Ready: true},
+			phase:        "Running",
+		},
+		{
+			name:         "timeout while pending",
+			readyTimeout: time.Minute,
+			status:       pending,
+			err:          fmt.Errorf("%w: pod vwap-1 not ready within 30s", k8s.ErrPodNotReady),
+			phase:        "Pending",
+			reason:       reasonReadyTimeout,
+			lastError:    "pod not ready: pod vwap-1 not ready within 30s",
+		},
+		{
+			name:         "timeout before the pod appears",
+			readyTimeout: 10 * time.Second,
+			err:          fmt.Errorf("%w: pod vwap-1 did not appear within 10s", k8s.ErrPodNotReady),
+			phase:        models.PhaseCreated,
+			reason:       reasonReadyTimeout,
+			lastError:    "pod not ready: pod vwap-1 did not appear within 10s",
+		},
+		{
+			name:         "failed",
+			readyTimeout: time.Minute,
+			status:       &k8s.PodStatus{Name: "vwap-1", Phase: "Pending", Reason: "ImagePullBackOff"},
+			err:          errors.New("pod vwap-1 failed: ImagePullBackOff"),
+			phase:        models.PhaseFailed,
+			reason:       "ImagePullBackOff",
+			lastError:    "pod vwap-1 failed: ImagePullBackOff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &clientService{config: SyncConfig{ReadyTimeout: tt.readyTimeout}, killed: newKilledAlgorithms()}
+			var waited time.Duration
+			deployer := &fakeDeployer{waitForPodReady: func(name string, timeout time.Duration) (*k8s.PodStatus, error) {
+				waited = timeout
+				return tt.status, tt.err
+			}}
+
+			state := cs.deployPod(deployer, k8s.PodSpec{Name: "vwap-1", Image: "image"}, 1, models.AlgorithmVWAP)
+
+			assert.Equal(t, min(tt.readyTimeout, maxReadyTimeout), waited)
+			assert.Equal(t, tt.phase, state.Phase)
+			assert.Equal(t, tt.reason, state.Reason)
+			assert.Equal(t, tt.lastError, state.LastError)
+			assert.Equal(t, tt.err == nil, state.Ready)
+			assert.Empty(t, deployer.deleted)
+		})
+	}
+}
+
+// fakeClientRepository serves the desired state and the records of a synchronization page.
+// Reading records per client is not expected and panics.
+type fakeClientRepository struct {
+	repository.ClientRepository
+	states     []models.DesiredState
+	parameters map[int64]map[string]models.AlgorithmParameters
+	observed   map[int64][]models.AlgorithmState
+
+	mu    sync.Mutex
+	saved []models.AlgorithmState
+}
+
+func (r *fakeClientRepository) DesiredStates(ctx context.Context, afterID int64, limit int) ([]models.DesiredState, error) {
+	return r.states, nil
+}
+
+func (r *fakeClientRepository) SchedulingOverridesByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.Scheduling, error) {
+	return nil, nil
+}
+
+func (r *fakeClientRepository) AlgorithmParametersByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmParameters, error) {
+	return r.parameters, nil
+}
+
+func (r *fakeClientRepository) AlgorithmWindowsByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmWindow, error) {
+	return nil, nil
+}
+
+func (r *fakeClientRepository) AlgorithmStates(ctx context.Context, clientID int64) ([]models.AlgorithmState, error) {
+	return r.observed[clientID], nil
+}
+
+func (r *fakeClientRepository) SaveAlgorithmState(ctx context.Context, state *models.AlgorithmState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.saved = append(r.saved, *state)
+	return nil
+}
+
+func (r *fakeClientRepository) DeletedClientsWithPods(ctx context.Context) ([]models.Client, error) {
+	return nil, nil
+}
+
+type fakeClusterRepository struct {
+	repository.ClusterRepository
+}
+
+func (r *fakeClusterRepository) Clusters(ctx context.Context) ([]models.Cluster, error) {
+	return nil, nil
+}
+
+type fakeKillSwitchRepository struct {
+	repository.KillSwitchRepository
+}
+
+func (r *fakeKillSwitchRepository) KillSwitches(ctx context.Context) ([]models.KillSwitch, error) {
+	return nil, nil
+}
+
+type fakeSecretService struct {
+	SecretService
+	decrypted map[int64]DecryptedSecrets
+}
+
+func (s *fakeSecretService) DecryptClients(ctx context.Context, clientIDs []int64) (map[int64]DecryptedSecrets, error) {
+	return s.decrypted, nil
+}
+
+func TestSyncAlgorithms_PageRecords(t *testing.T) {
+	repo := &fakeClientRepository{
+		states: []models.DesiredState{
+			{Client: models.Client{ID: 1, Image: "image"}, Algorithm: &models.AlgorithmStatus{ClientID: 1, VWAP: true}},
+			{Client: models.Client{ID: 2, Image: "image"}, Algorithm: &models.AlgorithmStatus{ClientID: 2, HFT: true}},
+		},
+		parameters: map[int64]map[string]models.AlgorithmParameters{
+			1: {models.AlgorithmVWAP: {ClientID: 1, Algorithm: models.AlgorithmVWAP, Parameters: []byte(`{"slices":10}`)}},
+		},
+	}
+	secrets := &fakeSecretService{decrypted: map[int64]DecryptedSecrets{
+		1: {Secrets: []models.ClientSecret{{ClientID: 1, Name: "API_KEY", Value: "key", Injection: models.SecretInjectionEnv}}},
+		2: {Err: errors.New("message authentication failed")},
+	}}
+	deployer := &fakeDeployer{}
+	cs := NewClientService(repo, &fakeClusterRepository{}, &fakeKillSwitchRepository{}, nil, secrets, k8s.NewStaticDeployerFactory(deployer), nil, SyncConfig{Workers: 2}).(*clientService)
+
+	cs.syncAlgorithms()
+
+	assert.Len(t, deployer.created, 1)
+	assert.Equal(t, "vwap-1", deployer.created[0].Name)
+	assert.Equal(t, `{"slices":10}`, deployer.created[0].Parameters)
+	assert.Contains(t, deployer.created[0].Env, k8s.EnvVar{Name: "API_KEY", Value: "key"})
+	assert.ElementsMatch(t, []string{"twap-1", "hft-1"}, deployer.deleted)
+
+	// The client whose secrets cannot be decrypted is left untouched.
+	assert.Len(t, repo.saved, len(models.Algorithms))
+	for _, state := range repo.saved {
+		assert.Equal(t, int64(1), state.ClientID)
+	}
+}
+
+func TestSyncAlgorithms_SkipsDeletedPods(t *testing.T) {
+	repo := &fakeClientRepository{
+		states: []models.DesiredState{
+			{Client: models.Client{ID: 1, Image: "image"}, Algorithm: &models.AlgorithmStatus{ClientID: 1}},
+		},
+		observed: map[int64][]models.AlgorithmState{
+			1: {
+				{ClientID: 1, Algorithm: models.AlgorithmVWAP, Phase: models.PhaseDeleted, PodName: "vwap-1"},
+				{ClientID: 1, Algorithm: models.AlgorithmTWAP, Phase: models.PhaseUnknown, PodName: "twap-1", LastError: "connection refused"},
+				{ClientID: 1, Algorithm: models.AlgorithmHFT, Phase: "Running", PodName: "hft-1"},
+			},
+		},
+	}
+	deployer := &fakeDeployer{}
+	cs := NewClientService(repo, &fakeClusterRepository{}, &fakeKillSwitchRepository{}, nil, &fakeSecretService{}, k8s.NewStaticDeployerFactory(deployer), nil, SyncConfig{Workers: 1}).(*clientService)
+
+	cs.syncAlgorithms()
+
+	// The pod already observed deleted is not deleted again, the others are.
+	assert.ElementsMatch(t, []string{"twap-1", "hft-1"}, deployer.deleted)
+	assert.Len(t, repo.saved, len(models.Algorithms))
+	for _, state := range repo.saved {
+		assert.Equal(t, models.PhaseDeleted, state.Phase, state.Algorithm)
+		assert.Empty(t, state.LastError, state.Algorithm)
+	}
+}
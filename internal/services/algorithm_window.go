@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/pkg/calendar"
+	"test-task/pkg/cron"
+	"time"
+)
+
+// maxWindowMargin bounds how early a pod is started before its window opens
+// and how late it is stopped after the window closes.
+const maxWindowMargin = 24 * 60
+
+// reasonOutsideWindow is the reason recorded for the pod of an enabled algorithm
+// deleted because its run window is closed.
+const reasonOutsideWindow = "OutsideWindow"
+
+var (
+	// ErrWindowNotFound is returned when no run window was set for a client algorithm.
+	ErrWindowNotFound = domain.New(domain.NotFound, "window_not_found", "window not found")
+	// ErrInvalidWindow is returned when a run window is malformed.
+	ErrInvalidWindow = domain.New(domain.Validation, "invalid_window", "invalid window")
+)
+
+// AlgorithmWindows returns the run windows of a client keyed by algorithm type.
+func (cs *clientService) AlgorithmWindows(ctx context.Context, clientID int64) (map[string]models.AlgorithmWindow, error) {
+	return cs.repository.AlgorithmWindows(ctx, clientID)
+}
+
+// SetAlgorithmWindow validates and stores the run window of a client algorithm.
+// The synchronization starts and stops the pod of the algorithm as the window opens and closes.
+func (cs *clientService) SetAlgorithmWindow(ctx context.Context, window *models.AlgorithmWindow) error {
+	if !models.IsAlgorithm(window.Algorithm) {
+		return fmt.Errorf("unknown algorithm %q", window.Algorithm)
+	}
+
+	if err := cs.validateWindow(window); err != nil {
+		return err
+	}
+
+	if _, err := cs.repository.ClientByID(window.ClientID); err != nil {
+		return err
+	}
+
+	return cs.repository.SaveAlgorithmWindow(ctx, window)
+}
+
+// DeleteAlgorithmWindow removes the run window of a client algorithm,
+// so the algorithm runs whenever it is enabled.
+func (cs *clientService) DeleteAlgorithmWindow(ctx context.Context, clientID int64, algorithm string) error {
+	if !models.IsAlgorithm(algorithm) {
+		return fmt.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	windows, err := cs.repository.AlgorithmWindows(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if _, ok := windows[algorithm]; !ok {
+		return ErrWindowNotFound
+	}
+
+	return cs.repository.DeleteAlgorithmWindow(ctx, clientID, algorithm)
+}
+
+// Calendars returns the trading calendars run windows can refer to.
+func (cs *clientService) Calendars() map[string]*calendar.Calendar {
+	return cs.config.Calendars
+}
+
+// validateWindow checks that exactly one of cron and calendar is set and can be evaluated.
+func (cs *clientService) validateWindow(window *models.AlgorithmWindow) error {
+	if (window.Cron == "") == (window.Calendar == "") {
+		return fmt.Errorf("%w: exactly one of cron and calendar must be set", ErrInvalidWindow)
+	}
+
+	if window.StartBefore < 0 || window.StartBefore > maxWindowMargin || window.StopAfter < 0 || window.StopAfter > maxWindowMargin {
+		return fmt.Errorf("%w: start_before and stop_after must be between 0 and %d minutes", ErrInvalidWindow, maxWindowMargin)
+	}
+
+	if window.Calendar != "" {
+		if window.Timezone != "" {
+			return fmt.Errorf("%w: the timezone of a calendar window is the timezone of the calendar", ErrInvalidWindow)
+		}
+		if _, ok := cs.config.Calendars[window.Calendar]; !ok {
+			return fmt.Errorf("%w: unknown calendar %q", ErrInvalidWindow, window.Calendar)
+		}
+		return nil
+	}
+
+	if _, err := cron.Parse(window.Cron); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWindow, err)
+	}
+	if _, err := cs.windows.location(window.Timezone); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWindow, err)
+	}
+
+	return nil
+}
+
+// windowOpen reports whether the run window of a client algorithm is open at the given time.
+// Algorithms without a window run whenever they are enabled; so do algorithms whose window
+// cannot be evaluated, for example because its calendar was removed, which is logged.
+func (cs *clientService) windowOpen(windows map[string]models.AlgorithmWindow, algorithm string, now time.Time) bool {
+	const op = "service.client.windowOpen"
+
+	window, ok := windows[algorithm]
+	if !ok {
+		return true
+	}
+
+	open, err := cs.windows.open(window, cs.config.Calendars, now)
+	if err != nil {
+		cs.log.Errorf("%s: Failed to evaluate %s window of client %d, ignoring it: %v", op, algorithm, window.ClientID, err)
+		return true
+	}
+
+	return open
+}
+
+// windowEvaluator evaluates run windows, caching parsed cron expressions and time zones
+// since every window is evaluated on every synchronization.
+type windowEvaluator struct {
+	mu        sync.Mutex
+	schedules map[string]*cron.Schedule
+	locations map[string]*time.Location
+}
+
+func newWindowEvaluator() *windowEvaluator {
+	return &windowEvaluator{
+		schedules: make(map[string]*cron.Schedule),
+		locations: make(map[string]*time.Location),
+	}
+}
+
+// open reports whether the window, extended by its start and stop margins, is open at now.
+func (e *windowEvaluator) open(window models.AlgorithmWindow, calendars map[string]*calendar.Calendar, now time.Time) (bool, error) {
+	before := time.Duration(window.StartBefore) * time.Minute
+	after := time.Duration(window.StopAfter) * time.Minute
+
+	if window.Calendar != "" {
+		cal, ok := calendars[window.Calendar]
+		if !ok {
+			return false, fmt.Errorf("unknown calendar %q", window.Calendar)
+		}
+		return cal.InSession(now, before, after), nil
+	}
+
+	schedule, err := e.schedule(window.Cron)
+	if err != nil {
+		return false, err
+	}
+	location, err := e.location(window.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	// The pod runs now if a window minute starts within the next StartBefore
+	// minutes or ended within the last StopAfter minutes.
+	next := schedule.Next(now.Add(-after).In(location))
+
+	return !next.IsZero() && !next.After(now.Add(before)), nil
+}
+
+func (e *windowEvaluator) schedule(expr string) (*cron.Schedule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if schedule, ok := e.schedules[expr]; ok {
+		return schedule, nil
+	}
+
+	schedule, err := cron.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	e.schedules[expr] = schedule
+
+	return schedule, nil
+}
+
+// location loads an IANA time zone, UTC if name is empty.
+func (e *windowEvaluator) location(name string) (*time.Location, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if location, ok := e.locations[name]; ok {
+		return location, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	e.locations[name] = location
+
+	return location, nil
+}
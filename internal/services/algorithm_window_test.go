@@ -0,0 +1,67 @@
+package service
+
+import (
+	"test-task/internal/models"
+	"test-task/pkg/calendar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowEvaluator_Cron(t *testing.T) {
+	e := newWindowEvaluator()
+	window := models.AlgorithmWindow{Cron: "30-59 9 * * MON-FRI", Timezone: "America/New_York", StartBefore: 5, StopAfter: 10}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	for at, want := range map[string]bool{
+		"2026-10-19 09:24": false,
+		"2026-10-19 09:25": true,
+		"2026-10-19 09:45": true,
+		"2026-10-19 10:09": true,
+		"2026-10-19 10:10": false,
+		"2026-10-18 09:45": false,
+	} {
+		now, err := time.ParseInLocation("2006-01-02 15:04", at, newYork)
+		assert.NoError(t, err)
+
+		open, err := e.open(window, nil, now.UTC())
+		assert.NoError(t, err)
+		assert.Equal(t, want, open, at)
+	}
+}
+
+func TestWindowEvaluator_Calendar(t *testing.T) {
+	nyse, err := calendar.New(calendar.Spec{
+		Timezone: "America/New_York",
+		Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+		Open:     "09:30",
+		Close:    "16:00",
+		Holidays: []string{"2026-11-26"},
+	})
+	assert.NoError(t, err)
+	calendars := map[string]*calendar.Calendar{"nyse": nyse}
+
+	e := newWindowEvaluator()
+	window := models.AlgorithmWindow{Calendar: "nyse", StartBefore: 15, StopAfter: 15}
+
+	for at, want := range map[string]bool{
+		"2026-10-19T13:14:00Z": false,
+		"2026-10-19T13:15:00Z": true,
+		"2026-10-19T20:14:00Z": true,
+		"2026-10-19T20:15:00Z": false,
+		"2026-11-26T16:00:00Z": false,
+	} {
+		now, err := time.Parse(time.RFC3339, at)
+		assert.NoError(t, err)
+
+		open, err := e.open(window, calendars, now)
+		assert.NoError(t, err)
+		assert.Equal(t, want, open, at)
+	}
+
+	_, err = e.open(models.AlgorithmWindow{Calendar: "lse"}, calendars, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+)
+
+// ErrInvalidClientQuery is returned when a client listing request is malformed.
+var ErrInvalidClientQuery = domain.New(domain.Validation, "invalid_client_query", "invalid client query")
+
+// Page sizes of the client listing.
+const (
+	defaultClientPageSize = 50
+	maxClientPageSize     = 500
+)
+
+// ListClients returns a page of the clients matching the filter of the request, sorted by
+// its sort key and order. Pages are addressed with opaque cursors rather than offsets, so
+// that clients created or deleted between requests do not shift pages. A cursor is only
+// valid with the sort key and order of the page it was returned with.
+func (cs *clientService) ListClients(ctx context.Context, request models.ClientListRequest) (*models.ClientPage, error) {
+	query, err := clientQuery(&request)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.listClients(ctx, request, query)
+}
+
+// DeletedClients returns a page of the deleted clients that have not been purged yet,
+// with the filters, sorting and cursors of ListClients.
+func (cs *clientService) DeletedClients(ctx context.Context, request models.ClientListRequest) (*models.ClientPage, error) {
+	query, err := clientQuery(&request)
+	if err != nil {
+		return nil, err
+	}
+	query.Deleted = true
+
+	return cs.listClients(ctx, request, query)
+}
+
+// listClients returns the page of the validated query.
+func (cs *clientService) listClients(ctx context.Context, request models.ClientListRequest, query models.ClientQuery) (*models.ClientPage, error) {
+	// Fetch one client more than requested to know whether there is a next page.
+	query.Limit++
+	clients, total, err := cs.repository.ListClients(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ClientPage{Items: clients, Total: total}
+	if len(clients) == query.Limit {
+		page.Items = clients[:len(clients)-1]
+		page.NextCursor = encodeClientCursor(request.Sort, request.Order, page.Items[len(page.Items)-1])
+	}
+
+	return page, nil
+}
+
+// clientQuery validates a client listing request, filling in the default sort key,
+// order and page size.
+func clientQuery(request *models.ClientListRequest) (models.ClientQuery, error) {
+	if request.Sort == "" {
+		request.Sort = models.ClientSortID
+	}
+	if request.Order == "" {
+		request.Order = "asc"
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultClientPageSize
+	}
+
+	query := models.ClientQuery{Filter: request.ClientFilter, Sort: request.Sort, Limit: request.Limit}
+
+	switch request.Sort {
+	case models.ClientSortID, models.ClientSortClientName, models.ClientSortVersion, models.ClientSortPriority:
+	default:
+		return query, fmt.Errorf("%w: unknown sort key %q", ErrInvalidClientQuery, request.Sort)
+	}
+
+	switch request.Order {
+	case "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return query, fmt.Errorf("%w: order must be asc or desc", ErrInvalidClientQuery)
+	}
+
+	if request.Limit < 1 || request.Limit > maxClientPageSize {
+		return query, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidClientQuery, maxClientPageSize)
+	}
+
+	filter := request.ClientFilter
+	if filter.Algorithm != "" && !models.IsAlgorithm(filter.Algorithm) {
+		return query, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidClientQuery, filter.Algorithm)
+	}
+	if filter.PriorityMin != nil && filter.PriorityMax != nil && *filter.PriorityMin > *filter.PriorityMax {
+		return query, fmt.Errorf("%w: priority_min is greater than priority_max", ErrInvalidClientQuery)
+	}
+
+	if request.Cursor != "" {
+		cursor, err := decodeClientCursor(request.Cursor)
+		if err != nil {
+			return query, err
+		}
+		if cursor.Sort != request.Sort || cursor.Order != request.Order {
+			return query, fmt.Errorf("%w: cursor was returned for sort %s %s", ErrInvalidClientQuery, cursor.Sort, cursor.Order)
+		}
+		query.After = cursor
+	}
+
+	return query, nil
+}
+
+// encodeClientCursor returns the cursor of the page following the given client.
+func encodeClientCursor(sort, order string, last models.Client) string {
+	cursor := models.ClientCursor{Sort: sort, Order: order, ID: last.ID}
+	switch sort {
+	case models.ClientSortClientName:
+		cursor.Value = last.ClientName
+	case models.ClientSortVersion:
+		cursor.Value = last.Version
+	case models.ClientSortPriority:
+		cursor.Value = last.Priority
+	default:
+		cursor.Value = last.ID
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeClientCursor parses a cursor returned by encodeClientCursor and checks
+// that its value has the type of its sort key.
+func decodeClientCursor(value string) (*models.ClientCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidClientQuery)
+	}
+
+	var cursor models.ClientCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidClientQuery)
+	}
+
+	valid := false
+	switch v := cursor.Value.(type) {
+	case string:
+		valid = cursor.Sort == models.ClientSortClientName
+	case float64:
+		switch cursor.Sort {
+		case models.ClientSortID, models.ClientSortVersion:
+			cursor.Value, valid = int64(v), v == float64(int64(v))
+		case models.ClientSortPriority:
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidClientQuery)
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"test-task/infra/k8s"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClientService_ListClients_Pages(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	algorithm := models.AlgorithmHFT
+	first := []models.Client{{ID: 3, Priority: 9}, {ID: 1, Priority: 7}, {ID: 2, Priority: 7}}
+	mockRepo.On("ListClients", mock.Anything, mock.MatchedBy(func(query models.ClientQuery) bool {
+		return query.After == nil && query.Limit == 3 && query.Sort == models.ClientSortPriority && query.Descending && query.Filter.Algorithm == algorithm
+	})).Return(first, int64(4), nil)
+
+	request := models.ClientListRequest{ClientFilter: models.ClientFilter{Algorithm: algorithm}, Sort: models.ClientSortPriority, Order: "desc", Limit: 2}
+	page, err := svc.ListClients(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, first[:2], page.Items)
+	assert.Equal(t, int64(4), page.Total)
+	assert.NotEmpty(t, page.NextCursor)
+
+	mockRepo.On("ListClients", mock.Anything, mock.MatchedBy(func(query models.ClientQuery) bool {
+		return query.After != nil && query.After.ID == 1 && query.After.Value == float64(7)
+	})).Return([]models.Client{{ID: 2, Priority: 7}, {ID: 4, Priority: 1}}, int64(4), nil)
+
+	request.Cursor = page.NextCursor
+	page, err = svc.ListClients(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Empty(t, page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_DeletedClients(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	deletedAt := time.Now()
+	deleted := []models.Client{{ID: 4, DeletedAt: &deletedAt}}
+	mockRepo.On("ListClients", mock.Anything, mock.MatchedBy(func(query models.ClientQuery) bool {
+		return query.Deleted && query.Sort == models.ClientSortID && query.Limit == 51
+	})).Return(deleted, int64(1), nil)
+
+	page, err := svc.DeletedClients(context.Background(), models.ClientListRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, page.Items)
+	assert.Empty(t, page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_ListClients_Invalid(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	low, high := 5.0, 1.0
+	mockRepo.On("ListClients", mock.Anything, mock.Anything).Return([]models.Client{{ID: 1}, {ID: 2}}, int64(3), nil).Once()
+	page, err := svc.ListClients(context.Background(), models.ClientListRequest{Limit: 1})
+	assert.NoError(t, err)
+
+	for _, request := range []models.ClientListRequest{
+		{Sort: "created_at"},
+		{Order: "sideways"},
+		{Limit: 10000},
+		{ClientFilter: models.ClientFilter{Algorithm: "iceberg"}},
+		{ClientFilter: models.ClientFilter{PriorityMin: &low, PriorityMax: &high}},
+		{Cursor: "not a cursor"},
+		// A cursor is only valid with the sort key it was returned for.
+		{Sort: models.ClientSortClientName, Cursor: page.NextCursor},
+	} {
+		_, err := svc.ListClients(context.Background(), request)
+		assert.ErrorIs(t, err, service.ErrInvalidClientQuery)
+	}
+
+	mockRepo.AssertNumberOfCalls(t, "ListClients", 1)
+}
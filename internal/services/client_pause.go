@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"time"
+)
+
+var (
+	// ErrClientNotPaused is returned when a paused client was expected.
+	ErrClientNotPaused = domain.New(domain.NotFound, "client_not_paused", "client is not paused")
+	// ErrClientPaused is returned when an operation would touch the pods of a paused client.
+	ErrClientPaused = domain.New(domain.Conflict, "client_paused", "client is paused")
+	// ErrInvalidPause is returned when a pause request is malformed.
+	ErrInvalidPause = domain.New(domain.Validation, "invalid_pause", "invalid pause")
+)
+
+// PauseClient pauses the reconciliation of a client, for example while its pods are
+// debugged manually. The synchronization leaves the pods of a paused client untouched
+// until the client is resumed or the pause expires. Pausing a paused client replaces its pause.
+func (cs *clientService) PauseClient(ctx context.Context, clientID int64, request models.PauseRequest) (*models.ClientPause, error) {
+	const op = "service.client.PauseClient"
+
+	now := time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidPause)
+	}
+
+	if _, err := cs.repository.ClientByID(clientID); err != nil {
+		return nil, err
+	}
+
+	pause := &models.ClientPause{
+		ClientID:  clientID,
+		Reason:    request.Reason,
+		Actor:     request.Actor,
+		PausedAt:  now,
+		ExpiresAt: request.ExpiresAt,
+	}
+
+	// Wait for an in-flight reconciliation of the client so that no pod is
+	// touched once the pause is returned.
+	defer cs.locks.lock(clientID)()
+
+	if err := cs.repository.SavePause(ctx, pause); err != nil {
+		return nil, err
+	}
+	cs.pauses.paused(clientID, now)
+
+	cs.log.Infof("%s: client %d paused by %s until %v", op, clientID, pause.Actor, pause.ExpiresAt)
+
+	return pause, nil
+}
+
+// ResumeClient resumes the reconciliation of a paused client.
+// The pods of the client are reconciled again by the next synchronization.
+func (cs *clientService) ResumeClient(ctx context.Context, clientID int64) error {
+	const op = "service.client.ResumeClient"
+
+	pause, err := cs.ClientPause(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	if err := cs.repository.DeletePause(ctx, clientID); err != nil {
+		return err
+	}
+
+	cs.log.Infof("%s: client %d resumed, paused by %s: %s", op, clientID, pause.Actor, pause.Reason)
+
+	return nil
+}
+
+// ClientPause returns the active pause of a client.
+func (cs *clientService) ClientPause(ctx context.Context, clientID int64) (*models.ClientPause, error) {
+	if _, err := cs.repository.ClientByID(clientID); err != nil {
+		return nil, err
+	}
+
+	pause, err := cs.repository.Pause(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if pause == nil {
+		return nil, ErrClientNotPaused
+	}
+
+	return pause, nil
+}
+
+// checkNotPaused returns ErrClientPaused if the client is paused.
+func (cs *clientService) checkNotPaused(ctx context.Context, clientID int64) error {
+	pause, err := cs.repository.Pause(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if pause.Active(time.Now()) {
+		return fmt.Errorf("%w by %s: %s", ErrClientPaused, pause.Actor, pause.Reason)
+	}
+
+	return nil
+}
+
+// pauseLog remembers when clients were last paused by this instance, so that a
+// synchronization cycle re-checks only the clients paused after it read its desired state.
+type pauseLog struct {
+	mu sync.Mutex
+	at map[int64]time.Time
+}
+
+func newPauseLog() *pauseLog {
+	return &pauseLog{at: make(map[int64]time.Time)}
+}
+
+// paused records that the client was paused at the given time.
+func (l *pauseLog) paused(clientID int64, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.at[clientID] = at
+}
+
+// pausedSince reports whether the client was paused after the given time.
+func (l *pauseLog) pausedSince(clientID int64, since time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	at, ok := l.at[clientID]
+	return ok && !at.Before(since)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"test-task/internal/repository"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+// Defaults of the purge of deleted clients.
+const (
+	defaultClientRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval   = time.Hour
+)
+
+// RetentionService permanently removes deleted clients once their retention period is over,
+// and idempotency keys once they expired.
+type RetentionService interface {
+	PurgeDeletedClients(ctx context.Context) (int64, error)
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)
+	StartPurge()
+}
+
+type retentionService struct {
+	repository repository.ClientRepository
+	keys       repository.IdempotencyRepository
+	leader     Leader
+	retention  time.Duration
+	interval   time.Duration
+	log        logger.Logger
+}
+
+// NewRetentionService creates the retention service. Clients deleted more than retention ago,
+// 30 days if zero, and expired idempotency keys are purged every interval, one hour if zero,
+// by the instance that is the leader.
+func NewRetentionService(clientRepo repository.ClientRepository, idempotencyRepo repository.IdempotencyRepository, leader Leader, retention, interval time.Duration) RetentionService {
+	logger := logger.GetLogger()
+	if retention <= 0 {
+		retention = defaultClientRetention
+	}
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+	return &retentionService{
+		repository: clientRepo,
+		keys:       idempotencyRepo,
+		leader:     leader,
+		retention:  retention,
+		interval:   interval,
+		log:        logger,
+	}
+}
+
+// StartPurge starts purging deleted clients and expired idempotency keys every interval in the background.
+// Every instance runs the purge, but only the leader removes records.
+func (rs *retentionService) StartPurge() {
+	const op = "service.retention.StartPurge"
+
+	ticker := time.NewTicker(rs.interval)
+
+	go func() {
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := rs.PurgeDeletedClients(context.Background()); err != nil {
+				rs.log.Errorf("%s: Failed to purge deleted clients: %v", op, err)
+			}
+			if _, err := rs.PurgeIdempotencyKeys(context.Background()); err != nil {
+				rs.log.Errorf("%s: Failed to purge idempotency keys: %v", op, err)
+			}
+		}
+	}()
+
+	rs.log.Infof("%s: Purge started, removing clients deleted more than %v ago every %v", op, rs.retention, rs.interval)
+}
+
+// PurgeDeletedClients permanently removes the clients deleted more than the retention period ago
+// if this instance is the leader. It returns the number of clients removed.
+func (rs *retentionService) PurgeDeletedClients(ctx context.Context) (int64, error) {
+	const op = "service.retention.PurgeDeletedClients"
+
+	leader, err := rs.leader.IsLeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !leader {
+		return 0, nil
+	}
+
+	purged, err := rs.repository.PurgeDeletedClients(ctx, time.Now().Add(-rs.retention))
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		rs.log.Infof("%s: Purged %d deleted clients", op, purged)
+	}
+
+	return purged, nil
+}
+
+// PurgeIdempotencyKeys removes the expired idempotency keys together with their stored responses
+// if this instance is the leader. It returns the number of keys removed.
+func (rs *retentionService) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	const op = "service.retention.PurgeIdempotencyKeys"
+
+	leader, err := rs.leader.IsLeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !leader {
+		return 0, nil
+	}
+
+	purged, err := rs.keys.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		rs.log.Debugf("%s: Purged %d expired idempotency keys", op, purged)
+	}
+
+	return purged, nil
+}
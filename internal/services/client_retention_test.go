@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	service "test-task/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRetentionService_PurgeDeletedClients(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewRetentionService(mockRepo, new(MockIdempotencyRepository), staticLeader(true), 24*time.Hour, time.Minute)
+
+	mockRepo.On("PurgeDeletedClients", mock.Anything, mock.MatchedBy(func(deletedBefore time.Time) bool {
+		age := time.Since(deletedBefore)
+		return age >= 24*time.Hour && age < 25*time.Hour
+	})).Return(int64(3), nil).Once()
+
+	purged, err := svc.PurgeDeletedClients(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+
+	mockRepo.On("PurgeDeletedClients", mock.Anything, mock.Anything).Return(int64(0), errors.New("connection refused")).Once()
+	_, err = svc.PurgeDeletedClients(context.Background())
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRetentionService_PurgeDeletedClients_NotLeader(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewRetentionService(mockRepo, new(MockIdempotencyRepository), staticLeader(false), 0, 0)
+
+	purged, err := svc.PurgeDeletedClients(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	mockRepo.AssertNotCalled(t, "PurgeDeletedClients", mock.Anything, mock.Anything)
+}
+
+func TestRetentionService_PurgeIdempotencyKeys(t *testing.T) {
+	keys := new(MockIdempotencyRepository)
+	svc := service.NewRetentionService(new(MockClientRepository), keys, staticLeader(true), 0, 0)
+
+	keys.On("DeleteExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(4), nil).Once()
+
+	purged, err := svc.PurgeIdempotencyKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	keys.AssertExpectations(t)
+}
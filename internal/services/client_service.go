@@ -1,42 +1,152 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"test-task/infra/k8s"
+	"test-task/internal/domain"
 	"test-task/internal/models"
 	"test-task/internal/repository"
+	"test-task/pkg/calendar"
+	"test-task/pkg/jsonschema"
+	"test-task/pkg/notify"
 	"test-task/pkg/util/logger"
 	"time"
 )
 
+var (
+	// ErrClientNotFound is returned when the requested client does not exist.
+	ErrClientNotFound = repository.ErrClientNotFound
+	// ErrDeletedClientNotFound is returned when the client to restore is not deleted or was purged.
+	ErrDeletedClientNotFound = repository.ErrDeletedClientNotFound
+	// ErrClientNameTaken is returned when another client has the same name, compared regardless of case.
+	ErrClientNameTaken = repository.ErrClientNameTaken
+	// ErrInvalidClient is returned when a client update is malformed.
+	ErrInvalidClient = domain.New(domain.Validation, "invalid_client", "invalid client")
+	// ErrInvalidAlgorithmStatus is returned when an algorithm status update is malformed.
+	ErrInvalidAlgorithmStatus = domain.New(domain.Validation, "invalid_algorithm_status", "invalid algorithm status")
+	// ErrRevisionMismatch is returned when a client was changed since the revision the caller expected.
+	ErrRevisionMismatch = domain.New(domain.PreconditionFailed, "revision_mismatch", "client revision does not match")
+)
+
 type ClientService interface {
 	Create(client *models.Client) (int64, error)
 	ClientByID(id int64) (*models.Client, error)
+	ClientByName(ctx context.Context, name string) (*models.Client, error)
+	ClientDetails(ctx context.Context, id int64) (*models.ClientDetails, error)
 	Update(id int64, updateParams map[string]interface{}) error
-	Delete(id int64) error
+	PatchClient(ctx context.Context, id int64, patch models.ClientPatch, revision *int64) (*models.Client, error)
+	Delete(id int64, revision *int64) error
+	Restore(id int64) (*models.Client, error)
 	Clients() ([]models.Client, error)
+	ListClients(ctx context.Context, request models.ClientListRequest) (*models.ClientPage, error)
+	DeletedClients(ctx context.Context, request models.ClientListRequest) (*models.ClientPage, error)
 	AlgorithmStatuses() ([]models.AlgorithmStatus, error)
 	UpdateAlgorithmStatus(id int64, status map[string]interface{}) error
+	SetAlgorithmEnabled(ctx context.Context, clientID int64, algorithm string, enabled bool) error
+	AlgorithmStates(ctx context.Context, clientID int64) ([]models.AlgorithmState, error)
+	Scheduling(ctx context.Context, clientID int64) (map[string]models.Scheduling, error)
+	SetSchedulingOverride(ctx context.Context, clientID int64, algorithm string, scheduling models.Scheduling) error
+	DeleteSchedulingOverride(ctx context.Context, clientID int64, algorithm string) error
+	Manifests(ctx context.Context, clientID int64) ([]byte, error)
+	MigrateClient(ctx context.Context, clientID int64, clusterID *int64) ([]models.AlgorithmState, error)
+	ParameterSchema(algorithm string) (*jsonschema.Schema, error)
+	Parameters(ctx context.Context, clientID int64, algorithm string) (*models.AlgorithmParameters, error)
+	SetParameters(ctx context.Context, clientID int64, algorithm string, parameters json.RawMessage, restart bool) (*models.ParametersUpdate, error)
+	PauseClient(ctx context.Context, clientID int64, request models.PauseRequest) (*models.ClientPause, error)
+	ResumeClient(ctx context.Context, clientID int64) error
+	ClientPause(ctx context.Context, clientID int64) (*models.ClientPause, error)
+	EngageKillSwitch(ctx context.Context, request models.KillSwitchRequest) (*models.KillSwitchResult, error)
+	ReleaseKillSwitch(ctx context.Context, request models.KillSwitchRequest) ([]models.KillSwitch, error)
+	KillSwitches(ctx context.Context) ([]models.KillSwitch, error)
+	AlgorithmWindows(ctx context.Context, clientID int64) (map[string]models.AlgorithmWindow, error)
+	SetAlgorithmWindow(ctx context.Context, window *models.AlgorithmWindow) error
+	DeleteAlgorithmWindow(ctx context.Context, clientID int64, algorithm string) error
+	Calendars() map[string]*calendar.Calendar
+	PlanSync(ctx context.Context) (*models.SyncPlan, error)
+	SyncPlan(ctx context.Context, id int64) (*models.SyncPlan, error)
+	ApplySyncPlan(ctx context.Context, id int64) (*models.SyncPlan, error)
 	StartAlgorithmSync()
+	SyncMetrics() models.SyncMetrics
+	LastSyncRun() *models.SyncRun
+}
+
+// SyncConfig holds the settings of the algorithm synchronization process.
+type SyncConfig struct {
+	// ReadyTimeout is how long to wait for a created pod to become ready.
+	// Zero disables waiting. Values above maxReadyTimeout are capped.
+	ReadyTimeout time.Duration
+	// RestartThreshold is the number of pod restarts within RestartWindow after which
+	// the algorithm is marked as failed and its pod is removed. Zero disables detection.
+	RestartThreshold int
+	// RestartWindow is the period over which restarts are counted.
+	// Zero counts all restarts since the pod was created.
+	RestartWindow time.Duration
+	// Workers is the number of clients reconciled concurrently. Values below one mean one.
+	Workers int
+	// BatchSize is the number of clients read from the database per query.
+	// Zero reads all clients with a single query.
+	BatchSize int
+	// Scheduling holds the default placement constraints per algorithm type.
+	Scheduling map[string]models.Scheduling
+	// ParameterSchemas holds the JSON schema of the parameters document per algorithm type.
+	// Algorithm types without a schema accept any JSON object.
+	ParameterSchemas map[string]*jsonschema.Schema
+	// Calendars holds the trading calendars run windows can refer to, keyed by name.
+	Calendars map[string]*calendar.Calendar
+	// Interval is the time between synchronizations, five minutes if zero.
+	// Pods are started and stopped at most this late relative to their run windows.
+	Interval time.Duration
 }
 
 type clientService struct {
-	repository  repository.ClientRepository
-	k8sDeployer k8s.KubernetesDeployer
-	log         logger.Logger
+	repository        repository.ClientRepository
+	clusterRepository repository.ClusterRepository
+	killSwitches      repository.KillSwitchRepository
+	syncPlans         repository.SyncPlanRepository
+	secrets           SecretService
+	deployers         k8s.DeployerFactory
+	notifier          notify.Notifier
+	config            SyncConfig
+	locks             *clientLocks
+	pauses            *pauseLog
+	killed            *killedAlgorithms
+	windows           *windowEvaluator
+	metrics           *syncMetrics
+	log               logger.Logger
 }
 
-func NewClientService(clientRepo repository.ClientRepository, k8sDeployer k8s.KubernetesDeployer) ClientService {
+func NewClientService(clientRepo repository.ClientRepository, clusterRepo repository.ClusterRepository, killSwitchRepo repository.KillSwitchRepository, syncPlanRepo repository.SyncPlanRepository, secrets SecretService, deployers k8s.DeployerFactory, notifier notify.Notifier, config SyncConfig) ClientService {
 	logger := logger.GetLogger()
 	return &clientService{
-		repository:  clientRepo,
-		k8sDeployer: k8sDeployer,
-		log:         logger,
+		repository:        clientRepo,
+		clusterRepository: clusterRepo,
+		killSwitches:      killSwitchRepo,
+		syncPlans:         syncPlanRepo,
+		secrets:           secrets,
+		deployers:         deployers,
+		notifier:          notifier,
+		config:            config,
+		locks:             newClientLocks(),
+		pauses:            newPauseLog(),
+		killed:            newKilledAlgorithms(),
+		windows:           newWindowEvaluator(),
+		metrics:           newSyncMetrics(),
+		log:               logger,
 	}
 }
 
+// Create stores a new client with all algorithms disabled.
+// The creation, update and spawn times of the client are set to the current time,
+// and its ID and revision to the stored ones.
+// It returns ErrClientNameTaken if another client has the same name.
 func (cs *clientService) Create(client *models.Client) (int64, error) {
+	now := time.Now()
+	client.SpawnedAt, client.CreatedAt, client.UpdatedAt = now, now, now
+
 	var algorithm models.AlgorithmStatus
 	return cs.repository.Create(client, &algorithm)
 }
@@ -45,12 +155,161 @@ func (cs *clientService) ClientByID(id int64) (*models.Client, error) {
 	return cs.repository.ClientByID(id)
 }
 
+// ClientByName returns the client with the given name, compared regardless of case.
+func (cs *clientService) ClientByName(ctx context.Context, name string) (*models.Client, error) {
+	return cs.repository.ClientByName(ctx, name)
+}
+
+// ClientDetails returns a client together with its algorithm status.
+func (cs *clientService) ClientDetails(ctx context.Context, id int64) (*models.ClientDetails, error) {
+	client, err := cs.repository.ClientByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	algoStatus, err := cs.repository.AlgorithmByClientID(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrAlgorithmStatusNotFound) {
+		return nil, err
+	}
+
+	return &models.ClientDetails{Client: *client, Algorithm: algoStatus}, nil
+}
+
 func (cs *clientService) Update(id int64, updateParams map[string]interface{}) error {
 	return cs.repository.Update(id, updateParams)
 }
 
-func (cs *clientService) Delete(id int64) error {
-	return cs.repository.Delete(id)
+// PatchClient changes the fields set in the patch and returns the updated client.
+// If revision is not nil, the client is only changed if its current revision matches,
+// otherwise ErrRevisionMismatch is returned.
+// The pods of the client pick up the changes when they are recreated.
+func (cs *clientService) PatchClient(ctx context.Context, id int64, patch models.ClientPatch, revision *int64) (*models.Client, error) {
+	if err := validateClientPatch(patch); err != nil {
+		return nil, err
+	}
+
+	client, err := cs.repository.ClientByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision == nil {
+		if err := cs.repository.Update(id, patch.Columns()); err != nil {
+			return nil, err
+		}
+		return cs.repository.ClientByID(id)
+	}
+
+	if client.Revision != *revision {
+		return nil, fmt.Errorf("%w: client %d is at revision %d", ErrRevisionMismatch, id, client.Revision)
+	}
+	updated, err := cs.repository.UpdateIfRevision(id, *revision, patch.Columns())
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: client %d was changed concurrently", ErrRevisionMismatch, id)
+	}
+
+	return cs.repository.ClientByID(id)
+}
+
+// validateClientPatch checks that a client patch sets at least one field.
+// The values of the fields are checked by the binding rules of models.ClientPatch.
+func validateClientPatch(patch models.ClientPatch) error {
+	if len(patch.Columns()) == 0 {
+		return fmt.Errorf("%w: no fields to update", ErrInvalidClient)
+	}
+
+	return nil
+}
+
+// Delete deletes a client and the pods of its algorithms together with their Secrets and
+// ConfigMaps. Deleted clients are no longer read or synchronized, and are kept until they are
+// purged after the retention period, so they can be restored until then. Pods that could not
+// be deleted are deleted by the synchronization.
+// If revision is not nil, the client is only deleted if its current revision matches,
+// otherwise ErrRevisionMismatch is returned.
+func (cs *clientService) Delete(id int64, revision *int64) error {
+	const op = "service.client.Delete"
+
+	defer cs.locks.lock(id)()
+
+	client, err := cs.repository.ClientByID(id)
+	if err != nil {
+		return err
+	}
+
+	if revision == nil {
+		err = cs.repository.Delete(id)
+	} else {
+		err = cs.deleteIfRevision(id, *revision)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		cs.log.Errorf("%s: Client %d deleted but its pods are left to the synchronization: %v", op, id, err)
+		return nil
+	}
+	cs.deleteClientPods(ctx, *client, clusters)
+
+	return nil
+}
+
+// deleteIfRevision deletes a client if its current revision matches, otherwise it returns ErrRevisionMismatch.
+func (cs *clientService) deleteIfRevision(id, revision int64) error {
+	deleted, err := cs.repository.DeleteIfRevision(id, revision)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return nil
+	}
+
+	client, err := cs.repository.ClientByID(id)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: client %d is at revision %d", ErrRevisionMismatch, id, client.Revision)
+}
+
+// deleteClientPods deletes the pods of every algorithm of a deleted client and records them
+// as deleted. Pods that could not be deleted keep a state other than deleted, so that the
+// synchronization deletes them again. Callers must hold the client lock.
+func (cs *clientService) deleteClientPods(ctx context.Context, client models.Client, clusters map[int64]*models.Cluster) {
+	const op = "service.client.deleteClientPods"
+
+	deployer, err := cs.deployerFor(client, clusters)
+	if err != nil {
+		cs.log.Errorf("%s: Failed to resolve cluster for deleted client %d: %v", op, client.ID, err)
+		return
+	}
+
+	for _, algorithm := range models.Algorithms {
+		state := cs.deletePod(deployer, client, algorithm, podName(client.ID, algorithm), nil)
+		if state.LastError != "" {
+			cs.log.Errorf("%s: Failed to delete %s pod of deleted client %d: %s", op, algorithm, client.ID, state.LastError)
+		}
+		if err := cs.repository.SaveAlgorithmState(ctx, &state); err != nil {
+			cs.log.Errorf("%s: Failed to save %s state for deleted client %d: %v", op, algorithm, client.ID, err)
+		}
+	}
+}
+
+// Restore restores a deleted client that has not been purged yet and returns it.
+// The synchronization then creates the pods of its enabled algorithms, which were
+// deleted together with the client, again.
+func (cs *clientService) Restore(id int64) (*models.Client, error) {
+	if err := cs.repository.Restore(id); err != nil {
+		return nil, err
+	}
+
+	return cs.repository.ClientByID(id)
 }
 
 func (cs *clientService) Clients() ([]models.Client, error) {
@@ -61,104 +320,281 @@ func (cs *clientService) AlgorithmStatuses() ([]models.AlgorithmStatus, error) {
 	return cs.repository.AlgorithmStatuses()
 }
 
+// UpdateAlgorithmStatus updates the algorithm flags of a client.
+// Enabling an algorithm whose kill switch is engaged fails with ErrKillSwitchEngaged.
+// Enabling an algorithm also clears its crash-loop failure mark so that the
+// synchronization starts creating its pod again.
 func (cs *clientService) UpdateAlgorithmStatus(id int64, status map[string]interface{}) error {
-	return cs.repository.UpdateAlgorithmStatus(id, status)
+	if len(status) == 0 {
+		return fmt.Errorf("%w: no algorithms to update", ErrInvalidAlgorithmStatus)
+	}
+	for algorithm, value := range status {
+		if _, ok := value.(bool); !ok || !models.IsAlgorithm(algorithm) {
+			return fmt.Errorf("%w: %q is not an algorithm flag", ErrInvalidAlgorithmStatus, algorithm)
+		}
+	}
+
+	var enabled []string
+	for _, algorithm := range models.Algorithms {
+		if v, ok := status[algorithm].(bool); ok && v {
+			enabled = append(enabled, algorithm)
+		}
+	}
+
+	if len(enabled) > 0 {
+		if err := cs.checkKillSwitches(context.Background(), enabled); err != nil {
+			return err
+		}
+	}
+
+	if err := cs.repository.UpdateAlgorithmStatus(id, status); err != nil {
+		return err
+	}
+
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	return cs.repository.ResetAlgorithmFailures(context.Background(), id, enabled)
 }
 
-// StartAlgorithmSync initiates the algorithm synchronization process.
-// This function starts a goroutine that synchronizes algorithms every 5 minute.
-// A Ticker is used to trigger the synchronization at the specified intervals.
-// When the function completes, the Ticker is stopped to release resources.
-func (cs *clientService) StartAlgorithmSync() {
-	const op = "service.client.StartAlgorithmSync"
+// SetAlgorithmEnabled enables or disables one algorithm of a client and reconciles the
+// pods of the client right away instead of waiting for the next synchronization.
+// The flag is stored even if the client is paused or outside the run window of the
+// algorithm; its pods then follow once the client is resumed or the window opens.
+// Pods that cannot be reconciled right away are left to the synchronization.
+func (cs *clientService) SetAlgorithmEnabled(ctx context.Context, clientID int64, algorithm string, enabled bool) error {
+	const op = "service.client.SetAlgorithmEnabled"
 
-	cs.log.Infof("%s: Starting synchronization process...", op)
-	ticker := time.NewTicker(5 * time.Minute)
+	if !models.IsAlgorithm(algorithm) {
+		return fmt.Errorf("unknown algorithm %q", algorithm)
+	}
 
-	go func() {
-		defer ticker.Stop()
-		for range ticker.C {
-			cs.syncAlgorithms()
-		}
-	}()
+	algoStatus, err := cs.repository.AlgorithmByClientID(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	if err := cs.UpdateAlgorithmStatus(algoStatus.ID, map[string]interface{}{algorithm: enabled}); err != nil {
+		return err
+	}
 
-	cs.log.Infof("%s: Synchronization process started", op)
+	client, err := cs.repository.ClientByID(clientID)
+	if err != nil {
+		return err
+	}
+
+	if algoStatus, err = cs.repository.AlgorithmByClientID(ctx, clientID); err != nil {
+		return err
+	}
+
+	pause, err := cs.repository.Pause(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	state := models.DesiredState{Client: *client, Algorithm: algoStatus, Pause: pause}
+	result := cs.syncClient(ctx, state, nil, clusters, time.Now())
+	if result.Status == models.SyncStatusSkipped {
+		// The flag is stored, the synchronization reconciles the pods later.
+		cs.log.Warnf("%s: %s enabled=%t for client %d but pods not reconciled: %s", op, algorithm, enabled, clientID, result.Message)
+		return nil
+	}
+
+	cs.log.Infof("%s: %s enabled=%t for client %d, reconciliation %s", op, algorithm, enabled, clientID, result.Status)
+
+	return nil
 }
 
-// syncAlgorithms fetches clients from the database and synchronizes pods for each client based on their algorithm status.
-func (cs *clientService) syncAlgorithms() {
-	const op = "service.client.syncAlgorithms"
+// AlgorithmStates returns the observed state of the algorithm pods of a client
+// as recorded by the last synchronization.
+func (cs *clientService) AlgorithmStates(ctx context.Context, clientID int64) ([]models.AlgorithmState, error) {
+	return cs.repository.AlgorithmStates(ctx, clientID)
+}
 
-	clients, err := cs.repository.Clients()
+// Scheduling returns the effective placement constraints of every algorithm type
+// for a client: the defaults of the algorithm type with the client's overrides applied.
+func (cs *clientService) Scheduling(ctx context.Context, clientID int64) (map[string]models.Scheduling, error) {
+	overrides, err := cs.repository.SchedulingOverrides(ctx, clientID)
 	if err != nil {
-		cs.log.Errorf("%s: Failed to fetch clients from database: %v", op, err)
-		return
+		return nil, err
 	}
 
-	for _, client := range clients {
-		algoStatus, err := cs.repository.AlgorithmByClientID(context.Background(), client.ID)
-		if err != nil {
-			cs.log.Errorf("%s: Failed to fetch algorithm status for client %d: %v", op, client.ID, err)
+	return cs.mergeScheduling(overrides), nil
+}
+
+// mergeScheduling merges the scheduling overrides of a client into the defaults per algorithm type.
+func (cs *clientService) mergeScheduling(overrides map[string]models.Scheduling) map[string]models.Scheduling {
+	scheduling := make(map[string]models.Scheduling, len(models.Algorithms))
+	for _, algorithm := range models.Algorithms {
+		scheduling[algorithm] = cs.config.Scheduling[algorithm].Merge(overrides[algorithm])
+	}
+
+	return scheduling
+}
+
+// SetSchedulingOverride stores placement constraints that override the defaults
+// of the algorithm type for a client. They apply to pods created afterwards.
+func (cs *clientService) SetSchedulingOverride(ctx context.Context, clientID int64, algorithm string, scheduling models.Scheduling) error {
+	if !models.IsAlgorithm(algorithm) {
+		return fmt.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	return cs.repository.SaveSchedulingOverride(ctx, clientID, algorithm, scheduling)
+}
+
+// DeleteSchedulingOverride removes the client's placement override for an algorithm type.
+func (cs *clientService) DeleteSchedulingOverride(ctx context.Context, clientID int64, algorithm string) error {
+	if !models.IsAlgorithm(algorithm) {
+		return fmt.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	return cs.repository.DeleteSchedulingOverride(ctx, clientID, algorithm)
+}
+
+// Manifests renders the pod manifests the synchronization would apply for every
+// enabled algorithm of a client, as a multi-document YAML stream.
+// Nothing is applied to the cluster, and secret values are redacted.
+func (cs *clientService) Manifests(ctx context.Context, clientID int64) ([]byte, error) {
+	client, err := cs.repository.ClientByID(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	algoStatus, err := cs.repository.AlgorithmByClientID(ctx, clientID)
+	if errors.Is(err, repository.ErrAlgorithmStatusNotFound) {
+		algoStatus, err = &models.AlgorithmStatus{ClientID: clientID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	scheduling, err := cs.Scheduling(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := cs.secrets.Secrets(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		secrets[i].Value = redactedValue
+	}
+
+	parameters, err := cs.repository.AlgorithmParameters(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests bytes.Buffer
+	for _, algorithm := range models.Algorithms {
+		if !algoStatus.Enabled(algorithm) {
 			continue
 		}
-		cs.syncPodsForClient(client, *algoStatus)
-	}
-}
-
-// syncPodsForClient synchronizes Kubernetes pods for a given client based on their algorithm status.
-// It creates or deletes pods depending on the algorithm status flags VWAP, TWAP, and HFT.
-// For each algorithm type, a pod is created if the corresponding flag is true in algoStatus;
-// otherwise, the pod is deleted.
-// Pod names are generated based on the client's ID and algorithm type (e.g., "vwap-123").
-// If pod creation or deletion fails, a fatal error is logged, which terminates the application.
-func (cs *clientService) syncPodsForClient(client models.Client, algoStatus models.AlgorithmStatus) {
-	const op = "service.client.syncPodsForClient"
-
-	// VWAP
-	vwapPodName := fmt.Sprintf("vwap-%d", client.ID)
-	if algoStatus.VWAP {
-		if err := cs.k8sDeployer.CreatePod(vwapPodName, client.Image); err != nil {
-			cs.log.Fatalf("%s: Failed to deploy VWAP pod for client %d: %v", op, client.ID, err)
-		} else {
-			cs.log.Debugf("%s: VWAP pod deployed successfully for client %d", op, client.ID)
-		}
-	} else {
-		if err := cs.k8sDeployer.DeletePod(vwapPodName); err != nil {
-			cs.log.Fatalf("%s: Failed to delete VWAP pod for client %d: %v", op, client.ID, err)
-		} else {
-			cs.log.Debugf("%s: VWAP pod deleted successfully for client %d", op, client.ID)
+
+		spec := podSpec(*client, algorithm, podName(client.ID, algorithm), scheduling[algorithm], secrets, string(parameters[algorithm].Parameters))
+		manifest, err := cs.deployers.Default().RenderPod(spec)
+		if err != nil {
+			return nil, err
 		}
+
+		manifests.WriteString("---\n")
+		manifests.Write(manifest)
 	}
 
-	// TWAP
-	twapPodName := fmt.Sprintf("twap-%d", client.ID)
-	if algoStatus.TWAP {
-		if err := cs.k8sDeployer.CreatePod(twapPodName, client.Image); err != nil {
-			cs.log.Fatalf("%s: Failed to deploy TWAP pod for client %d: %v", op, client.ID, err)
-		} else {
-			cs.log.Debugf("%s: TWAP pod deployed successfully for client %d", op, client.ID)
-		}
-	} else {
-		if err := cs.k8sDeployer.DeletePod(twapPodName); err != nil {
-			cs.log.Fatalf("%s: Failed to delete TWAP pod for client %d: %v", op, client.ID, err)
-		} else {
-			cs.log.Debugf("%s: TWAP pod deleted successfully for client %d", op, client.ID)
-		}
+	return manifests.Bytes(), nil
+}
+
+// MigrateClient moves the pods of a client to another cluster; a nil clusterID means the default cluster.
+// The target cluster must be healthy. Pods are deleted from the source cluster before they are
+// created in the target cluster, so that an algorithm never runs in both at the same time.
+// Once the client is assigned to the target cluster, pod creation failures there are recorded
+// in the returned algorithm states and retried by the synchronization.
+func (cs *clientService) MigrateClient(ctx context.Context, clientID int64, clusterID *int64) ([]models.AlgorithmState, error) {
+	const op = "service.client.MigrateClient"
+
+	defer cs.locks.lock(clientID)()
+
+	client, err := cs.repository.ClientByID(clientID)
+	if err != nil {
+		return nil, err
 	}
 
-	// HFT
-	hftPodName := fmt.Sprintf("hft-%d", client.ID)
-	if algoStatus.HFT {
-		if err := cs.k8sDeployer.CreatePod(hftPodName, client.Image); err != nil {
-			cs.log.Fatalf("%s: Failed to deploy HFT pod for client %d: %v", op, client.ID, err)
-		} else {
-			cs.log.Debugf("%s: HFT pod deployed successfully for client %d", op, client.ID)
-		}
-	} else {
-		if err := cs.k8sDeployer.DeletePod(hftPodName); err != nil {
-			cs.log.Fatalf("%s: Failed to delete HFT pod for client %d: %v", op, client.ID, err)
-		} else {
-			cs.log.Debugf("%s: HFT pod deleted successfully for client %d", op, client.ID)
+	if err := cs.checkNotPaused(ctx, clientID); err != nil {
+		return nil, err
+	}
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := cs.deployerFor(*client, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated := *client
+	migrated.ClusterID = clusterID
+	target, err := cs.deployerFor(migrated, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	if sameCluster(client.ClusterID, clusterID) {
+		return cs.repository.AlgorithmStates(ctx, clientID)
+	}
+
+	if err := target.Health(); err != nil {
+		return nil, fmt.Errorf("target cluster is unavailable: %w", err)
+	}
+
+	algoStatus, err := cs.repository.AlgorithmByClientID(ctx, clientID)
+	if errors.Is(err, repository.ErrAlgorithmStatusNotFound) {
+		algoStatus, err = &models.AlgorithmStatus{ClientID: clientID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, algorithm := range models.Algorithms {
+		if err := source.DeletePod(podName(clientID, algorithm)); err != nil {
+			return nil, fmt.Errorf("failed to delete %s pod from source cluster: %w", algorithm, err)
 		}
 	}
+
+	if err := cs.repository.Update(clientID, map[string]interface{}{"cluster_id": clusterID}); err != nil {
+		return nil, err
+	}
+
+	cs.log.Infof("%s: client %d assigned to cluster %v", op, clientID, clusterName(clusterID, clusters))
+
+	cs.syncPodsForClient(ctx, target, migrated, *algoStatus, nil)
+
+	return cs.repository.AlgorithmStates(ctx, clientID)
+}
+
+// sameCluster reports whether both cluster IDs refer to the same cluster.
+func sameCluster(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// clusterName returns the name of the cluster with the given ID for logging.
+func clusterName(clusterID *int64, clusters map[int64]*models.Cluster) string {
+	if clusterID == nil {
+		return defaultClusterName
+	}
+	if cluster, ok := clusters[*clusterID]; ok {
+		return cluster.Name
+	}
+	return fmt.Sprint(*clusterID)
 }
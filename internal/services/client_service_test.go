@@ -2,8 +2,16 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"test-task/infra/k8s"
+	"test-task/internal/domain"
 	"test-task/internal/models"
 	service "test-task/internal/services"
+	"test-task/pkg/calendar"
+	"test-task/pkg/jsonschema"
+	"test-task/pkg/notify"
 	"testing"
 	"time"
 
@@ -25,21 +33,56 @@ func (m *MockClientRepository) ClientByID(id int64) (*models.Client, error) {
 	return args.Get(0).(*models.Client), args.Error(1)
 }
 
+func (m *MockClientRepository) ClientByName(ctx context.Context, name string) (*models.Client, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
 func (m *MockClientRepository) Update(id int64, updateParams map[string]interface{}) error {
 	args := m.Called(id, updateParams)
 	return args.Error(0)
 }
 
+func (m *MockClientRepository) UpdateIfRevision(id, revision int64, updateParams map[string]interface{}) (bool, error) {
+	args := m.Called(id, revision, updateParams)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockClientRepository) Delete(id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockClientRepository) DeleteIfRevision(id, revision int64) (bool, error) {
+	args := m.Called(id, revision)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockClientRepository) Restore(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) PurgeDeletedClients(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClientRepository) DeletedClientsWithPods(ctx context.Context) ([]models.Client, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Client), args.Error(1)
+}
+
 func (m *MockClientRepository) Clients() ([]models.Client, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Client), args.Error(1)
 }
 
+func (m *MockClientRepository) ListClients(ctx context.Context, query models.ClientQuery) ([]models.Client, int64, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]models.Client), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockClientRepository) AlgorithmStatuses() ([]models.AlgorithmStatus, error) {
 	args := m.Called()
 	return args.Get(0).([]models.AlgorithmStatus), args.Error(1)
@@ -55,6 +98,101 @@ func (m *MockClientRepository) AlgorithmByClientID(ctx context.Context, clientID
 	return args.Get(0).(*models.AlgorithmStatus), args.Error(1)
 }
 
+func (m *MockClientRepository) AlgorithmStates(ctx context.Context, clientID int64) ([]models.AlgorithmState, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).([]models.AlgorithmState), args.Error(1)
+}
+
+func (m *MockClientRepository) AlgorithmStatesByClients(ctx context.Context, clientIDs []int64) (map[int64][]models.AlgorithmState, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).(map[int64][]models.AlgorithmState), args.Error(1)
+}
+
+func (m *MockClientRepository) SaveAlgorithmState(ctx context.Context, state *models.AlgorithmState) error {
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) ResetAlgorithmFailures(ctx context.Context, algorithmID int64, algorithms []string) error {
+	args := m.Called(ctx, algorithmID, algorithms)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) SchedulingOverrides(ctx context.Context, clientID int64) (map[string]models.Scheduling, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).(map[string]models.Scheduling), args.Error(1)
+}
+
+func (m *MockClientRepository) SchedulingOverridesByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.Scheduling, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).(map[int64]map[string]models.Scheduling), args.Error(1)
+}
+
+func (m *MockClientRepository) SaveSchedulingOverride(ctx context.Context, clientID int64, algorithm string, scheduling models.Scheduling) error {
+	args := m.Called(ctx, clientID, algorithm, scheduling)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) DeleteSchedulingOverride(ctx context.Context, clientID int64, algorithm string) error {
+	args := m.Called(ctx, clientID, algorithm)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) DesiredStates(ctx context.Context, afterID int64, limit int) ([]models.DesiredState, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]models.DesiredState), args.Error(1)
+}
+
+func (m *MockClientRepository) AlgorithmParameters(ctx context.Context, clientID int64) (map[string]models.AlgorithmParameters, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).(map[string]models.AlgorithmParameters), args.Error(1)
+}
+
+func (m *MockClientRepository) AlgorithmParametersByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmParameters, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).(map[int64]map[string]models.AlgorithmParameters), args.Error(1)
+}
+
+func (m *MockClientRepository) SaveAlgorithmParameters(ctx context.Context, params *models.AlgorithmParameters) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) Pause(ctx context.Context, clientID int64) (*models.ClientPause, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).(*models.ClientPause), args.Error(1)
+}
+
+func (m *MockClientRepository) SavePause(ctx context.Context, pause *models.ClientPause) error {
+	args := m.Called(ctx, pause)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) DeletePause(ctx context.Context, clientID int64) error {
+	args := m.Called(ctx, clientID)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) AlgorithmWindows(ctx context.Context, clientID int64) (map[string]models.AlgorithmWindow, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).(map[string]models.AlgorithmWindow), args.Error(1)
+}
+
+func (m *MockClientRepository) AlgorithmWindowsByClients(ctx context.Context, clientIDs []int64) (map[int64]map[string]models.AlgorithmWindow, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).(map[int64]map[string]models.AlgorithmWindow), args.Error(1)
+}
+
+func (m *MockClientRepository) SaveAlgorithmWindow(ctx context.Context, window *models.AlgorithmWindow) error {
+	args := m.Called(ctx, window)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) DeleteAlgorithmWindow(ctx context.Context, clientID int64, algorithm string) error {
+	args := m.Called(ctx, clientID, algorithm)
+	return args.Error(0)
+}
+
 type MockLogger struct {
 	mock.Mock
 }
@@ -71,11 +209,21 @@ type MockKubernetesDeployer struct {
 	mock.Mock
 }
 
-func (m *MockKubernetesDeployer) CreatePod(name, image string) error {
-	args := m.Called(name, image)
+func (m *MockKubernetesDeployer) CreatePod(spec k8s.PodSpec) error {
+	args := m.Called(spec)
 	return args.Error(0)
 }
 
+func (m *MockKubernetesDeployer) ApplyConfigMap(spec k8s.PodSpec) error {
+	args := m.Called(spec)
+	return args.Error(0)
+}
+
+func (m *MockKubernetesDeployer) RenderPod(spec k8s.PodSpec) ([]byte, error) {
+	args := m.Called(spec)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 func (m *MockKubernetesDeployer) DeletePod(name string) error {
 	args := m.Called(name)
 	return args.Error(0)
@@ -86,10 +234,164 @@ func (m *MockKubernetesDeployer) GetPodList() ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockKubernetesDeployer) PodStatus(name string) (*k8s.PodStatus, error) {
+	args := m.Called(name)
+	return args.Get(0).(*k8s.PodStatus), args.Error(1)
+}
+
+func (m *MockKubernetesDeployer) WaitForPodReady(name string, timeout time.Duration) (*k8s.PodStatus, error) {
+	args := m.Called(name, timeout)
+	return args.Get(0).(*k8s.PodStatus), args.Error(1)
+}
+
+func (m *MockKubernetesDeployer) Health() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+type MockSecretService struct {
+	mock.Mock
+}
+
+type MockKillSwitchRepository struct {
+	mock.Mock
+}
+
+// newMockKillSwitchRepository returns a kill switch repository mock without engaged kill switches.
+func newMockKillSwitchRepository() *MockKillSwitchRepository {
+	m := new(MockKillSwitchRepository)
+	m.On("KillSwitches", mock.Anything).Return([]models.KillSwitch{}, nil).Maybe()
+	return m
+}
+
+func (m *MockKillSwitchRepository) Engage(ctx context.Context, algorithms []string, actor, reason string) ([]models.KillSwitch, []models.Client, error) {
+	args := m.Called(ctx, algorithms, actor, reason)
+	return args.Get(0).([]models.KillSwitch), args.Get(1).([]models.Client), args.Error(2)
+}
+
+func (m *MockKillSwitchRepository) Release(ctx context.Context, algorithms []string, actor string) ([]models.KillSwitch, error) {
+	args := m.Called(ctx, algorithms, actor)
+	return args.Get(0).([]models.KillSwitch), args.Error(1)
+}
+
+func (m *MockKillSwitchRepository) KillSwitches(ctx context.Context) ([]models.KillSwitch, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.KillSwitch), args.Error(1)
+}
+
+type MockSyncPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockSyncPlanRepository) Create(ctx context.Context, plan *models.SyncPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockSyncPlanRepository) Plan(ctx context.Context, id int64) (*models.SyncPlan, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.SyncPlan), args.Error(1)
+}
+
+func (m *MockSyncPlanRepository) UpdateStatus(ctx context.Context, id int64, from, to string) (bool, error) {
+	args := m.Called(ctx, id, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSyncPlanRepository) Finish(ctx context.Context, plan *models.SyncPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+// newMockSecretService returns a secret service mock for clients without secrets.
+func newMockSecretService() *MockSecretService {
+	m := new(MockSecretService)
+	m.On("Secrets", mock.Anything, mock.Anything).Return([]models.ClientSecret{}, nil).Maybe()
+	m.On("Decrypt", mock.Anything, mock.Anything).Return([]models.ClientSecret{}, nil).Maybe()
+	m.On("DecryptClients", mock.Anything, mock.Anything).Return(map[int64]service.DecryptedSecrets{}, nil).Maybe()
+	return m
+}
+
+func (m *MockSecretService) Secrets(ctx context.Context, clientID int64) ([]models.ClientSecret, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).([]models.ClientSecret), args.Error(1)
+}
+
+func (m *MockSecretService) SetSecret(ctx context.Context, clientID int64, name string, value models.SecretValue) (*models.ClientSecret, error) {
+	args := m.Called(ctx, clientID, name, value)
+	return args.Get(0).(*models.ClientSecret), args.Error(1)
+}
+
+func (m *MockSecretService) DeleteSecret(ctx context.Context, clientID int64, name string) error {
+	args := m.Called(ctx, clientID, name)
+	return args.Error(0)
+}
+
+func (m *MockSecretService) Decrypt(ctx context.Context, clientID int64) ([]models.ClientSecret, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).([]models.ClientSecret), args.Error(1)
+}
+
+func (m *MockSecretService) DecryptClients(ctx context.Context, clientIDs []int64) (map[int64]service.DecryptedSecrets, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).(map[int64]service.DecryptedSecrets), args.Error(1)
+}
+
+type MockClusterRepository struct {
+	mock.Mock
+}
+
+func (m *MockClusterRepository) Create(ctx context.Context, cluster *models.Cluster) (int64, error) {
+	args := m.Called(ctx, cluster)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockClusterRepository) ClusterByID(ctx context.Context, id int64) (*models.Cluster, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Cluster), args.Error(1)
+}
+
+func (m *MockClusterRepository) Clusters(ctx context.Context) ([]models.Cluster, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Cluster), args.Error(1)
+}
+
+func (m *MockClusterRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockDeployerFactory returns a separate deployer per cluster name and defaultDeployer for the default cluster.
+type MockDeployerFactory struct {
+	defaultDeployer k8s.KubernetesDeployer
+	deployers       map[string]k8s.KubernetesDeployer
+}
+
+func (m *MockDeployerFactory) Deployer(cluster k8s.Cluster) k8s.KubernetesDeployer {
+	return m.deployers[cluster.Name]
+}
+
+func (m *MockDeployerFactory) Default() k8s.KubernetesDeployer {
+	return m.defaultDeployer
+}
+
+func (m *MockDeployerFactory) InFlightCalls() int {
+	return 0
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(event notify.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
 func TestClientService_Create(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
 	client := &models.Client{ID: 1, ClientName: "Test Client"}
 	algorithm := &models.AlgorithmStatus{}
@@ -106,7 +408,7 @@ func TestClientService_Create(t *testing.T) {
 func TestClientService_ClientByID(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
 	client := &models.Client{ID: 1, ClientName: "Test Client"}
 	mockRepo.On("ClientByID", int64(1)).Return(client, nil)
@@ -118,10 +420,58 @@ func TestClientService_ClientByID(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestClientService_Create_NameTaken(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), fmt.Errorf("%w: %q", service.ErrClientNameTaken, "test client"))
+
+	_, err := svc.Create(&models.Client{ClientName: "test client"})
+
+	assert.ErrorIs(t, err, service.ErrClientNameTaken)
+	assert.ErrorIs(t, err, domain.Conflict)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_ClientByName(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	client := &models.Client{ID: 1, ClientName: "Test Client"}
+	mockRepo.On("ClientByName", mock.Anything, "test client").Return(client, nil).Once()
+	mockRepo.On("ClientByName", mock.Anything, "missing").Return((*models.Client)(nil), fmt.Errorf("%w: %q", service.ErrClientNotFound, "missing")).Once()
+
+	res, err := svc.ClientByName(context.Background(), "test client")
+	assert.NoError(t, err)
+	assert.Equal(t, client, res)
+
+	_, err = svc.ClientByName(context.Background(), "missing")
+	assert.ErrorIs(t, err, service.ErrClientNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_ClientDetails(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	client := &models.Client{ID: 1, ClientName: "Test Client"}
+	algoStatus := &models.AlgorithmStatus{ID: 5, ClientID: 1, VWAP: true}
+	mockRepo.On("ClientByID", int64(1)).Return(client, nil)
+	mockRepo.On("ClientByID", int64(2)).Return((*models.Client)(nil), service.ErrClientNotFound)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(algoStatus, nil)
+
+	details, err := svc.ClientDetails(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ClientDetails{Client: *client, Algorithm: algoStatus}, details)
+
+	_, err = svc.ClientDetails(context.Background(), 2)
+	assert.ErrorIs(t, err, service.ErrClientNotFound)
+}
+
 func TestClientService_Update(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
 	updateParams := map[string]interface{}{"ClientName": "Updated Client"}
 	mockRepo.On("Update", int64(1), updateParams).Return(nil)
@@ -132,23 +482,133 @@ func TestClientService_Update(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestClientService_PatchClient(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	name := "Renamed"
+	priority := 2.5
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, ClientName: "Client1"}, nil).Once()
+	mockRepo.On("Update", int64(1), map[string]interface{}{"client_name": "Renamed", "priority": 2.5}).Return(nil)
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, ClientName: "Renamed", Priority: 2.5}, nil).Once()
+
+	client, err := svc.PatchClient(context.Background(), 1, models.ClientPatch{ClientName: &name, Priority: &priority}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Client{ID: 1, ClientName: "Renamed", Priority: 2.5}, client)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_PatchClient_Invalid(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	_, err := svc.PatchClient(context.Background(), 1, models.ClientPatch{}, nil)
+	assert.ErrorIs(t, err, service.ErrInvalidClient)
+
+	name := "Renamed"
+	mockRepo.On("ClientByID", int64(2)).Return((*models.Client)(nil), service.ErrClientNotFound)
+	_, err = svc.PatchClient(context.Background(), 2, models.ClientPatch{ClientName: &name}, nil)
+	assert.ErrorIs(t, err, service.ErrClientNotFound)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestClientService_Delete(t *testing.T) {
 	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, Image: "image"}, nil)
 	mockRepo.On("Delete", int64(1)).Return(nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil)
+	mockK8sDeployer.On("DeletePod", "vwap-1").Return(nil)
+	mockK8sDeployer.On("DeletePod", "twap-1").Return(nil)
+	mockK8sDeployer.On("DeletePod", "hft-1").Return(errors.New("timeout"))
+	mockRepo.On("SaveAlgorithmState", mock.Anything, mock.MatchedBy(func(state *models.AlgorithmState) bool {
+		return state.Algorithm != models.AlgorithmHFT && state.Phase == models.PhaseDeleted
+	})).Return(nil).Twice()
+	mockRepo.On("SaveAlgorithmState", mock.Anything, mock.MatchedBy(func(state *models.AlgorithmState) bool {
+		return state.Algorithm == models.AlgorithmHFT && state.Phase == models.PhaseUnknown && state.LastError == "timeout"
+	})).Return(nil).Once()
+
+	err := service.Delete(int64(1), nil)
 
-	err := service.Delete(int64(1))
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockK8sDeployer.AssertExpectations(t)
+}
+
+func TestClientService_PatchClient_Revision(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	name := "Renamed"
+	patch := models.ClientPatch{ClientName: &name}
+	stale, current := int64(2), int64(3)
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, ClientName: "Client1", Revision: 3}, nil).Twice()
+	_, err := svc.PatchClient(context.Background(), 1, patch, &stale)
+	assert.ErrorIs(t, err, service.ErrRevisionMismatch)
+	mockRepo.AssertNotCalled(t, "UpdateIfRevision", mock.Anything, mock.Anything, mock.Anything)
 
+	// The client is changed between the read and the conditional update.
+	mockRepo.On("UpdateIfRevision", int64(1), current, map[string]interface{}{"client_name": "Renamed"}).Return(false, nil).Once()
+	_, err = svc.PatchClient(context.Background(), 1, patch, &current)
+	assert.ErrorIs(t, err, service.ErrRevisionMismatch)
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, ClientName: "Client1", Revision: 3}, nil).Once()
+	mockRepo.On("UpdateIfRevision", int64(1), current, map[string]interface{}{"client_name": "Renamed"}).Return(true, nil).Once()
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, ClientName: "Renamed", Revision: 4}, nil).Once()
+	client, err := svc.PatchClient(context.Background(), 1, patch, &current)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(4), client.Revision)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_Delete_Revision(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	revision := int64(3)
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, Revision: 4}, nil).Twice()
+	mockRepo.On("DeleteIfRevision", int64(1), revision).Return(false, nil).Once()
+	assert.ErrorIs(t, svc.Delete(1, &revision), service.ErrRevisionMismatch)
+
+	mockRepo.On("ClientByID", int64(2)).Return((*models.Client)(nil), service.ErrClientNotFound).Once()
+	assert.ErrorIs(t, svc.Delete(2, &revision), service.ErrClientNotFound)
+
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	mockRepo.AssertNotCalled(t, "DeleteIfRevision", int64(2), mock.Anything)
+	mockRepo.AssertNotCalled(t, "SaveAlgorithmState", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_Restore(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	mockRepo.On("Restore", int64(1)).Return(nil).Once()
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, Revision: 5}, nil).Once()
+	client, err := svc.Restore(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), client.Revision)
+
+	mockRepo.On("Restore", int64(2)).Return(fmt.Errorf("%w: 2", service.ErrDeletedClientNotFound)).Once()
+	_, err = svc.Restore(2)
+	assert.ErrorIs(t, err, service.ErrDeletedClientNotFound)
+
 	mockRepo.AssertExpectations(t)
 }
 
 func TestClientService_Clients(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
 	clients := []models.Client{
 		{ID: 1, ClientName: "Test Client 1"},
@@ -166,7 +626,7 @@ func TestClientService_Clients(t *testing.T) {
 func TestClientService_AlgorithmStatuses(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
 	algorithms := []models.AlgorithmStatus{
 		{ID: 1, ClientID: 1, VWAP: true},
@@ -184,9 +644,9 @@ func TestClientService_AlgorithmStatuses(t *testing.T) {
 func TestClientService_UpdateAlgorithmStatus(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
-	updateParams := map[string]interface{}{"VWAP": true}
+	updateParams := map[string]interface{}{"vwap": false}
 	mockRepo.On("UpdateAlgorithmStatus", int64(1), updateParams).Return(nil)
 
 	err := service.UpdateAlgorithmStatus(int64(1), updateParams)
@@ -195,22 +655,536 @@ func TestClientService_UpdateAlgorithmStatus(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestStartAlgorithmSync(t *testing.T) {
+func TestClientService_UpdateAlgorithmStatus_Invalid(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	for _, status := range []map[string]interface{}{{}, {"VWAP": true}, {"vwap = true, twap": true}, {"twap": "yes"}} {
+		err := svc.UpdateAlgorithmStatus(int64(1), status)
+		assert.ErrorIs(t, err, service.ErrInvalidAlgorithmStatus)
+	}
+
+	mockRepo.AssertNotCalled(t, "UpdateAlgorithmStatus", mock.Anything, mock.Anything)
+}
+
+func TestClientService_UpdateAlgorithmStatus_ResetsFailures(t *testing.T) {
 	mockRepo := new(MockClientRepository)
 	mockK8sDeployer := new(MockKubernetesDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
-	service := service.NewClientService(mockRepo, mockK8sDeployer)
+	updateParams := map[string]interface{}{"hft": true, "vwap": false}
+	mockRepo.On("UpdateAlgorithmStatus", int64(1), updateParams).Return(nil)
+	mockRepo.On("ResetAlgorithmFailures", mock.Anything, int64(1), []string{models.AlgorithmHFT}).Return(nil)
 
-	clients := []models.Client{
-		{ID: 1, ClientName: "Client1"},
-		{ID: 2, ClientName: "Client2"},
+	err := service.UpdateAlgorithmStatus(int64(1), updateParams)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_AlgorithmStates(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	states := []models.AlgorithmState{
+		{ClientID: 1, Algorithm: models.AlgorithmVWAP, Phase: "Running", Ready: true, PodName: "vwap-1"},
+		{ClientID: 1, Algorithm: models.AlgorithmHFT, Phase: models.PhaseFailed, PodName: "hft-1", LastError: "ImagePullBackOff"},
 	}
-	mockRepo.On("Clients", mock.Anything).Return(clients, nil)
+	mockRepo.On("AlgorithmStates", mock.Anything, int64(1)).Return(states, nil)
+
+	res, err := service.AlgorithmStates(context.Background(), int64(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, states, res)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_Scheduling(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	config := service.SyncConfig{
+		Scheduling: map[string]models.Scheduling{
+			models.AlgorithmHFT: {
+				NodeSelector: map[string]string{"node-pool": "hft"},
+				Tolerations:  []models.Toleration{{Key: "dedicated", Operator: "Equal", Value: "hft", Effect: "NoSchedule"}},
+			},
+		},
+	}
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), config)
+
+	overrides := map[string]models.Scheduling{
+		models.AlgorithmHFT: {NodeSelector: map[string]string{"zone": "ld4"}},
+	}
+	mockRepo.On("SchedulingOverrides", mock.Anything, int64(1)).Return(overrides, nil)
+
+	res, err := service.Scheduling(context.Background(), int64(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.Scheduling{}, res[models.AlgorithmVWAP])
+	assert.Equal(t, map[string]string{"node-pool": "hft", "zone": "ld4"}, res[models.AlgorithmHFT].NodeSelector)
+	assert.Equal(t, config.Scheduling[models.AlgorithmHFT].Tolerations, res[models.AlgorithmHFT].Tolerations)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_SetSchedulingOverride_UnknownAlgorithm(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	err := service.SetSchedulingOverride(context.Background(), int64(1), "arbitrage", models.Scheduling{})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SaveSchedulingOverride", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClientService_Manifests(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	service := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	client := &models.Client{ID: 1, Image: "test-image", CPU: "500m", Memory: "16GB"}
+	mockRepo.On("ClientByID", int64(1)).Return(client, nil)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{ClientID: 1, TWAP: true}, nil)
+	mockRepo.On("SchedulingOverrides", mock.Anything, int64(1)).Return(map[string]models.Scheduling{}, nil)
+	mockRepo.On("AlgorithmParameters", mock.Anything, int64(1)).Return(map[string]models.AlgorithmParameters{}, nil)
+	mockK8sDeployer.On("RenderPod", mock.MatchedBy(func(spec k8s.PodSpec) bool {
+		return spec.Name == "twap-1" && spec.Image == "test-image" &&
+			spec.Resources == k8s.Resources{CPU: "500m"} && spec.Labels["algosync/algorithm"] == "twap"
+	})).Return([]byte("kind: Pod\n"), nil)
+
+	res, err := service.Manifests(context.Background(), int64(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "---\nkind: Pod\n", string(res))
+	mockRepo.AssertExpectations(t)
+	mockK8sDeployer.AssertExpectations(t)
+}
+
+func TestClientService_Manifests_RedactsSecrets(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	mockSecrets := new(MockSecretService)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), mockSecrets, k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, Image: "test-image"}, nil)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{ClientID: 1, HFT: true}, nil)
+	mockRepo.On("SchedulingOverrides", mock.Anything, int64(1)).Return(map[string]models.Scheduling{}, nil)
+	mockRepo.On("AlgorithmParameters", mock.Anything, int64(1)).Return(map[string]models.AlgorithmParameters{}, nil)
+	mockSecrets.On("Secrets", mock.Anything, int64(1)).Return([]models.ClientSecret{
+		{Name: "EXCHANGE_API_KEY", Injection: models.SecretInjectionEnv},
+		{Name: "EXCHANGE_API_SECRET", Injection: models.SecretInjectionSecret},
+	}, nil)
+	mockK8sDeployer.On("RenderPod", mock.MatchedBy(func(spec k8s.PodSpec) bool {
+		return spec.Env[len(spec.Env)-1] == k8s.EnvVar{Name: "EXCHANGE_API_KEY", Value: "<redacted>"} &&
+			len(spec.SecretEnv) == 1 && spec.SecretEnv[0].Name == "EXCHANGE_API_SECRET"
+	})).Return([]byte("kind: Pod\n"), nil)
+
+	_, err := svc.Manifests(context.Background(), int64(1))
+
+	assert.NoError(t, err)
+	mockSecrets.AssertNotCalled(t, "Decrypt", mock.Anything, mock.Anything)
+	mockK8sDeployer.AssertExpectations(t)
+}
+
+func TestClientService_Manifests_NotFound(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	mockRepo.On("ClientByID", int64(1)).Return((*models.Client)(nil), service.ErrClientNotFound)
+
+	_, err := svc.Manifests(context.Background(), int64(1))
+
+	assert.ErrorIs(t, err, service.ErrClientNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_MigrateClient(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+	source := new(MockKubernetesDeployer)
+	target := new(MockKubernetesDeployer)
+	deployers := &MockDeployerFactory{
+		defaultDeployer: source,
+		deployers:       map[string]k8s.KubernetesDeployer{"eu": target},
+	}
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), deployers, new(MockNotifier), service.SyncConfig{})
+
+	clusterID := int64(7)
+	client := &models.Client{ID: 1, Image: "test-image"}
+	states := []models.AlgorithmState{{ClientID: 1, Algorithm: models.AlgorithmVWAP, Phase: string(k8s.PodRunning)}}
+
+	mockRepo.On("ClientByID", int64(1)).Return(client, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return((*models.ClientPause)(nil), nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{{ID: 7, Name: "eu"}}, nil)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{ClientID: 1, VWAP: true}, nil)
+	target.On("Health").Return(nil)
+	source.On("DeletePod", mock.Anything).Return(nil)
+	mockRepo.On("Update", int64(1), map[string]interface{}{"cluster_id": &clusterID}).Return(nil)
+	mockRepo.On("AlgorithmStates", mock.Anything, int64(1)).Return(states, nil)
+	mockRepo.On("SchedulingOverrides", mock.Anything, int64(1)).Return(map[string]models.Scheduling{}, nil)
+	mockRepo.On("AlgorithmParameters", mock.Anything, int64(1)).Return(map[string]models.AlgorithmParameters{}, nil)
+	mockRepo.On("AlgorithmWindows", mock.Anything, int64(1)).Return(map[string]models.AlgorithmWindow{}, nil)
+	target.On("CreatePod", mock.Anything).Return(nil)
+	target.On("WaitForPodReady", "vwap-1", mock.Anything).Return(&k8s.PodStatus{Name: "vwap-1", Phase: k8s.PodRunning, Ready: true}, nil)
+	target.On("DeletePod", mock.Anything).Return(nil)
+	mockRepo.On("SaveAlgorithmState", mock.Anything, mock.Anything).Return(nil)
+
+	res, err := svc.MigrateClient(context.Background(), int64(1), &clusterID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, states, res)
+	source.AssertNumberOfCalls(t, "DeletePod", len(models.Algorithms))
+	target.AssertCalled(t, "CreatePod", mock.MatchedBy(func(spec k8s.PodSpec) bool { return spec.Name == "vwap-1" }))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_MigrateClient_UnhealthyTarget(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+	source := new(MockKubernetesDeployer)
+	target := new(MockKubernetesDeployer)
+	deployers := &MockDeployerFactory{
+		defaultDeployer: source,
+		deployers:       map[string]k8s.KubernetesDeployer{"eu": target},
+	}
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), deployers, new(MockNotifier), service.SyncConfig{})
+
+	clusterID := int64(7)
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return((*models.ClientPause)(nil), nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{{ID: 7, Name: "eu"}}, nil)
+	target.On("Health").Return(errors.New("connection refused"))
+
+	_, err := svc.MigrateClient(context.Background(), int64(1), &clusterID)
+
+	assert.Error(t, err)
+	source.AssertNotCalled(t, "DeletePod", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestClientService_MigrateClient_UnknownCluster(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	clusterID := int64(7)
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return((*models.ClientPause)(nil), nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil)
+
+	_, err := svc.MigrateClient(context.Background(), int64(1), &clusterID)
+
+	assert.ErrorIs(t, err, service.ErrClusterNotFound)
+}
+
+func vwapSchema(t *testing.T) *jsonschema.Schema {
+	schema, err := jsonschema.Compile([]byte(`{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["participation_rate"],
+		"properties": {
+			"participation_rate": {"type": "number", "exclusiveMinimum": 0, "maximum": 1},
+			"slice_interval": {"type": "integer", "minimum": 1}
+		}
+	}`))
+	assert.NoError(t, err)
+	return schema
+}
+
+func TestClientService_SetParameters_Invalid(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	config := service.SyncConfig{ParameterSchemas: map[string]*jsonschema.Schema{models.AlgorithmVWAP: vwapSchema(t)}}
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), config)
+
+	for _, doc := range []string{
+		`{"slice_interval": 5}`,
+		`{"participation_rate": 1.5}`,
+		`{"participation_rate": 0.1, "slice_interval": 0.5}`,
+		`{"participation_rate": 0.1, "risk": 1}`,
+		`[]`,
+	} {
+		_, err := svc.SetParameters(context.Background(), 1, models.AlgorithmVWAP, json.RawMessage(doc), false)
+		assert.ErrorIs(t, err, service.ErrInvalidParameters, doc)
+	}
+
+	_, err := svc.SetParameters(context.Background(), 1, models.AlgorithmHFT, json.RawMessage(`"not an object"`), false)
+	assert.ErrorIs(t, err, service.ErrInvalidParameters)
+
+	mockRepo.AssertNotCalled(t, "SaveAlgorithmParameters", mock.Anything, mock.Anything)
+}
+
+func TestClientService_SetParameters_AppliesAndRestarts(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	config := service.SyncConfig{ParameterSchemas: map[string]*jsonschema.Schema{models.AlgorithmVWAP: vwapSchema(t)}}
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), config)
+
+	doc := json.RawMessage(`{"participation_rate": 0.1, "slice_interval": 5}`)
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1, Image: "test-image"}, nil)
+	mockRepo.On("SaveAlgorithmParameters", mock.Anything, mock.MatchedBy(func(params *models.AlgorithmParameters) bool {
+		return params.ClientID == 1 && params.Algorithm == models.AlgorithmVWAP && string(params.Parameters) == string(doc)
+	})).Return(nil)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{ClientID: 1, VWAP: true}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return((*models.ClientPause)(nil), nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil)
+	mockK8sDeployer.On("ApplyConfigMap", mock.MatchedBy(func(spec k8s.PodSpec) bool {
+		return spec.Name == "vwap-1" && spec.Parameters == string(doc)
+	})).Return(nil)
+	mockK8sDeployer.On("DeletePod", mock.Anything).Return(nil)
+	mockRepo.On("AlgorithmStates", mock.Anything, int64(1)).Return([]models.AlgorithmState{}, nil)
+	mockRepo.On("SchedulingOverrides", mock.Anything, int64(1)).Return(map[string]models.Scheduling{}, nil)
+	mockRepo.On("AlgorithmParameters", mock.Anything, int64(1)).Return(map[string]models.AlgorithmParameters{
+		models.AlgorithmVWAP: {ClientID: 1, Algorithm: models.AlgorithmVWAP, Parameters: doc},
+	}, nil)
+	mockRepo.On("AlgorithmWindows", mock.Anything, int64(1)).Return(map[string]models.AlgorithmWindow{}, nil)
+	mockK8sDeployer.On("CreatePod", mock.MatchedBy(func(spec k8s.PodSpec) bool {
+		return spec.Name == "vwap-1" && spec.Parameters == string(doc) &&
+			spec.Env[len(spec.Env)-1] == k8s.EnvVar{Name: "ALGOSYNC_PARAMETERS_FILE", Value: "/etc/algosync/parameters.json"}
+	})).Return(nil)
+	mockRepo.On("SaveAlgorithmState", mock.Anything, mock.Anything).Return(nil)
+
+	update, err := svc.SetParameters(context.Background(), 1, models.AlgorithmVWAP, doc, true)
+
+	assert.NoError(t, err)
+	assert.True(t, update.Applied)
+	assert.True(t, update.Restarted)
+	mockK8sDeployer.AssertCalled(t, "DeletePod", "vwap-1")
+	mockK8sDeployer.AssertExpectations(t)
+}
+
+func TestClientService_SetParameters_Paused(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("SaveAlgorithmParameters", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{ClientID: 1, VWAP: true}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return(&models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice"}, nil)
+
+	update, err := svc.SetParameters(context.Background(), 1, models.AlgorithmVWAP, json.RawMessage(`{"participation_rate": 0.1}`), true)
+
+	assert.NoError(t, err)
+	assert.False(t, update.Applied)
+	assert.False(t, update.Restarted)
+	mockK8sDeployer.AssertNotCalled(t, "ApplyConfigMap", mock.Anything)
+	mockK8sDeployer.AssertNotCalled(t, "DeletePod", mock.Anything)
+}
+
+func TestClientService_PauseClient(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	expiresAt := time.Now().Add(time.Hour)
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("SavePause", mock.Anything, mock.MatchedBy(func(pause *models.ClientPause) bool {
+		return pause.ClientID == 1 && pause.Reason == "debugging" && pause.Actor == "alice" && pause.ExpiresAt == &expiresAt
+	})).Return(nil)
+
+	pause, err := svc.PauseClient(context.Background(), 1, models.PauseRequest{Reason: "debugging", Actor: "alice", ExpiresAt: &expiresAt})
+
+	assert.NoError(t, err)
+	assert.False(t, pause.PausedAt.IsZero())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClientService_PauseClient_Invalid(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	expired := time.Now().Add(-time.Minute)
+	_, err := svc.PauseClient(context.Background(), 1, models.PauseRequest{Reason: "debugging", Actor: "alice", ExpiresAt: &expired})
+	assert.ErrorIs(t, err, service.ErrInvalidPause)
+
+	mockRepo.On("ClientByID", int64(2)).Return((*models.Client)(nil), service.ErrClientNotFound)
+	_, err = svc.PauseClient(context.Background(), 2, models.PauseRequest{Reason: "debugging", Actor: "alice"})
+	assert.ErrorIs(t, err, service.ErrClientNotFound)
+
+	mockRepo.AssertNotCalled(t, "SavePause", mock.Anything, mock.Anything)
+}
+
+func TestClientService_ResumeClient(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	mockRepo.On("ClientByID", mock.Anything).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return(&models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice"}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(2)).Return((*models.ClientPause)(nil), nil)
+	mockRepo.On("DeletePause", mock.Anything, int64(1)).Return(nil)
+
+	assert.NoError(t, svc.ResumeClient(context.Background(), 1))
+	assert.ErrorIs(t, svc.ResumeClient(context.Background(), 2), service.ErrClientNotPaused)
+
+	mockRepo.AssertNumberOfCalls(t, "DeletePause", 1)
+}
+
+func TestClientService_MigrateClient_Paused(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	source := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(source), new(MockNotifier), service.SyncConfig{})
+
+	clusterID := int64(7)
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return(&models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice"}, nil)
+
+	_, err := svc.MigrateClient(context.Background(), int64(1), &clusterID)
+
+	assert.ErrorIs(t, err, service.ErrClientPaused)
+	source.AssertNotCalled(t, "DeletePod", mock.Anything)
+}
+
+func TestClientService_EngageKillSwitch(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+	mockKillSwitches := new(MockKillSwitchRepository)
+	defaultDeployer := new(MockKubernetesDeployer)
+	eu := new(MockKubernetesDeployer)
+	deployers := &MockDeployerFactory{
+		defaultDeployer: defaultDeployer,
+		deployers:       map[string]k8s.KubernetesDeployer{"eu": eu},
+	}
+	svc := service.NewClientService(mockRepo, mockClusterRepo, mockKillSwitches, new(MockSyncPlanRepository), newMockSecretService(), deployers, new(MockNotifier), service.SyncConfig{})
+
+	clusterID := int64(7)
+	switches := []models.KillSwitch{{ID: 1, Algorithm: models.AlgorithmHFT, Actor: "alice", Reason: "market incident"}}
+	clients := []models.Client{{ID: 1}, {ID: 2, ClusterID: &clusterID}, {ID: 3}}
+
+	mockKillSwitches.On("Engage", mock.Anything, []string{models.AlgorithmHFT}, "alice", "market incident").Return(switches, clients, nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{{ID: 7, Name: "eu"}}, nil)
+	defaultDeployer.On("DeletePod", "hft-1").Return(nil)
+	defaultDeployer.On("DeletePod", "hft-3").Return(errors.New("timeout"))
+	eu.On("DeletePod", "hft-2").Return(nil)
+
+	result, err := svc.EngageKillSwitch(context.Background(), models.KillSwitchRequest{
+		Algorithms: []string{models.AlgorithmHFT, models.AlgorithmHFT},
+		Actor:      "alice",
+		Reason:     "market incident",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, switches, result.KillSwitches)
+	assert.Equal(t, 3, result.DisabledClients)
+	assert.Equal(t, 2, result.DeletedPods)
+	assert.Len(t, result.Errors, 1)
+	defaultDeployer.AssertExpectations(t)
+	eu.AssertExpectations(t)
+}
+
+func TestClientService_EngageKillSwitch_UnknownAlgorithm(t *testing.T) {
+	mockKillSwitches := new(MockKillSwitchRepository)
+	svc := service.NewClientService(new(MockClientRepository), new(MockClusterRepository), mockKillSwitches, new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	_, err := svc.EngageKillSwitch(context.Background(), models.KillSwitchRequest{Algorithms: []string{"foo"}, Actor: "alice"})
+
+	assert.Error(t, err)
+	mockKillSwitches.AssertNotCalled(t, "Engage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClientService_ReleaseKillSwitch_AllAlgorithms(t *testing.T) {
+	mockKillSwitches := new(MockKillSwitchRepository)
+	svc := service.NewClientService(new(MockClientRepository), new(MockClusterRepository), mockKillSwitches, new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	released := []models.KillSwitch{{ID: 1, Algorithm: models.AlgorithmHFT, Actor: "alice"}}
+	mockKillSwitches.On("Release", mock.Anything, models.Algorithms, "bob").Return(released, nil)
+
+	switches, err := svc.ReleaseKillSwitch(context.Background(), models.KillSwitchRequest{Actor: "bob"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, released, switches)
+}
+
+func TestClientService_UpdateAlgorithmStatus_KillSwitchEngaged(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockKillSwitches := new(MockKillSwitchRepository)
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), mockKillSwitches, new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), service.SyncConfig{})
+
+	mockKillSwitches.On("KillSwitches", mock.Anything).Return([]models.KillSwitch{{Algorithm: models.AlgorithmHFT, Actor: "alice"}}, nil)
+	mockRepo.On("UpdateAlgorithmStatus", int64(1), mock.Anything).Return(nil)
+
+	err := svc.UpdateAlgorithmStatus(1, map[string]interface{}{"vwap": true, "hft": true})
+	assert.ErrorIs(t, err, service.ErrKillSwitchEngaged)
+	mockRepo.AssertNotCalled(t, "UpdateAlgorithmStatus", mock.Anything, mock.Anything)
+
+	err = svc.UpdateAlgorithmStatus(1, map[string]interface{}{"hft": false})
+	assert.NoError(t, err)
+	mockRepo.AssertCalled(t, "UpdateAlgorithmStatus", int64(1), map[string]interface{}{"hft": false})
+}
+
+func TestClientService_SetAlgorithmWindow(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	nyse, err := calendar.New(calendar.Spec{Timezone: "America/New_York", Days: []string{"mon", "fri"}, Open: "09:30", Close: "16:00"})
+	assert.NoError(t, err)
+	config := service.SyncConfig{Calendars: map[string]*calendar.Calendar{"nyse": nyse}}
+	svc := service.NewClientService(mockRepo, new(MockClusterRepository), newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(new(MockKubernetesDeployer)), new(MockNotifier), config)
+
+	for _, window := range []models.AlgorithmWindow{
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Cron: "* 9-16 * * *", Calendar: "nyse"},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Cron: "* 9-16 * *"},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Cron: "* 9-16 * * *", Timezone: "Mars/Olympus"},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Calendar: "lse"},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Calendar: "nyse", Timezone: "UTC"},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Calendar: "nyse", StartBefore: -1},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Calendar: "nyse", StopAfter: 24*60 + 1},
+	} {
+		window := window
+		assert.ErrorIs(t, svc.SetAlgorithmWindow(context.Background(), &window), service.ErrInvalidWindow, window)
+	}
+	mockRepo.AssertNotCalled(t, "SaveAlgorithmWindow", mock.Anything, mock.Anything)
+
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("SaveAlgorithmWindow", mock.Anything, mock.Anything).Return(nil)
+
+	window := &models.AlgorithmWindow{ClientID: 1, Algorithm: models.AlgorithmTWAP, Calendar: "nyse", StartBefore: 15, StopAfter: 5}
+	assert.NoError(t, svc.SetAlgorithmWindow(context.Background(), window))
+
+	window = &models.AlgorithmWindow{ClientID: 1, Algorithm: models.AlgorithmVWAP, Cron: "30-59 9 * * MON-FRI", Timezone: "America/New_York"}
+	assert.NoError(t, svc.SetAlgorithmWindow(context.Background(), window))
+	mockRepo.AssertNumberOfCalls(t, "SaveAlgorithmWindow", 2)
+}
+
+func TestClientService_SetAlgorithmEnabled_Paused(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
 
-	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{VWAP: true}, nil)
-	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(2)).Return(&models.AlgorithmStatus{VWAP: false}, nil)
+	mockRepo.On("AlgorithmByClientID", mock.Anything, int64(1)).Return(&models.AlgorithmStatus{ID: 5, ClientID: 1}, nil)
+	mockRepo.On("UpdateAlgorithmStatus", int64(5), map[string]interface{}{"twap": true}).Return(nil)
+	mockRepo.On("ResetAlgorithmFailures", mock.Anything, int64(5), []string{models.AlgorithmTWAP}).Return(nil)
+	mockRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("Pause", mock.Anything, int64(1)).Return(&models.ClientPause{ClientID: 1, Reason: "debugging", Actor: "alice"}, nil)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil)
+
+	err := svc.SetAlgorithmEnabled(context.Background(), 1, models.AlgorithmTWAP, true)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockK8sDeployer.AssertNotCalled(t, "CreatePod", mock.Anything)
+
+	err = svc.SetAlgorithmEnabled(context.Background(), 1, "iceberg", true)
+	assert.Error(t, err)
+}
+
+func TestStartAlgorithmSync(t *testing.T) {
+	mockRepo := new(MockClientRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	mockClusterRepo := new(MockClusterRepository)
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil)
+
+	service := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), new(MockSyncPlanRepository), newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	states := []models.DesiredState{
+		{Client: models.Client{ID: 1, ClientName: "Client1"}, Algorithm: &models.AlgorithmStatus{VWAP: true}},
+		{Client: models.Client{ID: 2, ClientName: "Client2"}, Algorithm: &models.AlgorithmStatus{VWAP: false}},
+	}
+	mockRepo.On("DesiredStates", mock.Anything, int64(0), 0).Return(states, nil)
 
-	mockK8sDeployer.On("CreatePod", mock.Anything, mock.Anything).Return(nil)
+	mockK8sDeployer.On("CreatePod", mock.Anything).Return(nil)
 	mockK8sDeployer.On("DeletePod", mock.Anything).Return(nil)
 
 	go service.StartAlgorithmSync()
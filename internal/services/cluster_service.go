@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"test-task/infra/k8s"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+// ErrClusterNotFound is returned when the requested cluster does not exist.
+var ErrClusterNotFound = domain.New(domain.NotFound, "cluster_not_found", "cluster not found")
+
+// defaultClusterName is reported for clients that are not assigned to a cluster.
+const defaultClusterName = "default"
+
+type ClusterService interface {
+	Create(ctx context.Context, cluster *models.Cluster) (int64, error)
+	Clusters(ctx context.Context) ([]models.Cluster, error)
+	Delete(ctx context.Context, id int64) error
+	Health(ctx context.Context) ([]models.ClusterHealth, error)
+}
+
+type clusterService struct {
+	repository repository.ClusterRepository
+	deployers  k8s.DeployerFactory
+	log        logger.Logger
+}
+
+func NewClusterService(clusterRepo repository.ClusterRepository, deployers k8s.DeployerFactory) ClusterService {
+	logger := logger.GetLogger()
+	return &clusterService{
+		repository: clusterRepo,
+		deployers:  deployers,
+		log:        logger,
+	}
+}
+
+func (cs *clusterService) Create(ctx context.Context, cluster *models.Cluster) (int64, error) {
+	return cs.repository.Create(ctx, cluster)
+}
+
+func (cs *clusterService) Clusters(ctx context.Context) ([]models.Cluster, error) {
+	return cs.repository.Clusters(ctx)
+}
+
+func (cs *clusterService) Delete(ctx context.Context, id int64) error {
+	return cs.repository.Delete(ctx, id)
+}
+
+// Health checks the default cluster and every registered cluster concurrently.
+// The default cluster is always reported first.
+func (cs *clusterService) Health(ctx context.Context) ([]models.ClusterHealth, error) {
+	clusters, err := cs.repository.Clusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	health := make([]models.ClusterHealth, len(clusters)+1)
+	health[0] = models.ClusterHealth{Name: defaultClusterName}
+	for i := range clusters {
+		id := clusters[i].ID
+		health[i+1] = models.ClusterHealth{ClusterID: &id, Name: clusters[i].Name}
+	}
+
+	var wg sync.WaitGroup
+	for i := range health {
+		deployer := cs.deployers.Default()
+		if i > 0 {
+			deployer = cs.deployers.Deployer(clusterTarget(&clusters[i-1]))
+		}
+
+		wg.Add(1)
+		go func(h *models.ClusterHealth, deployer k8s.KubernetesDeployer) {
+			defer wg.Done()
+
+			err := deployer.Health()
+			h.CheckedAt = time.Now()
+			h.Healthy = err == nil
+			if err != nil {
+				h.Error = err.Error()
+			}
+		}(&health[i], deployer)
+	}
+	wg.Wait()
+
+	return health, nil
+}
+
+// clusterTarget converts a cluster record into the kubectl target of a deployer.
+func clusterTarget(cluster *models.Cluster) k8s.Cluster {
+	return k8s.Cluster{
+		Name:       cluster.Name,
+		Context:    cluster.Context,
+		Server:     cluster.APIEndpoint,
+		Kubeconfig: cluster.CredentialsRef,
+	}
+}
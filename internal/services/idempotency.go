@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is used again with a different request body.
+	ErrIdempotencyKeyReused = domain.New(domain.Validation, "idempotency_key_reused", "idempotency key was used with a different request")
+	// ErrIdempotencyKeyInUse is returned when the first request with an idempotency key is still in progress.
+	ErrIdempotencyKeyInUse = domain.New(domain.Conflict, "idempotency_key_in_use", "request with the idempotency key is in progress")
+)
+
+// Defaults of idempotency keys.
+const (
+	defaultIdempotencyTTL = 24 * time.Hour
+	// idempotencyLockTimeout is how long a request may hold its key without refreshing it before
+	// it is considered abandoned, for example because the instance processing it stopped.
+	// Requests in progress refresh their key every third of it, so they may take longer.
+	idempotencyLockTimeout = time.Minute
+)
+
+// IdempotencyService makes requests with an idempotency key safe to retry: the response of
+// the first request with a key is stored and returned for retries with the same key and body.
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope, key string, body []byte) (*models.IdempotencyRecord, error)
+	Hold(ctx context.Context, scope, key string, body []byte) (stop func())
+	Complete(ctx context.Context, scope, key string, body []byte, statusCode int, contentType string, response []byte) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+type idempotencyService struct {
+	repository  repository.IdempotencyRepository
+	ttl         time.Duration
+	lockTimeout time.Duration
+	log         logger.Logger
+}
+
+// NewIdempotencyService creates the idempotency service. Responses are kept for ttl,
+// 24 hours if zero, after the first request with their key.
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	logger := logger.GetLogger()
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &idempotencyService{
+		repository:  idempotencyRepo,
+		ttl:         ttl,
+		lockTimeout: idempotencyLockTimeout,
+		log:         logger,
+	}
+}
+
+// Begin claims the key of a request in the scope of its endpoint. It returns nil if the request
+// is the first with the key and has to be processed, followed by Complete or Release, or the
+// stored record whose response is to be returned if it is a retry.
+// Reusing a key with a different body fails with ErrIdempotencyKeyReused, and retrying while
+// the first request is in progress fails with ErrIdempotencyKeyInUse.
+func (is *idempotencyService) Begin(ctx context.Context, scope, key string, body []byte) (*models.IdempotencyRecord, error) {
+	const op = "service.idempotency.Begin"
+
+	now := time.Now()
+	record := &models.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash(body),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(is.ttl),
+	}
+
+	existing, err := is.repository.Claim(ctx, record, now.Add(-is.lockTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.RequestHash != record.RequestHash {
+		return nil, fmt.Errorf("%w: key %q", ErrIdempotencyKeyReused, key)
+	}
+	if !existing.Done() {
+		return nil, fmt.Errorf("%w: key %q", ErrIdempotencyKeyInUse, key)
+	}
+
+	is.log.Infof("%s: replaying response %d of %s for idempotency key %q", op, existing.StatusCode, scope, key)
+
+	return existing, nil
+}
+
+// Hold keeps the key claimed by Begin from being taken for abandoned while its request is in
+// progress, however long it takes, by refreshing it until stop is called. stop waits for a
+// refresh in progress, so it must be called before Complete or Release; later calls do nothing.
+func (is *idempotencyService) Hold(ctx context.Context, scope, key string, body []byte) (stop func()) {
+	const op = "service.idempotency.Hold"
+
+	record := &models.IdempotencyRecord{Scope: scope, Key: key, RequestHash: requestHash(body)}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(is.lockTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := is.repository.Refresh(ctx, record, time.Now()); err != nil && ctx.Err() == nil {
+					is.log.Errorf("%s: failed to refresh idempotency key %q of %s: %v", op, key, scope, err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// Complete stores the response of the first request with a key, to be returned for its retries.
+func (is *idempotencyService) Complete(ctx context.Context, scope, key string, body []byte, statusCode int, contentType string, response []byte) error {
+	return is.repository.Complete(ctx, &models.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash(body),
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        response,
+	})
+}
+
+// Release frees the key of a request whose response is not to be stored, so that a retry is processed again.
+func (is *idempotencyService) Release(ctx context.Context, scope, key string) error {
+	return is.repository.Release(ctx, scope, key)
+}
+
+// requestHash returns the hex SHA-256 hash of a request body. JSON bodies are hashed in
+// canonical form, so that retries differing only in whitespace or key order match.
+func requestHash(body []byte) string {
+	sum := sha256.Sum256(canonicalJSON(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalJSON re-encodes a JSON document with sorted object keys and without insignificant
+// whitespace, keeping numbers as written. Bodies that are not a single JSON document are returned as is.
+func canonicalJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return body
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return body
+	}
+
+	canonical, err := json.Marshal(document)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
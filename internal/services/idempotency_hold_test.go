@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/util/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIdempotencyRepository records the refreshed idempotency records.
+type fakeIdempotencyRepository struct {
+	repository.IdempotencyRepository
+
+	mu        sync.Mutex
+	refreshed []*models.IdempotencyRecord
+}
+
+func (r *fakeIdempotencyRepository) Refresh(ctx context.Context, record *models.IdempotencyRecord, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refreshed = append(r.refreshed, record)
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) refreshes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.refreshed)
+}
+
+func TestIdempotencyService_Hold(t *testing.T) {
+	repo := &fakeIdempotencyRepository{}
+	is := &idempotencyService{repository: repo, ttl: time.Hour, lockTimeout: 30 * time.Millisecond, log: logger.GetLogger()}
+
+	stop := is.Hold(context.Background(), "client.add", "k1", []byte(`{"b": 1, "a": 2}`))
+	assert.Eventually(t, func() bool { return repo.refreshes() >= 3 }, time.Second, 5*time.Millisecond)
+	stop()
+	stop()
+
+	refreshes := repo.refreshes()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, refreshes, repo.refreshes(), "refreshed after stop")
+
+	record := repo.refreshed[0]
+	assert.Equal(t, "client.add", record.Scope)
+	assert.Equal(t, "k1", record.Key)
+	assert.Equal(t, requestHash([]byte(`{"a":2,"b":1}`)), record.RequestHash)
+}
@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Claim(ctx context.Context, record *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, record, staleBefore)
+	return args.Get(0).(*models.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Refresh(ctx context.Context, record *models.IdempotencyRecord, now time.Time) error {
+	args := m.Called(ctx, record, now)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	args := m.Called(ctx, scope, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	svc := service.NewIdempotencyService(repo, time.Hour)
+	body := []byte(`{"client_name":"alice"}`)
+
+	repo.On("Claim", mock.Anything, mock.MatchedBy(func(record *models.IdempotencyRecord) bool {
+		return record.Scope == "client.add" && record.Key == "k1" && record.ExpiresAt.Sub(record.CreatedAt) == time.Hour
+	}), mock.Anything).Return((*models.IdempotencyRecord)(nil), nil).Once()
+	stored, err := svc.Begin(context.Background(), "client.add", "k1", body)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	sum := sha256.Sum256(body)
+	done := &models.IdempotencyRecord{Scope: "client.add", Key: "k2", RequestHash: hex.EncodeToString(sum[:]), StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":7}`)}
+	repo.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(done, nil).Once()
+	stored, err = svc.Begin(context.Background(), "client.add", "k2", body)
+	assert.NoError(t, err)
+	assert.Equal(t, done, stored)
+
+	repo.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(done, nil).Once()
+	_, err = svc.Begin(context.Background(), "client.add", "k2", []byte(`{"client_name":"bob"}`))
+	assert.ErrorIs(t, err, service.ErrIdempotencyKeyReused)
+	assert.ErrorIs(t, err, domain.Validation)
+
+	inProgress := &models.IdempotencyRecord{Scope: "client.add", Key: "k3", RequestHash: done.RequestHash}
+	repo.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(inProgress, nil).Once()
+	_, err = svc.Begin(context.Background(), "client.add", "k3", body)
+	assert.ErrorIs(t, err, service.ErrIdempotencyKeyInUse)
+	assert.ErrorIs(t, err, domain.Conflict)
+
+	repo.AssertExpectations(t)
+}
+
+func TestIdempotencyService_Complete(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	svc := service.NewIdempotencyService(repo, 0)
+
+	repo.On("Complete", mock.Anything, mock.MatchedBy(func(record *models.IdempotencyRecord) bool {
+		return record.Key == "k1" && record.StatusCode == 201 && string(record.Body) == `{"id":7}` && len(record.RequestHash) == 64
+	})).Return(nil)
+
+	err := svc.Complete(context.Background(), "client.add", "k1", []byte(`{}`), 201, "application/json", []byte(`{"id":7}`))
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestIdempotencyService_Begin_CanonicalBody(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	svc := service.NewIdempotencyService(repo, time.Hour)
+
+	var first string
+	repo.On("Claim", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		first = args.Get(1).(*models.IdempotencyRecord).RequestHash
+	}).Return((*models.IdempotencyRecord)(nil), nil).Once()
+	_, err := svc.Begin(context.Background(), "client.add", "k1", []byte(`{"client_name":"alice","priority":5,"algorithms":{"vwap":true,"hft":false}}`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		body   string
+		reused bool
+	}{
+		{name: "whitespace", body: "{\n  \"client_name\": \"alice\",\n  \"priority\": 5,\n  \"algorithms\": {\"vwap\": true, \"hft\": false}\n}"},
+		{name: "key order", body: `{"priority":5,"algorithms":{"hft":false,"vwap":true},"client_name":"alice"}`},
+		{name: "different value", body: `{"client_name":"alice","priority":6,"algorithms":{"vwap":true,"hft":false}}`, reused: true},
+		{name: "different number literal", body: `{"client_name":"alice","priority":5.0,"algorithms":{"vwap":true,"hft":false}}`, reused: true},
+		{name: "trailing data", body: `{"client_name":"alice","priority":5,"algorithms":{"vwap":true,"hft":false}} {}`, reused: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := &models.IdempotencyRecord{Scope: "client.add", Key: "k1", RequestHash: first, StatusCode: 201}
+			repo.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(done, nil).Once()
+
+			stored, err := svc.Begin(context.Background(), "client.add", "k1", []byte(tt.body))
+
+			if tt.reused {
+				assert.ErrorIs(t, err, service.ErrIdempotencyKeyReused)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, done, stored)
+			}
+		})
+	}
+}
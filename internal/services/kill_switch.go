@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"test-task/infra/k8s"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+)
+
+// ErrKillSwitchEngaged is returned when enabling an algorithm whose kill switch is engaged.
+var ErrKillSwitchEngaged = repository.ErrKillSwitchEngaged
+
+// reasonKillSwitch is the reason of algorithm states whose pod was deleted right after
+// its creation because the kill switch was engaged in the meantime.
+const reasonKillSwitch = "KillSwitchEngaged"
+
+// EngageKillSwitch stops the given algorithms, or every algorithm if none are given,
+// across all clients. The algorithms are disabled in the database in a single transaction,
+// after which their pods are deleted by up to SyncConfig.Workers workers. Pods of paused clients
+// are deleted too. Pods created concurrently by the synchronization are deleted by deployPod
+// once it sees the engaged kill switch, so the client locks are not waited for.
+// The algorithms cannot be enabled again until the kill switch is released.
+// Pods that could not be deleted are reported and removed by the next synchronization.
+func (cs *clientService) EngageKillSwitch(ctx context.Context, request models.KillSwitchRequest) (*models.KillSwitchResult, error) {
+	const op = "service.client.EngageKillSwitch"
+
+	algorithms, err := killSwitchAlgorithms(request.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	switches, clients, err := cs.killSwitches.Engage(ctx, algorithms, request.Actor, request.Reason)
+	if err != nil {
+		return nil, err
+	}
+	cs.killed.engage(algorithms)
+
+	result := &models.KillSwitchResult{KillSwitches: switches, DisabledClients: len(clients)}
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("algorithms disabled but pods not deleted: %w", err)
+	}
+
+	workers := cs.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	type podDeletion struct {
+		deployer k8s.KubernetesDeployer
+		name     string
+	}
+	queue := make(chan podDeletion, workers)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for deletion := range queue {
+				err := deletion.deployer.DeletePod(deletion.name)
+
+				mu.Lock()
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", deletion.name, err))
+				} else {
+					result.DeletedPods++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, client := range clients {
+		deployer, err := cs.deployerFor(client, clusters)
+		if err != nil {
+			mu.Lock()
+			result.Errors = append(result.Errors, fmt.Sprintf("client %d: %v", client.ID, err))
+			mu.Unlock()
+			continue
+		}
+
+		for _, algorithm := range algorithms {
+			queue <- podDeletion{deployer: deployer, name: podName(client.ID, algorithm)}
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	cs.log.Warnf("%s: kill switch for %v engaged by %s: %d clients disabled, %d pods deleted, %d errors",
+		op, algorithms, request.Actor, result.DisabledClients, result.DeletedPods, len(result.Errors))
+
+	return result, nil
+}
+
+// ReleaseKillSwitch releases the kill switches of the given algorithms, or of every
+// algorithm if none are given. Released algorithms stay disabled until they are enabled
+// again per client. It returns the released kill switches.
+func (cs *clientService) ReleaseKillSwitch(ctx context.Context, request models.KillSwitchRequest) ([]models.KillSwitch, error) {
+	algorithms, err := killSwitchAlgorithms(request.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	switches, err := cs.killSwitches.Release(ctx, algorithms, request.Actor)
+	if err != nil {
+		return nil, err
+	}
+	cs.killed.release(algorithms)
+
+	return switches, nil
+}
+
+// KillSwitches returns the engaged kill switches.
+func (cs *clientService) KillSwitches(ctx context.Context) ([]models.KillSwitch, error) {
+	return cs.killSwitches.KillSwitches(ctx)
+}
+
+// checkKillSwitches returns ErrKillSwitchEngaged if the kill switch of one of the algorithms is engaged.
+func (cs *clientService) checkKillSwitches(ctx context.Context, algorithms []string) error {
+	switches, err := cs.killSwitches.KillSwitches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ks := range switches {
+		for _, algorithm := range algorithms {
+			if ks.Algorithm == algorithm {
+				return fmt.Errorf("%w for %s by %s", ErrKillSwitchEngaged, algorithm, ks.Actor)
+			}
+		}
+	}
+
+	return nil
+}
+
+// refreshKillSwitches reloads the engaged kill switches, so that kill switches engaged
+// or released by other instances are honored by the synchronization.
+func (cs *clientService) refreshKillSwitches(ctx context.Context) error {
+	switches, err := cs.killSwitches.KillSwitches(ctx)
+	if err != nil {
+		return err
+	}
+
+	algorithms := make([]string, 0, len(switches))
+	for _, ks := range switches {
+		algorithms = append(algorithms, ks.Algorithm)
+	}
+	cs.killed.set(algorithms)
+
+	return nil
+}
+
+// killSwitchAlgorithms validates an algorithm filter, an empty filter selects every algorithm type.
+func killSwitchAlgorithms(filter []string) ([]string, error) {
+	if len(filter) == 0 {
+		return models.Algorithms, nil
+	}
+
+	seen := make(map[string]bool, len(filter))
+	algorithms := make([]string, 0, len(filter))
+	for _, algorithm := range filter {
+		if !models.IsAlgorithm(algorithm) {
+			return nil, fmt.Errorf("unknown algorithm %q", algorithm)
+		}
+		if !seen[algorithm] {
+			seen[algorithm] = true
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+
+	return algorithms, nil
+}
+
+// killedAlgorithms is the set of algorithm types whose kill switch is engaged.
+// The synchronization never creates pods of these algorithms, even for clients
+// whose desired state was read before the kill switch was engaged.
+type killedAlgorithms struct {
+	mu         sync.RWMutex
+	algorithms map[string]bool
+}
+
+func newKilledAlgorithms() *killedAlgorithms {
+	return &killedAlgorithms{algorithms: make(map[string]bool)}
+}
+
+func (k *killedAlgorithms) engage(algorithms []string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, algorithm := range algorithms {
+		k.algorithms[algorithm] = true
+	}
+}
+
+func (k *killedAlgorithms) release(algorithms []string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, algorithm := range algorithms {
+		delete(k.algorithms, algorithm)
+	}
+}
+
+func (k *killedAlgorithms) set(algorithms []string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.algorithms = make(map[string]bool, len(algorithms))
+	for _, algorithm := range algorithms {
+		k.algorithms[algorithm] = true
+	}
+}
+
+// engaged reports whether the kill switch of the algorithm is engaged.
+func (k *killedAlgorithms) engaged(algorithm string) bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.algorithms[algorithm]
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+var (
+	// ErrScheduledChangeNotFound is returned when the requested pending change does not exist.
+	ErrScheduledChangeNotFound = domain.New(domain.NotFound, "scheduled_change_not_found", "scheduled change not found")
+	// ErrInvalidScheduledChange is returned when a scheduled change request is malformed.
+	ErrInvalidScheduledChange = domain.New(domain.Validation, "invalid_scheduled_change", "invalid scheduled change")
+)
+
+// dueChangesBatch is the maximum number of changes applied per scheduler tick.
+const dueChangesBatch = 100
+
+// AlgorithmSwitch is the part of ClientService scheduled changes are applied with.
+type AlgorithmSwitch interface {
+	ClientByID(id int64) (*models.Client, error)
+	SetAlgorithmEnabled(ctx context.Context, clientID int64, algorithm string, enabled bool) error
+}
+
+// Leader reports whether this instance is the elected leader of the service instances.
+type Leader interface {
+	IsLeader(ctx context.Context) (bool, error)
+}
+
+type ScheduledChangeService interface {
+	Schedule(ctx context.Context, clientID int64, request models.ScheduledChangeRequest) (*models.ScheduledChange, error)
+	ScheduledChanges(ctx context.Context, clientID int64) ([]models.ScheduledChange, error)
+	Cancel(ctx context.Context, clientID, changeID int64) error
+	AuditEntries(ctx context.Context, clientID int64, limit int) ([]models.AuditEntry, error)
+	ApplyDueChanges(ctx context.Context) (int, error)
+	StartScheduler()
+}
+
+type scheduledChangeService struct {
+	repository repository.ScheduledChangeRepository
+	audit      repository.AuditRepository
+	clients    AlgorithmSwitch
+	leader     Leader
+	interval   time.Duration
+	log        logger.Logger
+}
+
+// NewScheduledChangeService creates the scheduled change service. Due changes are applied
+// every interval, 30 seconds if zero, by the instance that is the leader.
+func NewScheduledChangeService(changeRepo repository.ScheduledChangeRepository, auditRepo repository.AuditRepository, clients AlgorithmSwitch, leader Leader, interval time.Duration) ScheduledChangeService {
+	logger := logger.GetLogger()
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &scheduledChangeService{
+		repository: changeRepo,
+		audit:      auditRepo,
+		clients:    clients,
+		leader:     leader,
+		interval:   interval,
+		log:        logger,
+	}
+}
+
+// Schedule stores a change of an algorithm flag of a client to be applied at request.ApplyAt,
+// which must be in the future.
+func (ss *scheduledChangeService) Schedule(ctx context.Context, clientID int64, request models.ScheduledChangeRequest) (*models.ScheduledChange, error) {
+	const op = "service.scheduledChange.Schedule"
+
+	if !models.IsAlgorithm(request.Algorithm) {
+		return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidScheduledChange, request.Algorithm)
+	}
+	if request.Enabled == nil {
+		return nil, fmt.Errorf("%w: enabled is required", ErrInvalidScheduledChange)
+	}
+	if !request.ApplyAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: apply_at must be in the future", ErrInvalidScheduledChange)
+	}
+
+	if _, err := ss.clients.ClientByID(clientID); err != nil {
+		return nil, err
+	}
+
+	change := &models.ScheduledChange{
+		ClientID:  clientID,
+		Algorithm: request.Algorithm,
+		Enabled:   *request.Enabled,
+		ApplyAt:   request.ApplyAt,
+		Actor:     request.Actor,
+		Reason:    request.Reason,
+	}
+	if err := ss.repository.Create(ctx, change); err != nil {
+		return nil, err
+	}
+
+	ss.log.Infof("%s: %s enabled=%t scheduled for client %d at %v by %s", op, change.Algorithm, change.Enabled, clientID, change.ApplyAt, change.Actor)
+
+	return change, nil
+}
+
+// ScheduledChanges returns the scheduled changes of a client, including applied and cancelled ones.
+func (ss *scheduledChangeService) ScheduledChanges(ctx context.Context, clientID int64) ([]models.ScheduledChange, error) {
+	return ss.repository.ScheduledChanges(ctx, clientID)
+}
+
+// Cancel cancels a pending change of a client. Changes that are being applied
+// or were applied already cannot be cancelled.
+func (ss *scheduledChangeService) Cancel(ctx context.Context, clientID, changeID int64) error {
+	cancelled, err := ss.repository.Cancel(ctx, clientID, changeID)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrScheduledChangeNotFound
+	}
+
+	return nil
+}
+
+// AuditEntries returns the latest limit audit entries of a client, newest first.
+func (ss *scheduledChangeService) AuditEntries(ctx context.Context, clientID int64, limit int) ([]models.AuditEntry, error) {
+	return ss.audit.AuditEntries(ctx, clientID, limit)
+}
+
+// StartScheduler starts applying due changes every interval in the background.
+// Every instance runs the scheduler, but only the leader applies changes, so a change
+// is applied once however many instances run. Changes are stored in the database, so
+// changes that became due while no instance was running are applied once one starts.
+func (ss *scheduledChangeService) StartScheduler() {
+	const op = "service.scheduledChange.StartScheduler"
+
+	ticker := time.NewTicker(ss.interval)
+
+	go func() {
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := ss.ApplyDueChanges(context.Background()); err != nil {
+				ss.log.Errorf("%s: Failed to apply due changes: %v", op, err)
+			}
+		}
+	}()
+
+	ss.log.Infof("%s: Scheduler started, checking for due changes every %v", op, ss.interval)
+}
+
+// ApplyDueChanges applies the changes that are due if this instance is the leader,
+// oldest first, and records each applied or failed change in the audit history.
+// It returns the number of changes applied.
+func (ss *scheduledChangeService) ApplyDueChanges(ctx context.Context) (int, error) {
+	const op = "service.scheduledChange.ApplyDueChanges"
+
+	leader, err := ss.leader.IsLeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !leader {
+		return 0, nil
+	}
+
+	changes, err := ss.repository.DueChanges(ctx, time.Now(), dueChangesBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i := range changes {
+		change := &changes[i]
+
+		claimed, err := ss.repository.Claim(ctx, change.ID)
+		if err != nil {
+			return applied, err
+		}
+		if !claimed {
+			ss.log.Debugf("%s: Change %d was cancelled before it was applied", op, change.ID)
+			continue
+		}
+
+		now := time.Now()
+		change.AppliedAt = &now
+		change.Status = models.ScheduledChangeApplied
+		if err := ss.clients.SetAlgorithmEnabled(ctx, change.ClientID, change.Algorithm, change.Enabled); err != nil {
+			change.Status = models.ScheduledChangeFailed
+			change.Error = err.Error()
+			ss.log.Errorf("%s: Failed to apply change %d to client %d: %v", op, change.ID, change.ClientID, err)
+		} else {
+			applied++
+		}
+
+		if err := ss.repository.Finish(ctx, change); err != nil {
+			return applied, err
+		}
+		ss.record(ctx, change)
+	}
+
+	return applied, nil
+}
+
+// record adds an applied or failed change to the audit history. Failing to record it
+// is logged only, as the change itself was already made.
+func (ss *scheduledChangeService) record(ctx context.Context, change *models.ScheduledChange) {
+	const op = "service.scheduledChange.record"
+
+	action := models.AuditScheduledChangeApplied
+	if change.Status == models.ScheduledChangeFailed {
+		action = models.AuditScheduledChangeFailed
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"change_id": change.ID,
+		"enabled":   change.Enabled,
+		"apply_at":  change.ApplyAt,
+		"reason":    change.Reason,
+		"error":     change.Error,
+	})
+	if err != nil {
+		ss.log.Errorf("%s: Failed to encode audit details of change %d: %v", op, change.ID, err)
+		return
+	}
+
+	entry := &models.AuditEntry{
+		Actor:     change.Actor,
+		Action:    action,
+		ClientID:  change.ClientID,
+		Algorithm: change.Algorithm,
+		Details:   details,
+	}
+	if err := ss.audit.Record(ctx, entry); err != nil {
+		ss.log.Errorf("%s: Failed to record change %d in the audit history: %v", op, change.ID, err)
+	}
+}
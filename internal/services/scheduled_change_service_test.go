@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScheduledChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduledChangeRepository) Create(ctx context.Context, change *models.ScheduledChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockScheduledChangeRepository) ScheduledChanges(ctx context.Context, clientID int64) ([]models.ScheduledChange, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).([]models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) Cancel(ctx context.Context, clientID, id int64) (bool, error) {
+	args := m.Called(ctx, clientID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) DueChanges(ctx context.Context, now time.Time, limit int) ([]models.ScheduledChange, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) Claim(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) Finish(ctx context.Context, change *models.ScheduledChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) AuditEntries(ctx context.Context, clientID int64, limit int) ([]models.AuditEntry, error) {
+	args := m.Called(ctx, clientID, limit)
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
+
+type MockAlgorithmSwitch struct {
+	mock.Mock
+}
+
+func (m *MockAlgorithmSwitch) ClientByID(id int64) (*models.Client, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
+func (m *MockAlgorithmSwitch) SetAlgorithmEnabled(ctx context.Context, clientID int64, algorithm string, enabled bool) error {
+	args := m.Called(ctx, clientID, algorithm, enabled)
+	return args.Error(0)
+}
+
+type staticLeader bool
+
+func (l staticLeader) IsLeader(ctx context.Context) (bool, error) {
+	return bool(l), nil
+}
+
+func TestScheduledChangeService_Schedule(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	clients := new(MockAlgorithmSwitch)
+	svc := service.NewScheduledChangeService(changes, new(MockAuditRepository), clients, staticLeader(true), time.Minute)
+
+	enabled := true
+	applyAt := time.Now().Add(time.Hour)
+	clients.On("ClientByID", int64(42)).Return(&models.Client{ID: 42}, nil)
+	changes.On("Create", mock.Anything, mock.MatchedBy(func(change *models.ScheduledChange) bool {
+		return change.ClientID == 42 && change.Algorithm == models.AlgorithmTWAP && change.Enabled && change.ApplyAt.Equal(applyAt) && change.Actor == "alice"
+	})).Return(nil)
+
+	change, err := svc.Schedule(context.Background(), 42, models.ScheduledChangeRequest{Algorithm: models.AlgorithmTWAP, Enabled: &enabled, ApplyAt: applyAt, Actor: "alice"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), change.ClientID)
+	changes.AssertExpectations(t)
+}
+
+func TestScheduledChangeService_Schedule_Invalid(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	clients := new(MockAlgorithmSwitch)
+	svc := service.NewScheduledChangeService(changes, new(MockAuditRepository), clients, staticLeader(true), time.Minute)
+
+	enabled := true
+	_, err := svc.Schedule(context.Background(), 42, models.ScheduledChangeRequest{Algorithm: "iceberg", Enabled: &enabled, ApplyAt: time.Now().Add(time.Hour), Actor: "alice"})
+	assert.ErrorIs(t, err, service.ErrInvalidScheduledChange)
+
+	_, err = svc.Schedule(context.Background(), 42, models.ScheduledChangeRequest{Algorithm: models.AlgorithmHFT, Enabled: &enabled, ApplyAt: time.Now().Add(-time.Minute), Actor: "alice"})
+	assert.ErrorIs(t, err, service.ErrInvalidScheduledChange)
+
+	clients.On("ClientByID", int64(43)).Return((*models.Client)(nil), service.ErrClientNotFound)
+	_, err = svc.Schedule(context.Background(), 43, models.ScheduledChangeRequest{Algorithm: models.AlgorithmHFT, Enabled: &enabled, ApplyAt: time.Now().Add(time.Hour), Actor: "alice"})
+	assert.ErrorIs(t, err, service.ErrClientNotFound)
+
+	changes.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestScheduledChangeService_ApplyDueChanges(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	audit := new(MockAuditRepository)
+	clients := new(MockAlgorithmSwitch)
+	svc := service.NewScheduledChangeService(changes, audit, clients, staticLeader(true), time.Minute)
+
+	due := []models.ScheduledChange{
+		{ID: 1, ClientID: 42, Algorithm: models.AlgorithmTWAP, Enabled: true, Actor: "alice", Status: models.ScheduledChangePending},
+		{ID: 2, ClientID: 43, Algorithm: models.AlgorithmHFT, Enabled: true, Actor: "bob", Status: models.ScheduledChangePending},
+		{ID: 3, ClientID: 44, Algorithm: models.AlgorithmVWAP, Actor: "carol", Status: models.ScheduledChangePending},
+	}
+	changes.On("DueChanges", mock.Anything, mock.Anything, mock.Anything).Return(due, nil)
+	changes.On("Claim", mock.Anything, int64(1)).Return(true, nil)
+	changes.On("Claim", mock.Anything, int64(2)).Return(true, nil)
+	changes.On("Claim", mock.Anything, int64(3)).Return(false, nil)
+	clients.On("SetAlgorithmEnabled", mock.Anything, int64(42), models.AlgorithmTWAP, true).Return(nil)
+	clients.On("SetAlgorithmEnabled", mock.Anything, int64(43), models.AlgorithmHFT, true).Return(errors.New("kill switch engaged"))
+	changes.On("Finish", mock.Anything, mock.MatchedBy(func(change *models.ScheduledChange) bool {
+		return change.ID == 1 && change.Status == models.ScheduledChangeApplied && change.AppliedAt != nil
+	})).Return(nil)
+	changes.On("Finish", mock.Anything, mock.MatchedBy(func(change *models.ScheduledChange) bool {
+		return change.ID == 2 && change.Status == models.ScheduledChangeFailed && change.Error == "kill switch engaged"
+	})).Return(nil)
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.ClientID == 42 && entry.Actor == "alice" && entry.Action == models.AuditScheduledChangeApplied
+	})).Return(nil)
+	audit.On("Record", mock.Anything, mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.ClientID == 43 && entry.Actor == "bob" && entry.Action == models.AuditScheduledChangeFailed
+	})).Return(nil)
+
+	applied, err := svc.ApplyDueChanges(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	changes.AssertExpectations(t)
+	audit.AssertExpectations(t)
+	clients.AssertNotCalled(t, "SetAlgorithmEnabled", mock.Anything, int64(44), mock.Anything, mock.Anything)
+}
+
+func TestScheduledChangeService_ApplyDueChanges_NotLeader(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	svc := service.NewScheduledChangeService(changes, new(MockAuditRepository), new(MockAlgorithmSwitch), staticLeader(false), time.Minute)
+
+	applied, err := svc.ApplyDueChanges(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, applied)
+	changes.AssertNotCalled(t, "DueChanges", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"test-task/internal/repository"
+	"test-task/pkg/encryption"
+	"test-task/pkg/util/logger"
+)
+
+// ErrInvalidSecret is returned when a secret name or injection mode is not accepted.
+var ErrInvalidSecret = domain.New(domain.Validation, "invalid_secret", "invalid secret")
+
+// redactedValue replaces secret values in rendered manifests.
+const redactedValue = "<redacted>"
+
+type SecretService interface {
+	Secrets(ctx context.Context, clientID int64) ([]models.ClientSecret, error)
+	SetSecret(ctx context.Context, clientID int64, name string, value models.SecretValue) (*models.ClientSecret, error)
+	DeleteSecret(ctx context.Context, clientID int64, name string) error
+	Decrypt(ctx context.Context, clientID int64) ([]models.ClientSecret, error)
+	DecryptClients(ctx context.Context, clientIDs []int64) (map[int64]DecryptedSecrets, error)
+}
+
+// DecryptedSecrets holds the decrypted secrets of a client, or the error decrypting them.
+type DecryptedSecrets struct {
+	Secrets []models.ClientSecret
+	Err     error
+}
+
+type secretService struct {
+	repository       repository.SecretRepository
+	clientRepository repository.ClientRepository
+	cipher           encryption.Cipher
+	log              logger.Logger
+}
+
+func NewSecretService(secretRepo repository.SecretRepository, clientRepo repository.ClientRepository, cipher encryption.Cipher) SecretService {
+	logger := logger.GetLogger()
+	return &secretService{
+		repository:       secretRepo,
+		clientRepository: clientRepo,
+		cipher:           cipher,
+		log:              logger,
+	}
+}
+
+// Secrets returns the secrets of a client without their values.
+func (ss *secretService) Secrets(ctx context.Context, clientID int64) ([]models.ClientSecret, error) {
+	if err := ss.checkClient(clientID); err != nil {
+		return nil, err
+	}
+
+	secrets, err := ss.repository.Secrets(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range secrets {
+		secrets[i].Ciphertext = nil
+	}
+
+	return secrets, nil
+}
+
+// SetSecret encrypts and stores a secret of a client, replacing the secret with the same name.
+// Running pods keep the previous value until they are recreated.
+func (ss *secretService) SetSecret(ctx context.Context, clientID int64, name string, value models.SecretValue) (*models.ClientSecret, error) {
+	if !models.IsSecretName(name) {
+		return nil, fmt.Errorf("%w: name %q must be a valid environment variable name not starting with %s", ErrInvalidSecret, name, models.ReservedEnvPrefix)
+	}
+	if value.Injection == "" {
+		value.Injection = models.SecretInjectionSecret
+	}
+	if !models.IsSecretInjection(value.Injection) {
+		return nil, fmt.Errorf("%w: unknown injection %q", ErrInvalidSecret, value.Injection)
+	}
+
+	if err := ss.checkClient(clientID); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := ss.cipher.Encrypt([]byte(value.Value), secretAAD(clientID, name))
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &models.ClientSecret{
+		ClientID:   clientID,
+		Name:       name,
+		Injection:  value.Injection,
+		Ciphertext: ciphertext,
+	}
+	if err := ss.repository.SaveSecret(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	secret.Ciphertext = nil
+
+	return secret, nil
+}
+
+// DeleteSecret deletes a secret of a client.
+func (ss *secretService) DeleteSecret(ctx context.Context, clientID int64, name string) error {
+	return ss.repository.DeleteSecret(ctx, clientID, name)
+}
+
+// Decrypt returns the secrets of a client with their decrypted values for injection into pods.
+func (ss *secretService) Decrypt(ctx context.Context, clientID int64) ([]models.ClientSecret, error) {
+	secrets, err := ss.repository.Secrets(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.decrypt(clientID, secrets)
+}
+
+// DecryptClients returns the secrets of the given clients with their decrypted values, read with
+// a single query and keyed by client ID. A secret that cannot be decrypted only fails its client.
+func (ss *secretService) DecryptClients(ctx context.Context, clientIDs []int64) (map[int64]DecryptedSecrets, error) {
+	secrets, err := ss.repository.SecretsByClients(ctx, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := make(map[int64]DecryptedSecrets, len(secrets))
+	for clientID, clientSecrets := range secrets {
+		values, err := ss.decrypt(clientID, clientSecrets)
+		decrypted[clientID] = DecryptedSecrets{Secrets: values, Err: err}
+	}
+
+	return decrypted, nil
+}
+
+// decrypt decrypts the values of the secrets of a client in place.
+func (ss *secretService) decrypt(clientID int64, secrets []models.ClientSecret) ([]models.ClientSecret, error) {
+	const op = "service.secret.Decrypt"
+
+	for i := range secrets {
+		value, err := ss.cipher.Decrypt(secrets[i].Ciphertext, secretAAD(clientID, secrets[i].Name))
+		if err != nil {
+			ss.log.Errorf("%s: failed to decrypt secret %s of client %d: %v", op, secrets[i].Name, clientID, err)
+			return nil, fmt.Errorf("failed to decrypt secret %s: %w", secrets[i].Name, err)
+		}
+		secrets[i].Value = string(value)
+		secrets[i].Ciphertext = nil
+	}
+
+	return secrets, nil
+}
+
+// secretAAD returns the additional data a secret value is encrypted with. It binds the ciphertext
+// to its client and name, so a ciphertext copied to another row fails to decrypt.
+func secretAAD(clientID int64, name string) []byte {
+	return []byte(fmt.Sprintf("%d|%s", clientID, name))
+}
+
+// checkClient returns ErrClientNotFound if the client does not exist.
+func (ss *secretService) checkClient(clientID int64) error {
+	_, err := ss.clientRepository.ClientByID(clientID)
+	return err
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"test-task/pkg/encryption"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSecretKey = "0123456789abcdef0123456789abcdef"
+
+type MockSecretRepository struct {
+	mock.Mock
+}
+
+func (m *MockSecretRepository) Secrets(ctx context.Context, clientID int64) ([]models.ClientSecret, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).([]models.ClientSecret), args.Error(1)
+}
+
+func (m *MockSecretRepository) SecretsByClients(ctx context.Context, clientIDs []int64) (map[int64][]models.ClientSecret, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).(map[int64][]models.ClientSecret), args.Error(1)
+}
+
+func (m *MockSecretRepository) SaveSecret(ctx context.Context, secret *models.ClientSecret) error {
+	args := m.Called(ctx, secret)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) DeleteSecret(ctx context.Context, clientID int64, name string) error {
+	args := m.Called(ctx, clientID, name)
+	return args.Error(0)
+}
+
+func TestSecretService_SetSecret(t *testing.T) {
+	mockRepo := new(MockSecretRepository)
+	mockClientRepo := new(MockClientRepository)
+	cipher, err := encryption.NewAESCipher(testSecretKey)
+	assert.NoError(t, err)
+	svc := service.NewSecretService(mockRepo, mockClientRepo, cipher)
+
+	var stored []byte
+	mockClientRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("SaveSecret", mock.Anything, mock.MatchedBy(func(secret *models.ClientSecret) bool {
+		stored = secret.Ciphertext
+		return secret.ClientID == 1 && secret.Name == "EXCHANGE_API_KEY" && secret.Injection == models.SecretInjectionSecret
+	})).Return(nil)
+
+	secret, err := svc.SetSecret(context.Background(), 1, "EXCHANGE_API_KEY", models.SecretValue{Value: "s3cr3t"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, secret.Ciphertext)
+	assert.Empty(t, secret.Value)
+	assert.NotContains(t, string(stored), "s3cr3t")
+
+	mockRepo.On("Secrets", mock.Anything, int64(1)).Return([]models.ClientSecret{
+		{ClientID: 1, Name: "EXCHANGE_API_KEY", Injection: models.SecretInjectionSecret, Ciphertext: stored},
+	}, nil)
+
+	secrets, err := svc.Decrypt(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", secrets[0].Value)
+	assert.Nil(t, secrets[0].Ciphertext)
+}
+
+func TestSecretService_Decrypt_BoundToClientAndName(t *testing.T) {
+	mockRepo := new(MockSecretRepository)
+	mockClientRepo := new(MockClientRepository)
+	cipher, err := encryption.NewAESCipher(testSecretKey)
+	assert.NoError(t, err)
+	svc := service.NewSecretService(mockRepo, mockClientRepo, cipher)
+
+	var stored []byte
+	mockClientRepo.On("ClientByID", int64(1)).Return(&models.Client{ID: 1}, nil)
+	mockRepo.On("SaveSecret", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.ClientSecret).Ciphertext
+	}).Return(nil)
+
+	_, err = svc.SetSecret(context.Background(), 1, "EXCHANGE_API_KEY", models.SecretValue{Value: "s3cr3t"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		clientID int64
+		secret   string
+	}{
+		{name: "other client", clientID: 2, secret: "EXCHANGE_API_KEY"},
+		{name: "other name", clientID: 1, secret: "EXCHANGE_API_SECRET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("Secrets", mock.Anything, tt.clientID).Return([]models.ClientSecret{
+				{ClientID: tt.clientID, Name: tt.secret, Injection: models.SecretInjectionSecret, Ciphertext: append([]byte(nil), stored...)},
+			}, nil).Once()
+
+			_, err := svc.Decrypt(context.Background(), tt.clientID)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewAESCipher_Key(t *testing.T) {
+	_, err := encryption.NewAESCipher("")
+	assert.ErrorIs(t, err, encryption.ErrEmptyKey)
+
+	_, err = encryption.NewAESCipher("sercret")
+	assert.ErrorIs(t, err, encryption.ErrShortKey)
+
+	_, err = encryption.NewAESCipher(testSecretKey)
+	assert.NoError(t, err)
+}
+
+func TestSecretService_SetSecret_Invalid(t *testing.T) {
+	mockRepo := new(MockSecretRepository)
+	cipher, err := encryption.NewAESCipher(testSecretKey)
+	assert.NoError(t, err)
+	svc := service.NewSecretService(mockRepo, new(MockClientRepository), cipher)
+
+	_, err = svc.SetSecret(context.Background(), 1, "ALGOSYNC_CLIENT_ID", models.SecretValue{Value: "1"})
+	assert.ErrorIs(t, err, service.ErrInvalidSecret)
+
+	_, err = svc.SetSecret(context.Background(), 1, "API-KEY", models.SecretValue{Value: "1"})
+	assert.ErrorIs(t, err, service.ErrInvalidSecret)
+
+	_, err = svc.SetSecret(context.Background(), 1, "API_KEY", models.SecretValue{Value: "1", Injection: "file"})
+	assert.ErrorIs(t, err, service.ErrInvalidSecret)
+
+	mockRepo.AssertNotCalled(t, "SaveSecret", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"test-task/internal/models"
+	"time"
+)
+
+// syncMetrics collects queue depth and per-client latency of the synchronization,
+// and the per-client outcome of the current and last sync run.
+type syncMetrics struct {
+	mu sync.Mutex
+
+	workers    int
+	queueDepth int
+	inFlight   int
+	cycles     int64
+	started    *time.Time
+	duration   time.Duration
+	summary    models.LatencySummary
+	current    []time.Duration
+	clients    map[int64]time.Duration
+	run        *models.SyncRun
+	lastRun    *models.SyncRun
+}
+
+func newSyncMetrics() *syncMetrics {
+	return &syncMetrics{clients: make(map[int64]time.Duration)}
+}
+
+// startCycle records the start of a cycle reconciled by the given number of workers.
+func (m *syncMetrics) startCycle(workers int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.started = &now
+	m.workers = workers
+	m.queueDepth = 0
+	m.current = nil
+	m.run = &models.SyncRun{StartedAt: now, Clients: make([]models.ClientSyncResult, 0)}
+}
+
+// enqueued records that a client was queued for reconciliation.
+func (m *syncMetrics) enqueued() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueDepth++
+}
+
+// dequeued records that a worker picked up a client.
+func (m *syncMetrics) dequeued() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueDepth--
+	m.inFlight++
+}
+
+// clientSynced records the outcome and reconciliation time of a client.
+func (m *syncMetrics) clientSynced(result models.ClientSyncResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	m.current = append(m.current, result.Duration)
+	m.clients[result.ClientID] = result.Duration
+
+	if m.run == nil {
+		return
+	}
+	m.run.Clients = append(m.run.Clients, result)
+	switch result.Status {
+	case models.SyncStatusSynced:
+		m.run.Synced++
+	case models.SyncStatusPaused:
+		m.run.Paused++
+	default:
+		m.run.Skipped++
+	}
+}
+
+// endCycle records the end of a cycle and summarizes its client latencies.
+// Clients that were not part of the cycle are dropped from the per-client latencies.
+func (m *syncMetrics) endCycle(clientIDs map[int64]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cycles++
+	if m.started != nil {
+		m.duration = time.Since(*m.started)
+	}
+	m.summary = summarize(m.current)
+	for id := range m.clients {
+		if !clientIDs[id] {
+			delete(m.clients, id)
+		}
+	}
+
+	if m.run != nil {
+		finished := time.Now()
+		m.run.FinishedAt = &finished
+		sort.Slice(m.run.Clients, func(i, j int) bool { return m.run.Clients[i].ClientID < m.run.Clients[j].ClientID })
+		m.lastRun, m.run = m.run, nil
+	}
+}
+
+// lastSyncRun returns the results of the last finished sync run, or nil if no run finished yet.
+func (m *syncMetrics) lastSyncRun() *models.SyncRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lastRun
+}
+
+// snapshot returns a copy of the collected metrics.
+func (m *syncMetrics) snapshot() models.SyncMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clients := make(map[int64]time.Duration, len(m.clients))
+	for id, latency := range m.clients {
+		clients[id] = latency
+	}
+
+	return models.SyncMetrics{
+		Workers:            m.workers,
+		QueueDepth:         m.queueDepth,
+		InFlight:           m.inFlight,
+		Cycles:             m.cycles,
+		LastCycleStartedAt: m.started,
+		LastCycleDuration:  m.duration,
+		ClientLatency:      m.summary,
+		Clients:            clients,
+	}
+}
+
+func summarize(latencies []time.Duration) models.LatencySummary {
+	if len(latencies) == 0 {
+		return models.LatencySummary{}
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+
+	return models.LatencySummary{
+		Count: len(sorted),
+		Avg:   total / time.Duration(len(sorted)),
+		P95:   sorted[(len(sorted)*95-1)/100],
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// clientLocks serializes reconciliation of the same client across the worker pool
+// and API-triggered rollouts.
+type clientLocks struct {
+	mu    sync.Mutex
+	locks map[int64]*sync.Mutex
+}
+
+func newClientLocks() *clientLocks {
+	return &clientLocks{locks: make(map[int64]*sync.Mutex)}
+}
+
+// lock locks the client and returns the function unlocking it.
+func (l *clientLocks) lock(clientID int64) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[clientID]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[clientID] = lock
+	}
+	l.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"sync"
+	"test-task/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	assert.Equal(t, 0, summarize(nil).Count)
+
+	latencies := make([]time.Duration, 0, 20)
+	for i := 20; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	summary := summarize(latencies)
+	assert.Equal(t, 20, summary.Count)
+	assert.Equal(t, 10500*time.Microsecond, summary.Avg)
+	assert.Equal(t, 19*time.Millisecond, summary.P95)
+	assert.Equal(t, 20*time.Millisecond, summary.Max)
+}
+
+func TestSyncMetrics(t *testing.T) {
+	metrics := newSyncMetrics()
+	metrics.clients[3] = time.Second
+
+	metrics.startCycle(2)
+	metrics.enqueued()
+	metrics.enqueued()
+	metrics.dequeued()
+
+	snapshot := metrics.snapshot()
+	assert.Equal(t, 2, snapshot.Workers)
+	assert.Equal(t, 1, snapshot.QueueDepth)
+	assert.Equal(t, 1, snapshot.InFlight)
+
+	metrics.clientSynced(models.ClientSyncResult{ClientID: 2, Status: models.SyncStatusPaused, Duration: 30 * time.Millisecond})
+	metrics.dequeued()
+	metrics.clientSynced(models.ClientSyncResult{ClientID: 1, Status: models.SyncStatusSynced, Duration: 10 * time.Millisecond})
+	assert.Nil(t, metrics.lastSyncRun())
+	metrics.endCycle(map[int64]bool{1: true, 2: true})
+
+	snapshot = metrics.snapshot()
+	assert.Equal(t, 0, snapshot.QueueDepth)
+	assert.Equal(t, 0, snapshot.InFlight)
+	assert.Equal(t, int64(1), snapshot.Cycles)
+	assert.Equal(t, 20*time.Millisecond, snapshot.ClientLatency.Avg)
+	assert.Equal(t, map[int64]time.Duration{1: 10 * time.Millisecond, 2: 30 * time.Millisecond}, snapshot.Clients)
+
+	run := metrics.lastSyncRun()
+	assert.NotNil(t, run.FinishedAt)
+	assert.Equal(t, 1, run.Synced)
+	assert.Equal(t, 1, run.Paused)
+	assert.Equal(t, []int64{1, 2}, []int64{run.Clients[0].ClientID, run.Clients[1].ClientID})
+}
+
+func TestClientLocks(t *testing.T) {
+	locks := newClientLocks()
+
+	var mu sync.Mutex
+	running := map[int64]int{}
+	overlap := false
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(clientID int64) {
+			defer wg.Done()
+			defer locks.lock(clientID)()
+
+			mu.Lock()
+			running[clientID]++
+			if running[clientID] > 1 {
+				overlap = true
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			running[clientID]--
+			mu.Unlock()
+		}(int64(i % 2))
+	}
+	wg.Wait()
+
+	assert.False(t, overlap)
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"test-task/internal/domain"
+	"test-task/internal/models"
+	"time"
+)
+
+var (
+	// ErrPlanNotFound is returned when the requested sync plan does not exist.
+	ErrPlanNotFound = domain.New(domain.NotFound, "sync_plan_not_found", "sync plan not found")
+	// ErrPlanNotPending is returned when applying a plan that was applied or refused already.
+	ErrPlanNotPending = domain.New(domain.Conflict, "sync_plan_not_pending", "sync plan is not pending")
+	// ErrPlanStale is returned when applying a plan after the state it was computed from changed.
+	ErrPlanStale = domain.New(domain.Conflict, "sync_plan_stale", "state changed since the plan was computed")
+)
+
+// computedPlan holds the actions the synchronization would take, the clients they
+// touch and the fingerprint of the state of every client they were computed from.
+type computedPlan struct {
+	actions      []models.PlanAction
+	clients      map[int64]models.Client
+	fingerprints map[int64]string
+}
+
+// fingerprint returns the fingerprint of the state of the given clients. A client that
+// no longer has a desired state, e.g. because it was deleted, changes the fingerprint too.
+func (p *computedPlan) fingerprint(clientIDs []int64) string {
+	ids := append([]int64(nil), clientIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	h := sha256.New()
+	for _, clientID := range ids {
+		fingerprint, ok := p.fingerprints[clientID]
+		if !ok {
+			fingerprint = "missing"
+		}
+		fmt.Fprintf(h, "%d %s\n", clientID, fingerprint)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// touchedClients returns the IDs of the clients with actions in ascending order.
+func (p *computedPlan) touchedClients() []int64 {
+	clientIDs := make([]int64, 0, len(p.clients))
+	for clientID := range p.clients {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Slice(clientIDs, func(i, j int) bool { return clientIDs[i] < clientIDs[j] })
+
+	return clientIDs
+}
+
+// PlanSync computes the pod actions the synchronization would take for every client,
+// based on the desired state and the observed state recorded by the last synchronization,
+// and stores them as a pending plan. The fingerprint of the plan covers the state of the
+// clients with actions only. Nothing is changed in the clusters.
+func (cs *clientService) PlanSync(ctx context.Context) (*models.SyncPlan, error) {
+	const op = "service.client.PlanSync"
+
+	computed, err := cs.computePlan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.SyncPlan{Fingerprint: computed.fingerprint(computed.touchedClients()), Actions: computed.actions}
+	if err := cs.syncPlans.Create(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	cs.log.Infof("%s: plan %d computed with %d actions", op, plan.ID, len(plan.Actions))
+
+	return plan, nil
+}
+
+// SyncPlan returns a stored sync plan.
+func (cs *clientService) SyncPlan(ctx context.Context, id int64) (*models.SyncPlan, error) {
+	plan, err := cs.syncPlans.Plan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, ErrPlanNotFound
+	}
+
+	return plan, nil
+}
+
+// ApplySyncPlan executes exactly the actions of a pending plan. The plan is recomputed
+// while the clients it touches are locked, and if the desired or observed state of any
+// of these clients changed since the plan was computed, the plan is marked stale and
+// refused with ErrPlanStale. A plan is applied at most once. If it cannot be applied
+// after it was claimed, it is made pending again so that it can be retried. Once the
+// actions started, they are finished even if the caller goes away. Failed actions are
+// reported in the returned plan and retried by the synchronization.
+func (cs *clientService) ApplySyncPlan(ctx context.Context, id int64) (*models.SyncPlan, error) {
+	const op = "service.client.ApplySyncPlan"
+
+	plan, err := cs.SyncPlan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.PlanPending {
+		return nil, fmt.Errorf("%w: plan %d is %s", ErrPlanNotPending, id, plan.Status)
+	}
+
+	// Lock the clients in ascending order; reconciliations only ever hold one client lock.
+	var clientIDs []int64
+	byClient := make(map[int64][]*models.PlanAction)
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		if _, ok := byClient[action.ClientID]; !ok {
+			clientIDs = append(clientIDs, action.ClientID)
+		}
+		byClient[action.ClientID] = append(byClient[action.ClientID], action)
+	}
+	sort.Slice(clientIDs, func(i, j int) bool { return clientIDs[i] < clientIDs[j] })
+	for _, clientID := range clientIDs {
+		defer cs.locks.lock(clientID)()
+	}
+
+	computed, err := cs.computePlan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if computed.fingerprint(clientIDs) != plan.Fingerprint {
+		if _, err := cs.syncPlans.UpdateStatus(ctx, id, models.PlanPending, models.PlanStale); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: plan %d", ErrPlanStale, id)
+	}
+
+	claimed, err := cs.syncPlans.UpdateStatus(ctx, id, models.PlanPending, models.PlanApplying)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: plan %d is being applied", ErrPlanNotPending, id)
+	}
+
+	// The plan is applying now and has to leave that status whatever happens to the caller.
+	ctx = context.WithoutCancel(ctx)
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		if _, releaseErr := cs.syncPlans.UpdateStatus(ctx, id, models.PlanApplying, models.PlanPending); releaseErr != nil {
+			cs.log.Errorf("%s: Failed to make plan %d pending again: %v", op, id, releaseErr)
+		}
+		return nil, err
+	}
+
+	failed := 0
+	for _, clientID := range clientIDs {
+		actions := byClient[clientID]
+		if err := cs.applyPlanActions(ctx, computed.clients[clientID], clusters, actions); err != nil {
+			for _, action := range actions {
+				action.Error = err.Error()
+			}
+		}
+		for _, action := range actions {
+			if action.Error != "" {
+				failed++
+			}
+		}
+	}
+
+	now := time.Now()
+	plan.Status = models.PlanApplied
+	plan.AppliedAt = &now
+	if err := cs.syncPlans.Finish(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	cs.log.Infof("%s: plan %d applied, %d of %d actions failed", op, id, failed, len(plan.Actions))
+
+	return plan, nil
+}
+
+// applyPlanActions executes the plan actions of a client and records the resulting
+// algorithm states. Errors of single actions are set on the action. Callers must hold
+// the client lock.
+func (cs *clientService) applyPlanActions(ctx context.Context, client models.Client, clusters map[int64]*models.Cluster, actions []*models.PlanAction) error {
+	const op = "service.client.applyPlanActions"
+
+	deployer, err := cs.deployerFor(client, clusters)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := cs.clientInputs(ctx, client.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, action := range actions {
+		var state models.AlgorithmState
+		switch action.Action {
+		case models.PlanActionDelete:
+			state = cs.deletePod(deployer, client, action.Algorithm, action.PodName, nil)
+		case models.PlanActionReplace:
+			if state = cs.deletePod(deployer, client, action.Algorithm, action.PodName, nil); state.LastError != "" {
+				break
+			}
+			fallthrough
+		default:
+			spec := podSpec(client, action.Algorithm, action.PodName, inputs.scheduling[action.Algorithm], inputs.secrets, string(inputs.parameters[action.Algorithm].Parameters))
+			state = cs.deployPod(deployer, spec, client.ID, action.Algorithm)
+		}
+		action.Error = state.LastError
+
+		if err := cs.repository.SaveAlgorithmState(ctx, &state); err != nil {
+			cs.log.Errorf("%s: Failed to save %s state for client %d: %v", op, action.Algorithm, client.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// computePlan compares the desired state of every client with the observed state
+// recorded by the last synchronization and returns the actions that reconcile them,
+// together with the fingerprint of the state of every client. The observed states and
+// run windows are read per page of clients. Paused clients and algorithms disabled after
+// crash-looping get no actions, as the synchronization leaves them untouched.
+func (cs *clientService) computePlan(ctx context.Context) (*computedPlan, error) {
+	if err := cs.refreshKillSwitches(ctx); err != nil {
+		return nil, err
+	}
+
+	clusters, err := cs.clusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	computed := &computedPlan{
+		actions:      make([]models.PlanAction, 0),
+		clients:      make(map[int64]models.Client),
+		fingerprints: make(map[int64]string),
+	}
+	now := time.Now()
+
+	var cursor int64
+	for {
+		states, err := cs.repository.DesiredStates(ctx, cursor, cs.config.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		clientIDs := make([]int64, len(states))
+		for i, state := range states {
+			clientIDs[i] = state.Client.ID
+		}
+
+		observed, err := cs.repository.AlgorithmStatesByClients(ctx, clientIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		windows, err := cs.repository.AlgorithmWindowsByClients(ctx, clientIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range states {
+			clientID := state.Client.ID
+			actions, fingerprint := cs.planClient(state, observed[clientID], windows[clientID], clusters, now)
+			computed.fingerprints[clientID] = fingerprint
+			if len(actions) > 0 {
+				computed.actions = append(computed.actions, actions...)
+				computed.clients[clientID] = state.Client
+			}
+		}
+
+		if cs.config.BatchSize <= 0 || len(states) < cs.config.BatchSize {
+			break
+		}
+		cursor = states[len(states)-1].Client.ID
+	}
+
+	return computed, nil
+}
+
+// planClient returns the plan actions of a client and the fingerprint of the state they depend on.
+func (cs *clientService) planClient(state models.DesiredState, observed []models.AlgorithmState, windows map[string]models.AlgorithmWindow, clusters map[int64]*models.Cluster, now time.Time) ([]models.PlanAction, string) {
+	client := state.Client
+	cluster := clusterName(client.ClusterID, clusters)
+	paused := state.Pause.Active(now)
+
+	fingerprint := sha256.New()
+	fmt.Fprintf(fingerprint, "client %d %s %s %t %t\n", client.ID, client.Image, cluster, paused, state.Algorithm != nil)
+	if state.Algorithm == nil {
+		return nil, hex.EncodeToString(fingerprint.Sum(nil))
+	}
+
+	previous := make(map[string]*models.AlgorithmState, len(observed))
+	for i := range observed {
+		previous[observed[i].Algorithm] = &observed[i]
+	}
+
+	var actions []models.PlanAction
+	for _, algorithm := range models.Algorithms {
+		prev := previous[algorithm]
+		crashLooped := prev != nil && prev.FailedAt != nil
+		running := prev != nil && !crashLooped && prev.Phase != models.PhaseDeleted
+
+		var reason string
+		desired := true
+		switch {
+		case !state.Algorithm.Enabled(algorithm):
+			desired, reason = false, "algorithm disabled"
+		case cs.killed.engaged(algorithm):
+			desired, reason = false, "kill switch engaged"
+		case !cs.windowOpen(windows, algorithm, now):
+			desired, reason = false, "outside run window"
+		}
+
+		var phase, image string
+		if prev != nil {
+			phase, image = prev.Phase, prev.Image
+		}
+		fmt.Fprintf(fingerprint, "%s %t %t %s %s\n", algorithm, desired, crashLooped, phase, image)
+
+		if paused || crashLooped {
+			continue
+		}
+
+		action := models.PlanAction{
+			ClientID:  client.ID,
+			Algorithm: algorithm,
+			PodName:   podName(client.ID, algorithm),
+			Cluster:   cluster,
+			Reason:    reason,
+		}
+		switch {
+		case desired && !running:
+			action.Action = models.PlanActionCreate
+			action.Reason = "algorithm enabled, no pod running"
+		case desired && (prev.Phase == models.PhaseFailed || prev.Phase == models.PhaseUnknown):
+			action.Action = models.PlanActionReplace
+			action.Reason = fmt.Sprintf("pod is %s: %s", strings.ToLower(prev.Phase), prev.LastError)
+		case desired && !sameImage(prev.Image, client.Image):
+			action.Action = models.PlanActionReplace
+			action.Reason = fmt.Sprintf("image changed from %s to %s", prev.Image, client.Image)
+		case !desired && running:
+			action.Action = models.PlanActionDelete
+		default:
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, hex.EncodeToString(fingerprint.Sum(nil))
+}
+
+// sameImage reports whether two image references refer to the same image, treating
+// references without registry or tag like Kubernetes does (nginx is docker.io/library/nginx:latest).
+func sameImage(a, b string) bool {
+	return normalizeImage(a) == normalizeImage(b)
+}
+
+func normalizeImage(image string) string {
+	if image == "" || strings.Contains(image, "@") {
+		return image
+	}
+
+	if slash := strings.LastIndex(image, "/"); !strings.Contains(image[slash+1:], ":") {
+		image += ":latest"
+	}
+
+	first, _, found := strings.Cut(image, "/")
+	switch {
+	case !found:
+		image = "docker.io/library/" + image
+	case !strings.ContainsAny(first, ".:") && first != "localhost":
+		image = "docker.io/" + image
+	}
+
+	return image
+}
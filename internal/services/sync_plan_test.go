@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"test-task/infra/k8s"
+	"test-task/internal/models"
+	service "test-task/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// planFixture mocks two clients: client 1 with VWAP enabled but not running, HFT disabled
+// but running and TWAP enabled with a failed pod, and client 2 paused with VWAP enabled.
+// The observed states are returned once; tests recomputing the plan mock them again.
+func planFixture() (*MockClientRepository, *MockClusterRepository) {
+	mockRepo := new(MockClientRepository)
+	mockClusterRepo := new(MockClusterRepository)
+
+	states := []models.DesiredState{
+		{Client: models.Client{ID: 1, Image: "algo:1"}, Algorithm: &models.AlgorithmStatus{ID: 1, ClientID: 1, VWAP: true, TWAP: true}},
+		{Client: models.Client{ID: 2, Image: "algo:1"}, Algorithm: &models.AlgorithmStatus{ID: 2, ClientID: 2, VWAP: true}, Pause: &models.ClientPause{ClientID: 2, Reason: "debugging", Actor: "alice"}},
+	}
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil)
+	mockRepo.On("DesiredStates", mock.Anything, int64(0), 0).Return(states, nil)
+	mockRepo.On("AlgorithmStatesByClients", mock.Anything, []int64{1, 2}).Return(map[int64][]models.AlgorithmState{
+		1: {
+			{ClientID: 1, Algorithm: models.AlgorithmHFT, Phase: string(k8s.PodRunning), Image: "algo:1"},
+			{ClientID: 1, Algorithm: models.AlgorithmTWAP, Phase: models.PhaseFailed, Image: "algo:1", LastError: "image pull back-off"},
+		},
+	}, nil).Once()
+	mockRepo.On("AlgorithmWindowsByClients", mock.Anything, []int64{1, 2}).Return(map[int64]map[string]models.AlgorithmWindow{}, nil)
+	mockRepo.On("AlgorithmWindows", mock.Anything, mock.Anything).Return(map[string]models.AlgorithmWindow{}, nil).Maybe()
+
+	return mockRepo, mockClusterRepo
+}
+
+func TestClientService_PlanSync(t *testing.T) {
+	mockRepo, mockClusterRepo := planFixture()
+	mockPlans := new(MockSyncPlanRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), mockPlans, newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	mockPlans.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	plan, err := svc.PlanSync(context.Background())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, plan.Fingerprint)
+	assert.Equal(t, []models.PlanAction{
+		{ClientID: 1, Algorithm: models.AlgorithmVWAP, Action: models.PlanActionCreate, PodName: "vwap-1", Cluster: "default", Reason: "algorithm enabled, no pod running"},
+		{ClientID: 1, Algorithm: models.AlgorithmTWAP, Action: models.PlanActionReplace, PodName: "twap-1", Cluster: "default", Reason: "pod is failed: image pull back-off"},
+		{ClientID: 1, Algorithm: models.AlgorithmHFT, Action: models.PlanActionDelete, PodName: "hft-1", Cluster: "default", Reason: "algorithm disabled"},
+	}, plan.Actions)
+	mockK8sDeployer.AssertNotCalled(t, "CreatePod", mock.Anything)
+	mockK8sDeployer.AssertNotCalled(t, "DeletePod", mock.Anything)
+}
+
+func TestClientService_ApplySyncPlan(t *testing.T) {
+	mockRepo, mockClusterRepo := planFixture()
+	mockPlans := new(MockSyncPlanRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), mockPlans, newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	var stored *models.SyncPlan
+	mockPlans.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.SyncPlan)
+		stored.ID = 7
+		stored.Status = models.PlanPending
+	}).Return(nil)
+	_, err := svc.PlanSync(context.Background())
+	assert.NoError(t, err)
+
+	// The reconciler observed client 2, which the plan does not touch, in the meantime.
+	mockRepo.On("AlgorithmStatesByClients", mock.Anything, []int64{1, 2}).Return(map[int64][]models.AlgorithmState{
+		1: {
+			{ClientID: 1, Algorithm: models.AlgorithmHFT, Phase: string(k8s.PodRunning), Image: "algo:1"},
+			{ClientID: 1, Algorithm: models.AlgorithmTWAP, Phase: models.PhaseFailed, Image: "algo:1", LastError: "image pull back-off"},
+		},
+		2: {{ClientID: 2, Algorithm: models.AlgorithmVWAP, Phase: models.PhaseDeleted, Image: "algo:1"}},
+	}, nil).Once()
+	mockPlans.On("Plan", mock.Anything, int64(7)).Return(stored, nil)
+	mockPlans.On("UpdateStatus", mock.Anything, int64(7), models.PlanPending, models.PlanApplying).Return(true, nil)
+	mockPlans.On("Finish", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("SchedulingOverrides", mock.Anything, int64(1)).Return(map[string]models.Scheduling{}, nil)
+	mockRepo.On("AlgorithmParameters", mock.Anything, int64(1)).Return(map[string]models.AlgorithmParameters{}, nil)
+	mockRepo.On("SaveAlgorithmState", mock.Anything, mock.Anything).Return(nil)
+	mockK8sDeployer.On("CreatePod", mock.Anything).Return(nil)
+	mockK8sDeployer.On("DeletePod", mock.Anything).Return(nil)
+
+	plan, err := svc.ApplySyncPlan(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PlanApplied, plan.Status)
+	assert.NotNil(t, plan.AppliedAt)
+	mockK8sDeployer.AssertCalled(t, "CreatePod", mock.MatchedBy(func(spec k8s.PodSpec) bool { return spec.Name == "vwap-1" }))
+	mockK8sDeployer.AssertCalled(t, "CreatePod", mock.MatchedBy(func(spec k8s.PodSpec) bool { return spec.Name == "twap-1" }))
+	mockK8sDeployer.AssertCalled(t, "DeletePod", "twap-1")
+	mockK8sDeployer.AssertCalled(t, "DeletePod", "hft-1")
+	mockK8sDeployer.AssertNotCalled(t, "CreatePod", mock.MatchedBy(func(spec k8s.PodSpec) bool { return spec.Name == "vwap-2" }))
+	mockPlans.AssertExpectations(t)
+}
+
+func TestClientService_ApplySyncPlan_Stale(t *testing.T) {
+	mockRepo, mockClusterRepo := planFixture()
+	mockPlans := new(MockSyncPlanRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), mockPlans, newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	plan := &models.SyncPlan{
+		ID:          7,
+		Status:      models.PlanPending,
+		Fingerprint: "computed before client 1 was changed",
+		Actions:     []models.PlanAction{{ClientID: 1, Algorithm: models.AlgorithmVWAP, Action: models.PlanActionCreate, PodName: "vwap-1"}},
+		CreatedAt:   time.Now(),
+	}
+	mockPlans.On("Plan", mock.Anything, int64(7)).Return(plan, nil)
+	mockPlans.On("UpdateStatus", mock.Anything, int64(7), models.PlanPending, models.PlanStale).Return(true, nil)
+	mockRepo.On("AlgorithmStatesByClients", mock.Anything, []int64{1, 2}).Return(map[int64][]models.AlgorithmState{}, nil)
+
+	_, err := svc.ApplySyncPlan(context.Background(), 7)
+
+	assert.ErrorIs(t, err, service.ErrPlanStale)
+	mockPlans.AssertExpectations(t)
+	mockK8sDeployer.AssertNotCalled(t, "CreatePod", mock.Anything)
+
+	applied := &models.SyncPlan{ID: 8, Status: models.PlanApplied}
+	mockPlans.On("Plan", mock.Anything, int64(8)).Return(applied, nil)
+	_, err = svc.ApplySyncPlan(context.Background(), 8)
+	assert.ErrorIs(t, err, service.ErrPlanNotPending)
+}
+
+func TestClientService_ApplySyncPlan_Released(t *testing.T) {
+	mockRepo, mockClusterRepo := planFixture()
+	mockPlans := new(MockSyncPlanRepository)
+	mockK8sDeployer := new(MockKubernetesDeployer)
+	svc := service.NewClientService(mockRepo, mockClusterRepo, newMockKillSwitchRepository(), mockPlans, newMockSecretService(), k8s.NewStaticDeployerFactory(mockK8sDeployer), new(MockNotifier), service.SyncConfig{})
+
+	var stored *models.SyncPlan
+	mockPlans.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.SyncPlan)
+		stored.ID = 7
+		stored.Status = models.PlanPending
+	}).Return(nil)
+	_, err := svc.PlanSync(context.Background())
+	assert.NoError(t, err)
+
+	// The clusters are read for the plan, again for the recomputed plan, and fail once the plan is claimed.
+	mockClusterRepo.ExpectedCalls = nil
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster{}, nil).Once()
+	mockClusterRepo.On("Clusters", mock.Anything).Return([]models.Cluster(nil), errors.New("connection refused")).Once()
+	mockRepo.On("AlgorithmStatesByClients", mock.Anything, []int64{1, 2}).Return(map[int64][]models.AlgorithmState{
+		1: {
+			{ClientID: 1, Algorithm: models.AlgorithmHFT, Phase: string(k8s.PodRunning), Image: "algo:1"},
+			{ClientID: 1, Algorithm: models.AlgorithmTWAP, Phase: models.PhaseFailed, Image: "algo:1", LastError: "image pull back-off"},
+		},
+	}, nil).Once()
+	mockPlans.On("Plan", mock.Anything, int64(7)).Return(stored, nil)
+	mockPlans.On("UpdateStatus", mock.Anything, int64(7), models.PlanPending, models.PlanApplying).Return(true, nil)
+	mockPlans.On("UpdateStatus", mock.Anything, int64(7), models.PlanApplying, models.PlanPending).Return(true, nil)
+
+	_, err = svc.ApplySyncPlan(context.Background(), 7)
+
+	assert.Error(t, err)
+	mockPlans.AssertExpectations(t)
+	mockK8sDeployer.AssertNotCalled(t, "CreatePod", mock.Anything)
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_algorithm_state_phase;
+DROP TABLE IF EXISTS algorithm_state;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS algorithm_state (
+    id SERIAL PRIMARY KEY,
+    client_id INT NOT NULL,
+    algorithm VARCHAR(16) NOT NULL,
+    phase VARCHAR(32) NOT NULL,
+    ready BOOLEAN NOT NULL DEFAULT false,
+    pod_name VARCHAR(255) NOT NULL,
+    image VARCHAR(255) NOT NULL DEFAULT '',
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    started_at TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_synced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_client
+        FOREIGN KEY(client_id)
+        REFERENCES clients(id)
+        ON DELETE CASCADE,
+    CONSTRAINT uq_algorithm_state_client_algorithm UNIQUE (client_id, algorithm)
+);
+
+-- Create index for phase
+CREATE INDEX idx_algorithm_state_phase ON algorithm_state(phase);
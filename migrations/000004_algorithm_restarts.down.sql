@@ -0,0 +1,5 @@
+ALTER TABLE algorithm_state
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS restart_window_base,
+    DROP COLUMN IF EXISTS restart_window_started_at,
+    DROP COLUMN IF EXISTS restart_count;
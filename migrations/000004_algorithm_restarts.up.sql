@@ -0,0 +1,5 @@
+ALTER TABLE algorithm_state
+    ADD COLUMN IF NOT EXISTS restart_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS restart_window_started_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS restart_window_base INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS client_scheduling (
+    client_id INT NOT NULL,
+    algorithm VARCHAR(16) NOT NULL,
+    scheduling JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (client_id, algorithm),
+    CONSTRAINT fk_client
+        FOREIGN KEY(client_id)
+        REFERENCES clients(id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_clients_cluster_id;
+ALTER TABLE clients DROP COLUMN IF EXISTS cluster_id;
+DROP TRIGGER IF EXISTS update_clusters_updated_at ON clusters;
+DROP TABLE IF EXISTS clusters;
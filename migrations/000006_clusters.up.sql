@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS clusters (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    context VARCHAR(255) NOT NULL DEFAULT '',
+    api_endpoint VARCHAR(255) NOT NULL DEFAULT '',
+    credentials_ref VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Trigger to execute the function before any update on the clusters table
+CREATE TRIGGER update_clusters_updated_at
+BEFORE UPDATE ON clusters
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Clients without a cluster are deployed to the default cluster
+ALTER TABLE clients
+    ADD COLUMN IF NOT EXISTS cluster_id INT REFERENCES clusters(id) ON DELETE RESTRICT;
+
+-- Create index for cluster_id
+CREATE INDEX idx_clients_cluster_id ON clients(cluster_id);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_client_secrets_updated_at ON client_secrets;
+DROP TABLE IF EXISTS client_secrets;
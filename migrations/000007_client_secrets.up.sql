@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS client_secrets (
+    id SERIAL PRIMARY KEY,
+    client_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    ciphertext BYTEA NOT NULL,
+    injection VARCHAR(16) NOT NULL DEFAULT 'secret',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (client_id, name),
+    CONSTRAINT fk_client
+        FOREIGN KEY(client_id)
+        REFERENCES clients(id)
+        ON DELETE CASCADE
+);
+
+-- Trigger to execute the function before any update on the client_secrets table
+CREATE TRIGGER update_client_secrets_updated_at
+BEFORE UPDATE ON client_secrets
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
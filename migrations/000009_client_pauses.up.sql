@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS client_pauses (
+    client_id INT PRIMARY KEY,
+    reason TEXT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    paused_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- The pause ends automatically at expires_at, NULL pauses until resumed
+    expires_at TIMESTAMP,
+    CONSTRAINT fk_client
+        FOREIGN KEY(client_id)
+        REFERENCES clients(id)
+        ON DELETE CASCADE
+);
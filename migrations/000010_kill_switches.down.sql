@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS check_algorithm_status_kill_switches ON algorithm_status;
+DROP FUNCTION IF EXISTS check_kill_switches();
+DROP TABLE IF EXISTS kill_switches;
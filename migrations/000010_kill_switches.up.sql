@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS kill_switches (
+    id SERIAL PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    engaged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_by VARCHAR(255),
+    released_at TIMESTAMP
+);
+
+-- Only one engaged kill switch per algorithm, released ones are kept as history
+CREATE UNIQUE INDEX idx_kill_switches_engaged ON kill_switches (algorithm) WHERE released_at IS NULL;
+
+-- Function rejecting the enabling of an algorithm while its kill switch is engaged
+CREATE OR REPLACE FUNCTION check_kill_switches()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.vwap AND NOT OLD.vwap AND EXISTS (SELECT 1 FROM kill_switches WHERE algorithm = 'vwap' AND released_at IS NULL))
+        OR (NEW.twap AND NOT OLD.twap AND EXISTS (SELECT 1 FROM kill_switches WHERE algorithm = 'twap' AND released_at IS NULL))
+        OR (NEW.hft AND NOT OLD.hft AND EXISTS (SELECT 1 FROM kill_switches WHERE algorithm = 'hft' AND released_at IS NULL)) THEN
+        RAISE EXCEPTION 'kill switch engaged' USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger to execute the function before any update on the algorithm_status table
+CREATE TRIGGER check_algorithm_status_kill_switches
+BEFORE UPDATE ON algorithm_status
+FOR EACH ROW
+EXECUTE FUNCTION check_kill_switches();
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS algorithm_windows (
+    client_id INT NOT NULL,
+    algorithm VARCHAR(10) NOT NULL,
+    -- Exactly one of cron and calendar is set
+    cron VARCHAR(255),
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    calendar VARCHAR(64),
+    start_before INT NOT NULL DEFAULT 0,
+    stop_after INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (client_id, algorithm),
+    CHECK ((cron IS NULL) <> (calendar IS NULL)),
+    CONSTRAINT fk_client
+        FOREIGN KEY(client_id)
+        REFERENCES clients(id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS scheduled_changes (
+    id SERIAL PRIMARY KEY,
+    client_id INT NOT NULL,
+    algorithm VARCHAR(10) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    -- TIMESTAMPTZ so that apply_at is the instant requested whatever its offset
+    apply_at TIMESTAMPTZ NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    -- A change is applying while the scheduler applies it, a change left applying
+    -- by a scheduler that stopped is applied again by the next leader
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'applying', 'applied', 'failed', 'cancelled')),
+    applied_at TIMESTAMPTZ,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_client
+        FOREIGN KEY(client_id)
+        REFERENCES clients(id)
+        ON DELETE CASCADE
+);
+
+-- The scheduler polls the pending changes that are due
+CREATE INDEX idx_scheduled_changes_due ON scheduled_changes (apply_at) WHERE status IN ('pending', 'applying');
+
+-- Audit history is kept when the client is deleted, so client_id has no foreign key
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    client_id INT NOT NULL,
+    algorithm VARCHAR(10) NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_client ON audit_log (client_id, created_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sync_plans (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'applying', 'applied', 'stale')),
+    -- Hash of the desired and observed state the plan was computed from
+    fingerprint VARCHAR(64) NOT NULL,
+    actions JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP
+);
@@ -0,0 +1 @@
+ALTER TABLE clients ADD COLUMN revision BIGINT NOT NULL DEFAULT 1;
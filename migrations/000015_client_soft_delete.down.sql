@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_clients_deleted_at;
+ALTER TABLE clients DROP COLUMN IF EXISTS deleted_at;
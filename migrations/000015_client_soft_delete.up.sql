@@ -0,0 +1,3 @@
+-- Deleted clients are kept until purged after the retention period, NULL for live clients
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_clients_deleted_at ON clients (deleted_at) WHERE deleted_at IS NOT NULL;
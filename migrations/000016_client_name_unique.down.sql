@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_clients_client_name_unique;
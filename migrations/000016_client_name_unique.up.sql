@@ -0,0 +1,25 @@
+-- Client names are unique regardless of case among the clients that are not deleted.
+-- Existing duplicates are reported and have to be renamed or deleted before the migration
+-- is forced back to version 15 and run again.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(format('%s (ids %s)', name, ids), '; ' ORDER BY name)
+    INTO duplicates
+    FROM (
+        SELECT lower(client_name) AS name, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM clients
+        WHERE deleted_at IS NULL
+        GROUP BY lower(client_name)
+        HAVING count(*) > 1
+    ) AS duplicate_names;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'duplicate client names: %', duplicates
+            USING HINT = 'Rename or delete the duplicate clients, then run the migration again.';
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_client_name_unique ON clients (lower(client_name)) WHERE deleted_at IS NULL;
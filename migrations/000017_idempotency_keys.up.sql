@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    -- Endpoint the key was used with, the same key may be used with other endpoints
+    scope VARCHAR(64) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    -- SHA-256 of the body of the first request with the key
+    request_hash VARCHAR(64) NOT NULL,
+    -- Response of the first request, NULL while it is in progress
+    status_code INT,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
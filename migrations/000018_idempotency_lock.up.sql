@@ -0,0 +1,2 @@
+-- Last time the request holding the key reported it is still in progress
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,145 @@
+// Package calendar evaluates exchange trading sessions in their time zone,
+// skipping non-trading weekdays and holidays.
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Spec describes the trading sessions of an exchange.
+type Spec struct {
+	// Timezone is the IANA time zone of the session times.
+	Timezone string `json:"timezone"`
+	// Days are the trading weekdays as three-letter English names, e.g. "mon".
+	Days []string `json:"days"`
+	// Open and Close are the session times as HH:MM. A close at or before the
+	// open ends the session on the next day.
+	Open  string `json:"open"`
+	Close string `json:"close"`
+	// Holidays are the dates as YYYY-MM-DD on which no session opens.
+	Holidays []string `json:"holidays"`
+}
+
+// Calendar is a compiled trading calendar.
+type Calendar struct {
+	spec     Spec
+	location *time.Location
+	days     [7]bool
+	// open and close are minutes since midnight in wall clock time.
+	open, close int
+	overnight   bool
+	holidays    map[string]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// New compiles a calendar spec.
+func New(spec Spec) (*Calendar, error) {
+	location, err := time.LoadLocation(spec.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: invalid timezone %q: %w", spec.Timezone, err)
+	}
+
+	c := &Calendar{spec: spec, location: location, holidays: make(map[string]bool, len(spec.Holidays))}
+
+	if len(spec.Days) == 0 {
+		return nil, fmt.Errorf("calendar: no trading days")
+	}
+	for _, day := range spec.Days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("calendar: invalid day %q", day)
+		}
+		c.days[weekday] = true
+	}
+
+	if c.open, err = timeOfDay(spec.Open); err != nil {
+		return nil, err
+	}
+	if c.close, err = timeOfDay(spec.Close); err != nil {
+		return nil, err
+	}
+	c.overnight = c.close <= c.open
+
+	for _, holiday := range spec.Holidays {
+		if _, err := time.Parse(dateLayout, holiday); err != nil {
+			return nil, fmt.Errorf("calendar: invalid holiday %q", holiday)
+		}
+		c.holidays[holiday] = true
+	}
+
+	return c, nil
+}
+
+// Load reads calendars keyed by name from a JSON file.
+func Load(path string) (map[string]*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs map[string]Spec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("calendar: %s: %w", path, err)
+	}
+
+	calendars := make(map[string]*Calendar, len(specs))
+	for name, spec := range specs {
+		c, err := New(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		calendars[name] = c
+	}
+
+	return calendars, nil
+}
+
+// InSession reports whether t falls within a session extended by before the open
+// and after the close. before and after must not exceed a day.
+func (c *Calendar) InSession(t time.Time, before, after time.Duration) bool {
+	local := t.In(c.location)
+	for offset := -2; offset <= 1; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, c.location)
+		if !c.days[day.Weekday()] || c.holidays[day.Format(dateLayout)] {
+			continue
+		}
+
+		// time.Date normalizes the minutes into the wall clock time of the day,
+		// so sessions keep their local times across daylight saving changes.
+		closeDay := day.Day()
+		if c.overnight {
+			closeDay++
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, c.open, 0, 0, c.location).Add(-before)
+		end := time.Date(day.Year(), day.Month(), closeDay, 0, c.close, 0, 0, c.location).Add(after)
+		if !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalJSON encodes the calendar as its spec.
+func (c *Calendar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.spec)
+}
+
+// timeOfDay parses HH:MM into the minutes since midnight.
+func timeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("calendar: invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,132 @@
+package calendar_test
+
+import (
+	"test-task/pkg/calendar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInSession(t *testing.T) {
+	// A day session, an overnight weekday session and a nightly session, all in New York,
+	// where clocks go forward on 2026-03-08 (EST -05:00 to EDT -04:00) and back on 2026-11-01.
+	day, err := calendar.New(calendar.Spec{
+		Timezone: "America/New_York",
+		Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+		Open:     "09:30",
+		Close:    "16:00",
+		Holidays: []string{"2026-11-26"},
+	})
+	assert.NoError(t, err)
+	overnight, err := calendar.New(calendar.Spec{
+		Timezone: "America/New_York",
+		Days:     []string{"sun", "mon", "tue", "wed", "thu"},
+		Open:     "18:00",
+		Close:    "17:00",
+		Holidays: []string{"2026-12-24"},
+	})
+	assert.NoError(t, err)
+	nightly, err := calendar.New(calendar.Spec{
+		Timezone: "America/New_York",
+		Days:     []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"},
+		Open:     "22:00",
+		Close:    "06:00",
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		calendar *calendar.Calendar
+		at       string
+		before   time.Duration
+		after    time.Duration
+		want     bool
+	}{
+		{name: "before open", calendar: day, at: "2026-10-19T13:29:00Z", want: false},
+		{name: "at open", calendar: day, at: "2026-10-19T13:30:00Z", want: true},
+		{name: "before close", calendar: day, at: "2026-10-19T19:59:00Z", want: true},
+		{name: "at close", calendar: day, at: "2026-10-19T20:00:00Z", want: false},
+		{name: "start before open", calendar: day, at: "2026-10-19T13:15:00Z", before: 15 * time.Minute, want: true},
+		{name: "stop after close", calendar: day, at: "2026-10-19T20:14:00Z", after: 15 * time.Minute, want: true},
+		{name: "weekend", calendar: day, at: "2026-10-24T15:00:00Z", want: false},
+		{name: "holiday", calendar: day, at: "2026-11-26T15:00:00Z", want: false},
+		{name: "open in standard time", calendar: day, at: "2026-03-06T14:30:00Z", want: true},
+		{name: "closed an hour earlier in standard time", calendar: day, at: "2026-03-06T13:30:00Z", want: false},
+		{name: "open in daylight time", calendar: day, at: "2026-03-09T13:30:00Z", want: true},
+		{name: "closed in daylight time", calendar: day, at: "2026-03-09T20:00:00Z", want: false},
+		{name: "open back in standard time", calendar: day, at: "2026-11-02T14:30:00Z", want: true},
+
+		{name: "overnight before open", calendar: overnight, at: "2026-10-19T21:59:00Z", want: false},
+		{name: "overnight at open", calendar: overnight, at: "2026-10-19T22:00:00Z", want: true},
+		{name: "overnight after midnight", calendar: overnight, at: "2026-10-20T04:00:00Z", want: true},
+		{name: "overnight before close", calendar: overnight, at: "2026-10-20T20:59:00Z", want: true},
+		{name: "overnight at close", calendar: overnight, at: "2026-10-20T21:00:00Z", want: false},
+		{name: "overnight from sunday", calendar: overnight, at: "2026-10-19T02:00:00Z", want: true},
+		{name: "overnight into saturday", calendar: overnight, at: "2026-10-23T20:59:00Z", want: true},
+		{name: "overnight friday", calendar: overnight, at: "2026-10-23T22:30:00Z", want: false},
+		{name: "overnight saturday", calendar: overnight, at: "2026-10-24T15:00:00Z", want: false},
+		{name: "overnight stop after close", calendar: overnight, at: "2026-10-23T21:30:00Z", after: time.Hour, want: true},
+		{name: "overnight start before open", calendar: overnight, at: "2026-10-18T21:30:00Z", before: time.Hour, want: true},
+		{name: "overnight holiday open", calendar: overnight, at: "2026-12-24T23:30:00Z", want: false},
+		{name: "overnight after holiday close", calendar: overnight, at: "2026-12-24T15:00:00Z", want: true},
+		{name: "overnight friday close before spring forward", calendar: overnight, at: "2026-03-06T21:59:00Z", want: true},
+		{name: "overnight sunday before open in daylight time", calendar: overnight, at: "2026-03-08T21:59:00Z", want: false},
+		{name: "overnight sunday open in daylight time", calendar: overnight, at: "2026-03-08T22:00:00Z", want: true},
+		// Saturday 2026-10-31 is not a trading day; the Sunday 2026-11-01 session
+		// opens at 18:00 EST and closes on Monday at 17:00 EST.
+		{name: "overnight across fall back", calendar: overnight, at: "2026-11-01T22:59:00Z", want: false},
+		{name: "overnight fall back open", calendar: overnight, at: "2026-11-01T23:00:00Z", want: true},
+		{name: "overnight fall back close", calendar: overnight, at: "2026-11-02T21:59:00Z", want: true},
+		{name: "overnight fall back closed", calendar: overnight, at: "2026-11-02T22:00:00Z", want: false},
+
+		// The session opened on Saturday 2026-03-07 at 22:00 EST closes on Sunday at 06:00 EDT,
+		// after 7 hours; the one opened on Saturday 2026-10-31 at 22:00 EDT closes at 06:00 EST, after 9.
+		{name: "nightly before open", calendar: nightly, at: "2026-03-08T02:59:00Z", want: false},
+		{name: "nightly open before spring forward", calendar: nightly, at: "2026-03-08T03:00:00Z", want: true},
+		{name: "nightly open after spring forward", calendar: nightly, at: "2026-03-08T09:59:00Z", want: true},
+		{name: "nightly closed at wall clock close after spring forward", calendar: nightly, at: "2026-03-08T10:00:00Z", want: false},
+		{name: "nightly stop after close across spring forward", calendar: nightly, at: "2026-03-08T10:14:00Z", after: 15 * time.Minute, want: true},
+		{name: "nightly before open before fall back", calendar: nightly, at: "2026-11-01T01:59:00Z", want: false},
+		{name: "nightly open before fall back", calendar: nightly, at: "2026-11-01T02:00:00Z", want: true},
+		{name: "nightly open in repeated hour", calendar: nightly, at: "2026-11-01T06:30:00Z", want: true},
+		{name: "nightly open after fall back", calendar: nightly, at: "2026-11-01T10:59:00Z", want: true},
+		{name: "nightly closed at wall clock close after fall back", calendar: nightly, at: "2026-11-01T11:00:00Z", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, tt.calendar.InSession(at, tt.before, tt.after))
+		})
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	valid := calendar.Spec{Timezone: "UTC", Days: []string{"mon"}, Open: "09:00", Close: "17:00"}
+
+	tests := []struct {
+		name   string
+		modify func(spec *calendar.Spec)
+		err    string
+	}{
+		{name: "timezone", modify: func(spec *calendar.Spec) { spec.Timezone = "Mars/Olympus" }, err: `invalid timezone "Mars/Olympus"`},
+		{name: "no days", modify: func(spec *calendar.Spec) { spec.Days = nil }, err: "no trading days"},
+		{name: "day", modify: func(spec *calendar.Spec) { spec.Days = []string{"monday"} }, err: `invalid day "monday"`},
+		{name: "open", modify: func(spec *calendar.Spec) { spec.Open = "9am" }, err: `invalid time "9am"`},
+		{name: "close", modify: func(spec *calendar.Spec) { spec.Close = "24:00" }, err: `invalid time "24:00"`},
+		{name: "holiday", modify: func(spec *calendar.Spec) { spec.Holidays = []string{"2026-02-30"} }, err: `invalid holiday "2026-02-30"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid
+			tt.modify(&spec)
+
+			_, err := calendar.New(spec)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Package cron parses standard five-field cron expressions and matches times against them.
+//
+// The fields are minute, hour, day of month, month and day of week. Each field accepts
+// *, single values, ranges (a-b), lists (a,b) and steps (*/n, a-b/n). Months and days
+// of week also accept three-letter English names (JAN, MON), and 7 is Sunday.
+// As in crontab, when both day of month and day of week are restricted, a time matches
+// if either of them matches. A day field is unrestricted if it starts with *, including
+// steps such as */2, or lists every day, such as 1-31 or 0-6.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record whether the day fields are unrestricted.
+	domAny, dowAny bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Parse parses a five-field cron expression.
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), expr)
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Sunday is both 0 and 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = unrestricted(fields[2], s.dom, domField.min, domField.max)
+	// Sunday is set as 0 above, so every day of week is 0-6.
+	s.dowAny = unrestricted(fields[4], s.dow, dowField.min, 6)
+
+	return &s, nil
+}
+
+// unrestricted reports whether a day field starts with * or its days cover every value
+// from first to last.
+func unrestricted(expr string, bits uint64, first, last int) bool {
+	if strings.HasPrefix(expr, "*") {
+		return true
+	}
+	for v := first; v <= last; v++ {
+		if bits&(1<<uint(v)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether the minute of t matches the schedule, in the location of t.
+func (s *Schedule) Match(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 ||
+		s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	return s.matchDay(t)
+}
+
+// matchDay reports whether the day of t matches the day of month and day of week fields.
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// nextLimit bounds the search of Next, so that schedules that never match, such as
+// 30 February, do not search forever.
+const nextLimit = 5
+
+// Next returns the first minute at or after t that matches the schedule, in the location of t.
+// Fields that do not match are skipped as a whole: a month at a time, then a day, an hour
+// and a minute. It returns the zero time if no minute matches within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	location := t.Location()
+	t = t.Truncate(time.Minute)
+	limit := t.AddDate(nextLimit, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = midnight(time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location), t)
+		case !s.matchDay(t):
+			t = midnight(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location), t)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			// Adding the remaining minutes rather than setting the hour keeps an hour
+			// repeated by a daylight saving change.
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// midnight returns day, the start of a day, moved past from. time.Date resolves a midnight
+// skipped by a daylight saving change to the previous day, before the first minute of the day.
+func midnight(day, from time.Time) time.Time {
+	for !day.After(from) {
+		day = day.Add(time.Hour)
+	}
+	return day
+}
+
+// parse parses a comma-separated list of values, ranges and steps into a bit set.
+func (f field) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("cron: invalid step in %s field %q", f.name, part)
+			}
+			rangeExpr, step = part[:i], n
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("cron: invalid range in %s field %q", f.name, part)
+			}
+		default:
+			value, err := f.value(rangeExpr)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if step == 1 {
+				high = value
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single number or name of the field.
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("cron: invalid %s %q", f.name, s)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,181 @@
+package cron_test
+
+import (
+	"test-task/pkg/cron"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const layout = "2006-01-02 15:04"
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		err  string
+	}{
+		{name: "too few fields", expr: "* * * *", err: "expected 5 fields, got 4"},
+		{name: "too many fields", expr: "* * * * * *", err: "expected 5 fields, got 6"},
+		{name: "minute out of range", expr: "60 * * * *", err: `invalid minute "60"`},
+		{name: "hour out of range", expr: "* 24 * * *", err: `invalid hour "24"`},
+		{name: "day of month zero", expr: "* * 0 * *", err: `invalid day of month "0"`},
+		{name: "month out of range", expr: "* * * 13 *", err: `invalid month "13"`},
+		{name: "day of week out of range", expr: "* * * * 8", err: `invalid day of week "8"`},
+		{name: "unknown name", expr: "* * * FOO *", err: `invalid month "FOO"`},
+		{name: "name in wrong field", expr: "* * * MON *", err: `invalid month "MON"`},
+		{name: "not a number", expr: "a * * * *", err: `invalid minute "a"`},
+		{name: "reversed range", expr: "30-10 * * * *", err: `invalid range in minute field "30-10"`},
+		{name: "range out of bounds", expr: "50-70 * * * *", err: `invalid minute "70"`},
+		{name: "zero step", expr: "*/0 * * * *", err: `invalid step in minute field "*/0"`},
+		{name: "negative step", expr: "*/-1 * * * *", err: `invalid step in minute field "*/-1"`},
+		{name: "missing step", expr: "*/ * * * *", err: `invalid step in minute field "*/"`},
+		{name: "empty list item", expr: "1,,2 * * * *", err: `invalid minute ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cron.Parse(tt.expr)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestSchedule_Match(t *testing.T) {
+	// 2026-10-19 is a Monday.
+	tests := []struct {
+		name string
+		expr string
+		at   map[string]bool
+	}{
+		{name: "any", expr: "* * * * *", at: map[string]bool{"2026-10-19 00:00": true, "2026-10-24 23:59": true}},
+		{name: "value", expr: "30 9 * * *", at: map[string]bool{"2026-10-19 09:30": true, "2026-10-19 09:31": false, "2026-10-19 10:30": false}},
+		{name: "range", expr: "0 9-17 * * *", at: map[string]bool{"2026-10-19 08:00": false, "2026-10-19 09:00": true, "2026-10-19 17:00": true, "2026-10-19 18:00": false}},
+		{name: "step", expr: "*/15 * * * *", at: map[string]bool{"2026-10-19 10:00": true, "2026-10-19 10:45": true, "2026-10-19 10:50": false}},
+		{name: "range with step", expr: "10-40/10 * * * *", at: map[string]bool{"2026-10-19 10:00": false, "2026-10-19 10:10": true, "2026-10-19 10:40": true, "2026-10-19 10:50": false}},
+		{name: "value with step", expr: "50/5 * * * *", at: map[string]bool{"2026-10-19 10:45": false, "2026-10-19 10:50": true, "2026-10-19 10:55": true}},
+		{name: "list", expr: "0 9,12,16 * * *", at: map[string]bool{"2026-10-19 09:00": true, "2026-10-19 12:00": true, "2026-10-19 13:00": false}},
+		{name: "list of ranges and steps", expr: "0-4,*/20 * * * *", at: map[string]bool{"2026-10-19 10:04": true, "2026-10-19 10:05": false, "2026-10-19 10:40": true}},
+		{name: "weekday names", expr: "0 0 * * MON-FRI", at: map[string]bool{"2026-10-19 00:00": true, "2026-10-23 00:00": true, "2026-10-24 00:00": false}},
+		{name: "month names", expr: "0 0 1 jan,jul *", at: map[string]bool{"2026-01-01 00:00": true, "2026-07-01 00:00": true, "2026-10-01 00:00": false}},
+		{name: "sunday as 7", expr: "0 0 * * 7", at: map[string]bool{"2026-10-18 00:00": true, "2026-10-19 00:00": false}},
+		{name: "sunday as 0", expr: "0 0 * * 0", at: map[string]bool{"2026-10-18 00:00": true}},
+		{name: "day of month or day of week", expr: "0 0 1 * MON", at: map[string]bool{"2026-10-01 00:00": true, "2026-10-19 00:00": true, "2026-10-20 00:00": false}},
+		{name: "day of month and any day of week", expr: "0 0 1 * *", at: map[string]bool{"2026-10-01 00:00": true, "2026-10-19 00:00": false}},
+		{name: "day of month step and day of week", expr: "0 9 */2 * 1-5", at: map[string]bool{"2026-10-19 09:00": true, "2026-10-20 09:00": false, "2026-10-25 09:00": false}},
+		{name: "day of month and every day of week", expr: "0 0 1 * 0-6", at: map[string]bool{"2026-10-01 00:00": true, "2026-10-19 00:00": false}},
+		{name: "every day of month and day of week", expr: "0 0 1-31 * MON", at: map[string]bool{"2026-10-19 00:00": true, "2026-10-20 00:00": false}},
+		{name: "day of month and every day of week through 7", expr: "0 0 1 * 1-7", at: map[string]bool{"2026-10-01 00:00": true, "2026-10-18 00:00": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := cron.Parse(tt.expr)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for at, want := range tt.at {
+				tm, err := time.Parse(layout, at)
+				assert.NoError(t, err)
+				assert.Equal(t, want, schedule.Match(tm), at)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	// Midnight of 2026-09-06 is skipped in Santiago, clocks go from 23:59 to 01:00.
+	santiago, err := time.LoadLocation("America/Santiago")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		from     string
+		next     string
+	}{
+		{name: "matching minute", expr: "30 9 * * *", location: time.UTC, from: "2026-10-19 09:30", next: "2026-10-19 09:30"},
+		{name: "seconds are truncated", expr: "* * * * *", location: time.UTC, from: "2026-10-19 09:30", next: "2026-10-19 09:30"},
+		{name: "later minute", expr: "45 * * * *", location: time.UTC, from: "2026-10-19 09:30", next: "2026-10-19 09:45"},
+		{name: "next hour", expr: "15 * * * *", location: time.UTC, from: "2026-10-19 09:30", next: "2026-10-19 10:15"},
+		{name: "next day", expr: "0 9 * * *", location: time.UTC, from: "2026-10-19 09:30", next: "2026-10-20 09:00"},
+		{name: "next weekday", expr: "0 9 * * MON-FRI", location: time.UTC, from: "2026-10-23 10:00", next: "2026-10-26 09:00"},
+		{name: "next odd weekday", expr: "0 9 */2 * 1-5", location: time.UTC, from: "2026-10-19 10:00", next: "2026-10-21 09:00"},
+		{name: "next month", expr: "0 0 1 * *", location: time.UTC, from: "2026-10-19 09:30", next: "2026-11-01 00:00"},
+		{name: "next year", expr: "0 0 1 JAN *", location: time.UTC, from: "2026-10-19 09:30", next: "2027-01-01 00:00"},
+		{name: "leap day", expr: "0 0 29 2 *", location: time.UTC, from: "2026-10-19 09:30", next: "2028-02-29 00:00"},
+		{name: "skipped hour", expr: "30 2 * * *", location: newYork, from: "2026-03-08 00:00", next: "2026-03-09 02:30"},
+		{name: "skipped midnight", expr: "* * 6 9 *", location: santiago, from: "2026-09-05 12:00", next: "2026-09-06 01:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := cron.Parse(tt.expr)
+			if !assert.NoError(t, err) {
+				return
+			}
+			from, err := time.ParseInLocation(layout, tt.from, tt.location)
+			assert.NoError(t, err)
+
+			next := schedule.Next(from.Add(20 * time.Second))
+
+			assert.Equal(t, tt.next, next.Format(layout))
+			assert.Equal(t, tt.location, next.Location())
+		})
+	}
+}
+
+func TestSchedule_Next_RepeatedHour(t *testing.T) {
+	// Clocks go back from 02:00 EDT to 01:00 EST on 2026-11-01, 01:30 occurs twice.
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	schedule, err := cron.Parse("30 1 * * *")
+	assert.NoError(t, err)
+
+	first := schedule.Next(time.Date(2026, 11, 1, 0, 0, 0, 0, newYork))
+	second := schedule.Next(first.Add(time.Minute))
+
+	assert.Equal(t, "2026-11-01 01:30 EDT", first.Format(layout+" MST"))
+	assert.Equal(t, "2026-11-01 01:30 EST", second.Format(layout+" MST"))
+}
+
+func TestSchedule_Next_NeverMatches(t *testing.T) {
+	schedule, err := cron.Parse("0 0 30 2 *")
+	assert.NoError(t, err)
+
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestSchedule_Next_MatchesScan(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	exprs := []string{"*/7 3-5 * * *", "0,30 * * * SAT,SUN", "59 23 31 * *", "15 1,2 1,15 * MON", "0 0-23/5 * MAR,NOV *"}
+	froms := []time.Time{
+		time.Date(2026, 3, 7, 22, 13, 0, 0, newYork),
+		time.Date(2026, 10, 31, 23, 59, 0, 0, newYork),
+	}
+
+	for _, expr := range exprs {
+		schedule, err := cron.Parse(expr)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for _, from := range froms {
+			want := time.Time{}
+			for minute := from; minute.Before(from.AddDate(0, 0, 40)); minute = minute.Add(time.Minute) {
+				if schedule.Match(minute) {
+					want = minute
+					break
+				}
+			}
+
+			assert.True(t, want.Equal(schedule.Next(from)), "%s from %s", expr, from)
+		}
+	}
+}
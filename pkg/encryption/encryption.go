@@ -0,0 +1,90 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// MinKeyLength is the minimum length of an encryption key in bytes.
+const MinKeyLength = 32
+
+// keyInfo binds derived keys to this cipher, so the same configured key used elsewhere yields a different key.
+const keyInfo = "algosync secrets aes-256-gcm"
+
+var (
+	// ErrEmptyKey is returned when the cipher is created without a key.
+	ErrEmptyKey = errors.New("encryption key is empty")
+	// ErrShortKey is returned when the key is shorter than MinKeyLength.
+	ErrShortKey = fmt.Errorf("encryption key must be at least %d bytes", MinKeyLength)
+)
+
+type Cipher interface {
+	Encrypt(plaintext, additionalData []byte) ([]byte, error)
+	Decrypt(ciphertext, additionalData []byte) ([]byte, error)
+}
+
+type aesCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipher creates an AES-256-GCM cipher keyed with a key derived from key with HKDF-SHA256.
+// The key must be at least MinKeyLength bytes of random data.
+// Data encrypted with one key cannot be decrypted after the key is changed.
+func NewAESCipher(key string) (Cipher, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if len(key) < MinKeyLength {
+		return nil, ErrShortKey
+	}
+
+	derived := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(key), nil, []byte(keyInfo)), derived); err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &aesCipher{aead: aead}, nil
+}
+
+// Encrypt encrypts plaintext with a random nonce, which is prepended to the result.
+// The additional data is authenticated but not stored: Decrypt must be given the same data.
+func (c *aesCipher) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts data produced by Encrypt with the same additional data.
+func (c *aesCipher) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, ciphertext[:size], ciphertext[size:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"net/http"
+	"strconv"
+	"test-task/pkg/http/response"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,8 +16,15 @@ import (
 type Middleware interface {
 	CORS() gin.HandlerFunc
 	RPSLimit(rps int) gin.HandlerFunc
+	RequestID() gin.HandlerFunc
 }
 
+// requestIDHeader is the header carrying the ID of a request.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs supplied by callers, since they end up in logs.
+const maxRequestIDLength = 128
+
 type middleware struct {
 	secretKey string
 }
@@ -26,19 +37,21 @@ func NewMiddleware(secretKey string) Middleware {
 //
 // It sets the following headers:
 //   - Access-Control-Allow-Origin: *
-//   - Access-Control-Allow-Methods: GET, POST, PUT, DELETE, OPTIONS
-//   - Access-Control-Allow-Headers: Origin, Content-Type, Authorization
-//   - Access-Control-Expose-Headers: Content-Length
+//   - Access-Control-Allow-Methods: GET, POST, PUT, PATCH, DELETE, OPTIONS
+//   - Access-Control-Allow-Headers: Origin, Content-Type, Authorization, Idempotency-Key, If-Match
+//   - Access-Control-Expose-Headers: Content-Length, ETag, X-Request-ID, Idempotent-Replayed
 //   - Access-Control-Allow-Credentials: true
 //
+// PATCH, If-Match and ETag let browsers make conditional updates of clients.
+//
 // If the incoming request method is OPTIONS, it responds with HTTP status
 // 204 (No Content) and aborts further processing.
 func (m *middleware) CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, Idempotency-Key, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, ETag, X-Request-ID, Idempotent-Replayed")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -64,3 +77,31 @@ func (m *middleware) RPSLimit(rps int) gin.HandlerFunc {
 		prev = now
 	}
 }
+
+// RequestID returns a middleware handler that assigns an ID to every request.
+//
+// It keeps the X-Request-ID header of the request if one is set, and generates a random ID
+// otherwise. The ID is returned in the X-Request-ID response header and stored in the
+// context, so that error responses and logs can refer to it.
+func (m *middleware) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+
+		c.Set(response.RequestIDKey, id)
+		c.Writer.Header().Set(requestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+// newRequestID returns a random 128-bit request ID in hex.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// ErrInvalidIfMatch is returned when the If-Match header does not hold a single revision ETag.
+	ErrInvalidIfMatch = errors.New("If-Match header must hold a single revision ETag")
+	// ErrIfMatchRequired is returned when a request has to carry an If-Match header but has none.
+	ErrIfMatchRequired = errors.New("If-Match header is required")
+)
+
+// RevisionETag returns the ETag of a resource at the given revision.
+func RevisionETag(revision int64) string {
+	return strconv.Quote(strconv.FormatInt(revision, 10))
+}
+
+// IfMatchRevision returns the revision of the If-Match header of the request and whether the header is set.
+//
+// The revision is nil if the header is missing or "*", which matches any revision.
+// Weak ETags are accepted, since revisions identify the state of a resource rather than its encoding.
+func IfMatchRevision(c *gin.Context) (*int64, bool, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return nil, false, nil
+	}
+	if header == "*" {
+		return nil, true, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	value, err := strconv.Unquote(tag)
+	if err != nil || !strings.HasPrefix(tag, `"`) {
+		return nil, true, ErrInvalidIfMatch
+	}
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, true, ErrInvalidIfMatch
+	}
+
+	return &revision, true, nil
+}
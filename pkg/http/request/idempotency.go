@@ -0,0 +1,31 @@
+package request
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of a request.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds idempotency keys, which are stored with the response.
+const maxIdempotencyKeyLength = 255
+
+// ErrInvalidIdempotencyKey is returned when the Idempotency-Key header is not a valid key.
+var ErrInvalidIdempotencyKey = errors.New("Idempotency-Key header must hold at most 255 printable ASCII characters")
+
+// IdempotencyKey returns the Idempotency-Key header of the request, empty if the header is missing.
+func IdempotencyKey(c *gin.Context) (string, error) {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if len(key) > maxIdempotencyKeyLength {
+		return "", ErrInvalidIdempotencyKey
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return "", ErrInvalidIdempotencyKey
+		}
+	}
+
+	return key, nil
+}
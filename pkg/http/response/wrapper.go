@@ -1,22 +1,63 @@
 package response
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"test-task/internal/domain"
 	"test-task/internal/models"
+	"test-task/pkg/util/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// RequestIDKey is the context key under which the ID of a request is stored.
+const RequestIDKey = "request_id"
+
+// problemContentType is the media type of problem details responses.
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix prefixes error codes to form the problem type URI.
+const problemTypePrefix = "urn:algosync:error:"
+
+// Codes of request errors that are detected before a service is called.
+var (
+	// codeValidationFailed is the code of request fields that violate their binding rules,
+	// the same as the code of domain validation errors listing invalid fields.
+	codeValidationFailed = domain.Validation.Code()
+	// codeMalformedRequest is the code of request bodies and parameters that cannot be
+	// decoded into the request type, such as invalid JSON or unknown fields.
+	codeMalformedRequest = "malformed_request"
 )
 
 type Wrapper interface {
 	Write(code int, message string)
 	Error(code int, err error)
+	Bind(err error)
+	Fail(err error)
+}
+
+// statuses maps the kinds of domain errors to HTTP status codes.
+var statuses = map[domain.Kind]int{
+	domain.NotFound:           http.StatusNotFound,
+	domain.Conflict:           http.StatusConflict,
+	domain.Validation:         http.StatusUnprocessableEntity,
+	domain.PreconditionFailed: http.StatusPreconditionFailed,
+	domain.Unavailable:        http.StatusServiceUnavailable,
 }
 
 type wrapper struct {
-	c *gin.Context
+	c   *gin.Context
+	log logger.Logger
 }
 
 func New(c *gin.Context) Wrapper {
-	return &wrapper{c: c}
+	return &wrapper{c: c, log: logger.GetLogger()}
 }
 
 // Write writes a JSON response with the provided HTTP status code and message.
@@ -26,10 +67,174 @@ func (w *wrapper) Write(code int, message string) {
 	w.c.JSON(code, models.Response{Code: code, Message: message})
 }
 
-// Error writes a JSON response with the provided HTTP status code and error message.
+// Error writes a problem details response with the provided HTTP status code.
 //
-// It serializes the response into JSON format using the provided code and the
-// error message extracted from the error object.
+// The detail is the error message, unless the status is a server error and the error
+// is not a domain error; such errors are logged with the request ID instead, since their
+// messages may contain internal details such as SQL driver errors. Invalid fields of
+// validation and JSON binding errors are listed in the errors member. Errors without a
+// domain code get the code of the request error they are, or one derived from the status.
 func (w *wrapper) Error(code int, err error) {
-	w.c.JSON(code, models.Response{Code: code, Message: err.Error()})
+	requestID := w.c.GetString(RequestIDKey)
+
+	problem := models.Problem{
+		Title:     http.StatusText(code),
+		Status:    code,
+		Code:      domain.CodeOf(err),
+		RequestID: requestID,
+		Errors:    problemFields(err),
+	}
+	if problem.Code == "" {
+		problem.Code = requestCode(err)
+	}
+	if problem.Code == "" {
+		problem.Code = statusCode(code)
+	}
+	problem.Type = problemTypePrefix + problem.Code
+
+	switch {
+	case code >= http.StatusInternalServerError && !domain.IsDomain(err):
+		w.log.Errorf("request %s: %s %s: %v", requestID, w.c.Request.Method, w.c.Request.URL.Path, err)
+	case len(problem.Errors) > 0:
+		problem.Detail = "invalid fields: " + fieldList(problem.Errors)
+	default:
+		problem.Detail = err.Error()
+	}
+
+	w.c.Header("Content-Type", problemContentType)
+	w.c.JSON(code, problem)
+}
+
+// Bind writes a problem details response for an error of binding a request.
+//
+// Fields that violate their binding rules are answered with 422 Unprocessable Entity,
+// like the validation errors of services, and requests that cannot be decoded with
+// 400 Bad Request.
+func (w *wrapper) Bind(err error) {
+	if requestCode(err) == codeValidationFailed {
+		w.Error(http.StatusUnprocessableEntity, err)
+		return
+	}
+	w.Error(http.StatusBadRequest, err)
+}
+
+// Fail writes a problem details response for an error returned by a service.
+//
+// The HTTP status code is derived from the kind of the error; errors without a kind
+// are answered with 500 Internal Server Error.
+func (w *wrapper) Fail(err error) {
+	w.Error(Status(err), err)
+}
+
+// Status returns the HTTP status code of an error by its domain kind.
+func Status(err error) int {
+	kind, ok := domain.KindOf(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	return statuses[kind]
+}
+
+// statusCode returns the error code of errors without a code of their own,
+// derived from the HTTP status, for example "bad_request".
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// requestCode returns the code of validation and binding errors of a request,
+// or an empty string for other errors.
+func requestCode(err error) string {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return codeValidationFailed
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		numErr    *strconv.NumError
+	)
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		return codeMalformedRequest
+	}
+	return ""
+}
+
+// problemFields lists the invalid fields of domain validation errors, request
+// validation errors, unknown JSON fields and JSON type errors.
+func problemFields(err error) []models.ProblemField {
+	var fieldErrs domain.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		fields := make([]models.ProblemField, len(fieldErrs))
+		for i, field := range fieldErrs {
+			fields[i] = models.ProblemField{Field: field.Field, Message: field.Message}
+		}
+		return fields
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]models.ProblemField, len(validationErrs))
+		for i, field := range validationErrs {
+			fields[i] = models.ProblemField{Field: fieldPath(field), Message: ruleMessage(field)}
+		}
+		return fields
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix); ok {
+		return []models.ProblemField{{Field: strings.Trim(field, `"`), Message: "is not allowed"}}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []models.ProblemField{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}}
+	}
+
+	return nil
+}
+
+// fieldList joins invalid fields and their messages for the detail of a problem.
+func fieldList(fields []models.ProblemField) string {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// unknownFieldPrefix starts the errors of decoding JSON objects with fields the target does not have.
+const unknownFieldPrefix = "json: unknown field "
+
+// fieldPath returns the path of an invalid field without the name of the request type,
+// for example "algorithms[1]".
+func fieldPath(field validator.FieldError) string {
+	_, path, found := strings.Cut(field.Namespace(), ".")
+	if !found {
+		return field.Field()
+	}
+	return path
+}
+
+// ruleMessage describes the binding rule an invalid field violates.
+func ruleMessage(field validator.FieldError) string {
+	switch field.Tag() {
+	case "required":
+		return "is required"
+	case "notblank":
+		return "must not be blank"
+	case "image_ref":
+		return "must be a container image reference"
+	case "quantity":
+		return "must be a resource quantity such as 500m or 1Gi"
+	case "min":
+		return "must be at least " + field.Param()
+	case "max":
+		return "must be at most " + field.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(field.Param(), " ", ", ")
+	}
+	return fmt.Sprintf("failed on the %q rule", field.Tag())
 }
@@ -0,0 +1,312 @@
+// Package jsonschema validates JSON documents against a subset of JSON Schema.
+//
+// Supported keywords: type, enum, properties, required, additionalProperties (boolean),
+// items, minimum, maximum, exclusiveMinimum, exclusiveMaximum (numbers), minLength,
+// maxLength, pattern, minItems and maxItems. Other keywords, such as title,
+// description and default, are accepted and ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled JSON schema.
+type Schema struct {
+	types                []string
+	enum                 []interface{}
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *bool
+	items                *Schema
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	minItems             *int
+	maxItems             *int
+
+	raw json.RawMessage
+}
+
+type schemaJSON struct {
+	Type                 json.RawMessage            `json:"type"`
+	Enum                 []interface{}              `json:"enum"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties *bool                      `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	ExclusiveMinimum     *float64                   `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64                   `json:"exclusiveMaximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Pattern              string                     `json:"pattern"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+}
+
+var knownTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// Compile parses a JSON schema document.
+func Compile(data []byte) (*Schema, error) {
+	s, err := compile(data, "")
+	if err != nil {
+		return nil, err
+	}
+	s.raw = append(json.RawMessage(nil), data...)
+	return s, nil
+}
+
+// CompileFile parses the JSON schema document stored in the file at path.
+func CompileFile(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	s, err := Compile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+func compile(data []byte, path string) (*Schema, error) {
+	var sj schemaJSON
+	if err := json.Unmarshal(data, &sj); err != nil {
+		return nil, fmt.Errorf("invalid schema at %q: %w", pathOrRoot(path), err)
+	}
+
+	s := &Schema{
+		enum:                 sj.Enum,
+		required:             sj.Required,
+		additionalProperties: sj.AdditionalProperties,
+		minimum:              sj.Minimum,
+		maximum:              sj.Maximum,
+		exclusiveMinimum:     sj.ExclusiveMinimum,
+		exclusiveMaximum:     sj.ExclusiveMaximum,
+		minLength:            sj.MinLength,
+		maxLength:            sj.MaxLength,
+		minItems:             sj.MinItems,
+		maxItems:             sj.MaxItems,
+	}
+
+	if len(sj.Type) > 0 {
+		var single string
+		if err := json.Unmarshal(sj.Type, &single); err == nil {
+			s.types = []string{single}
+		} else if err := json.Unmarshal(sj.Type, &s.types); err != nil {
+			return nil, fmt.Errorf("invalid type at %q", pathOrRoot(path))
+		}
+		for _, t := range s.types {
+			if !knownTypes[t] {
+				return nil, fmt.Errorf("unknown type %q at %q", t, pathOrRoot(path))
+			}
+		}
+	}
+
+	if sj.Pattern != "" {
+		pattern, err := regexp.Compile(sj.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern at %q: %w", pathOrRoot(path), err)
+		}
+		s.pattern = pattern
+	}
+
+	if len(sj.Properties) > 0 {
+		s.properties = make(map[string]*Schema, len(sj.Properties))
+		for name, raw := range sj.Properties {
+			property, err := compile(raw, path+"/"+name)
+			if err != nil {
+				return nil, err
+			}
+			s.properties[name] = property
+		}
+	}
+
+	if len(sj.Items) > 0 {
+		items, err := compile(sj.Items, path+"/items")
+		if err != nil {
+			return nil, err
+		}
+		s.items = items
+	}
+
+	return s, nil
+}
+
+// MarshalJSON returns the source document of the schema.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.raw == nil {
+		return []byte("{}"), nil
+	}
+	return s.raw, nil
+}
+
+// ValidationError lists every violation found in a document.
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Violations, "; ")
+}
+
+// Validate checks the JSON document against the schema.
+// It returns a *ValidationError listing all violations if the document does not conform.
+func (s *Schema) Validate(document []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(document, &value); err != nil {
+		return &ValidationError{Violations: []string{fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+
+	var violations []string
+	s.validate(value, "", &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *Schema) validate(value interface{}, path string, violations *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*violations = append(*violations, fmt.Sprintf("%s: %s", pathOrRoot(path), fmt.Sprintf(format, args...)))
+	}
+
+	if len(s.types) > 0 && !s.matchesType(value) {
+		fail("must be of type %s", strings.Join(s.types, " or "))
+		return
+	}
+
+	if len(s.enum) > 0 {
+		found := false
+		for _, e := range s.enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", s.enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			fail("must be >= %v", *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			fail("must be <= %v", *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+			fail("must be > %v", *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+			fail("must be < %v", *s.exclusiveMaximum)
+		}
+	case string:
+		length := len([]rune(v))
+		if s.minLength != nil && length < *s.minLength {
+			fail("must be at least %d characters long", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("must be at most %d characters long", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match pattern %q", s.pattern.String())
+		}
+	case []interface{}:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("must have at most %d items", *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(item, fmt.Sprintf("%s/%d", path, i), violations)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, ok := s.properties[name]
+			if !ok {
+				if s.additionalProperties != nil && !*s.additionalProperties {
+					fail("unknown property %q", name)
+				}
+				continue
+			}
+			property.validate(v[name], path+"/"+name, violations)
+		}
+	}
+}
+
+func (s *Schema) matchesType(value interface{}) bool {
+	for _, t := range s.types {
+		switch t {
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if v, ok := value.(float64); ok && v == math.Trunc(v) {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
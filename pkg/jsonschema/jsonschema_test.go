@@ -0,0 +1,147 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"test-task/pkg/jsonschema"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     string
+		document   string
+		violations []string
+	}{
+		{name: "type string", schema: `{"type": "string"}`, document: `"vwap"`},
+		{name: "type string mismatch", schema: `{"type": "string"}`, document: `1`, violations: []string{`/: must be of type string`}},
+		{name: "type integer", schema: `{"type": "integer"}`, document: `5`},
+		{name: "type integer fraction", schema: `{"type": "integer"}`, document: `5.5`, violations: []string{`/: must be of type integer`}},
+		{name: "type number", schema: `{"type": "number"}`, document: `5.5`},
+		{name: "type boolean", schema: `{"type": "boolean"}`, document: `"true"`, violations: []string{`/: must be of type boolean`}},
+		{name: "type null", schema: `{"type": "null"}`, document: `null`},
+		{name: "type array", schema: `{"type": "array"}`, document: `{}`, violations: []string{`/: must be of type array`}},
+		{name: "type list", schema: `{"type": ["string", "null"]}`, document: `null`},
+		{name: "type list mismatch", schema: `{"type": ["string", "null"]}`, document: `false`, violations: []string{`/: must be of type string or null`}},
+		{name: "invalid JSON", schema: `{}`, document: `{`, violations: []string{`invalid JSON: unexpected end of JSON input`}},
+
+		{name: "required present", schema: `{"type": "object", "required": ["size"]}`, document: `{"size": 1}`},
+		{name: "required missing", schema: `{"type": "object", "required": ["size", "side"]}`, document: `{}`, violations: []string{
+			`/: missing required property "size"`,
+			`/: missing required property "side"`,
+		}},
+
+		{name: "enum match", schema: `{"enum": ["buy", "sell"]}`, document: `"sell"`},
+		{name: "enum mismatch", schema: `{"enum": ["buy", "sell"]}`, document: `"hold"`, violations: []string{`/: must be one of [buy sell]`}},
+		{name: "enum number", schema: `{"enum": [1, 2]}`, document: `2`},
+
+		{name: "minimum boundary", schema: `{"minimum": 1}`, document: `1`},
+		{name: "below minimum", schema: `{"minimum": 1}`, document: `0.5`, violations: []string{`/: must be >= 1`}},
+		{name: "maximum boundary", schema: `{"maximum": 100}`, document: `100`},
+		{name: "above maximum", schema: `{"maximum": 100}`, document: `101`, violations: []string{`/: must be <= 100`}},
+		{name: "exclusive minimum boundary", schema: `{"exclusiveMinimum": 0}`, document: `0`, violations: []string{`/: must be > 0`}},
+		{name: "exclusive maximum boundary", schema: `{"exclusiveMaximum": 1}`, document: `1`, violations: []string{`/: must be < 1`}},
+		{name: "bounds ignore strings", schema: `{"minimum": 1}`, document: `"0"`},
+		{name: "min length", schema: `{"minLength": 2}`, document: `"é"`, violations: []string{`/: must be at least 2 characters long`}},
+		{name: "max length counts runes", schema: `{"maxLength": 2}`, document: `"éé"`},
+		{name: "pattern", schema: `{"pattern": "^[A-Z]+$"}`, document: `"btc"`, violations: []string{`/: must match pattern "^[A-Z]+$"`}},
+		{name: "min items", schema: `{"minItems": 1}`, document: `[]`, violations: []string{`/: must have at least 1 items`}},
+		{name: "max items", schema: `{"maxItems": 1}`, document: `[1, 2]`, violations: []string{`/: must have at most 1 items`}},
+		{name: "items", schema: `{"items": {"type": "integer"}}`, document: `[1, "2", 3.5]`, violations: []string{
+			`/1: must be of type integer`,
+			`/2: must be of type integer`,
+		}},
+
+		{
+			name:     "nested object",
+			schema:   `{"type": "object", "properties": {"limits": {"type": "object", "required": ["max"], "properties": {"max": {"type": "number", "maximum": 10}}}}}`,
+			document: `{"limits": {"max": 5}}`,
+		},
+		{
+			name:     "nested object violations",
+			schema:   `{"type": "object", "properties": {"limits": {"type": "object", "required": ["max", "min"], "properties": {"max": {"type": "number", "maximum": 10}}}}}`,
+			document: `{"limits": {"max": 50}}`,
+			violations: []string{
+				`/limits: missing required property "min"`,
+				`/limits/max: must be <= 10`,
+			},
+		},
+		{
+			name:       "nested type mismatch stops descent",
+			schema:     `{"properties": {"limits": {"type": "object", "required": ["max"]}}}`,
+			document:   `{"limits": 1}`,
+			violations: []string{`/limits: must be of type object`},
+		},
+
+		{name: "additional properties allowed by default", schema: `{"properties": {"size": {}}}`, document: `{"size": 1, "side": "buy"}`},
+		{name: "additional properties allowed", schema: `{"properties": {"size": {}}, "additionalProperties": true}`, document: `{"side": "buy"}`},
+		{name: "additional properties rejected", schema: `{"properties": {"size": {}}, "additionalProperties": false}`, document: `{"size": 1, "b": 2, "a": 1}`, violations: []string{
+			`/: unknown property "a"`,
+			`/: unknown property "b"`,
+		}},
+		{
+			name:       "nested additional properties rejected",
+			schema:     `{"properties": {"limits": {"properties": {"max": {}}, "additionalProperties": false}}}`,
+			document:   `{"limits": {"max": 1, "min": 0}}`,
+			violations: []string{`/limits: unknown property "min"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := jsonschema.Compile([]byte(tt.schema))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = schema.Validate([]byte(tt.document))
+
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *jsonschema.ValidationError
+			if assert.True(t, errors.As(err, &validationErr), "expected a validation error, got %v", err) {
+				assert.Equal(t, tt.violations, validationErr.Violations)
+			}
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{name: "not JSON", schema: `{`, err: `invalid schema at "/"`},
+		{name: "unknown type", schema: `{"type": "decimal"}`, err: `unknown type "decimal" at "/"`},
+		{name: "invalid type", schema: `{"type": 1}`, err: `invalid type at "/"`},
+		{name: "invalid pattern", schema: `{"pattern": "("}`, err: `invalid pattern at "/"`},
+		{name: "nested unknown type", schema: `{"properties": {"limits": {"properties": {"max": {"type": "float"}}}}}`, err: `unknown type "float" at "/limits/max"`},
+		{name: "items unknown type", schema: `{"items": {"type": "float"}}`, err: `unknown type "float" at "/items"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jsonschema.Compile([]byte(tt.schema))
+
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	source := `{"type": "object", "required": ["size"]}`
+	schema, err := jsonschema.Compile([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	data, err := schema.MarshalJSON()
+
+	assert.NoError(t, err)
+	assert.Equal(t, source, string(data))
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"test-task/pkg/util/logger"
+	"time"
+)
+
+// Event types emitted by the service.
+const (
+	EventAlgorithmCrashLoop = "algorithm.crash_loop"
+)
+
+// Event represents a notification about something operators should know about.
+type Event struct {
+	Type      string    `json:"type"`
+	ClientID  int64     `json:"client_id"`
+	Algorithm string    `json:"algorithm,omitempty"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
+
+type Notifier interface {
+	Notify(event Event) error
+}
+
+type logNotifier struct {
+	log logger.Logger
+}
+
+// NewLogNotifier creates a notifier that writes events to the application log.
+func NewLogNotifier() Notifier {
+	return &logNotifier{log: logger.GetLogger()}
+}
+
+// Notify logs the event as a warning.
+func (n *logNotifier) Notify(event Event) error {
+	n.log.Warnf("[notify] %s client=%d algorithm=%s: %s", event.Type, event.ClientID, event.Algorithm, event.Message)
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+	log    logger.Logger
+}
+
+// NewWebhookNotifier creates a notifier that posts events as JSON to the given URL.
+// Events are also written to the application log.
+func NewWebhookNotifier(url string) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		log:    logger.GetLogger(),
+	}
+}
+
+// Notify logs the event and sends it to the webhook.
+// It returns an error if the request fails or the webhook responds with a non-2xx status.
+func (n *webhookNotifier) Notify(event Event) error {
+	n.log.Warnf("[notify] %s client=%d algorithm=%s: %s", event.Type, event.ClientID, event.Algorithm, event.Message)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
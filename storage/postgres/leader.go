@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// Leader elects a single leader among service instances sharing a database with a
+// session-level PostgreSQL advisory lock. The lock is held on a dedicated connection,
+// so it is released when the leader stops or loses its connection and another
+// instance takes over on its next attempt.
+type Leader struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewLeader creates a leader election for the named role. Instances electing
+// a leader for the same name compete for the same lock.
+func NewLeader(db *sql.DB, name string) *Leader {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return &Leader{db: db, key: int64(h.Sum64())}
+}
+
+// IsLeader reports whether this instance is the leader, trying to become the leader
+// if it is not. A leader whose lock connection broke is no longer the leader.
+func (l *Leader) IsLeader(ctx context.Context) (bool, error) {
+	const op = "storage.postgres.Leader.IsLeader()"
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Resign releases the leadership if this instance holds it.
+func (l *Leader) Resign() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+
+	if _, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		log.Errorf("Error releasing leader lock: %v", err)
+	}
+	l.conn.Close()
+	l.conn = nil
+}